@@ -0,0 +1,197 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// CreateAPIKeyHandler is the handler responsible for creating game api keys
+func CreateAPIKeyHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "CreateAPIKey")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "CreateAPIKeyHandler"),
+			zap.String("operation", "createAPIKey"),
+			zap.String("gameID", gameID),
+		)
+
+		var payload APIKeyPayload
+		err := WithSegment("payload", c, func() error {
+			if err := LoadJSONPayload(&payload, c, l); err != nil {
+				log.E(l, "Failed to parse json payload.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		_, err = app.GetGame(c.StdContext(), gameID)
+		if err != nil {
+			log.W(l, "Could not find game.")
+			return FailWith(http.StatusNotFound, err.Error(), c)
+		}
+
+		var apiKey *models.APIKey
+		var key string
+		err = WithSegment("api-key-create", c, func() error {
+			log.D(l, "Creating api key...")
+			apiKey, key, err = models.CreateAPIKey(app.Db(c.StdContext()), gameID, payload.Name, payload.Scope)
+			if err != nil {
+				log.E(l, "Failed to create the api key.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Created api key successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.String("apiKeyPublicID", apiKey.PublicID),
+				zap.String("scope", apiKey.Scope),
+				zap.Duration("duration", time.Now().Sub(start)),
+			)
+		})
+		result := apiKey.Serialize()
+		result["key"] = key
+		return SucceedWith(result, c)
+	}
+}
+
+// ListAPIKeysHandler is the handler responsible for listing the game api keys, without the keys
+func ListAPIKeysHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListAPIKeys")
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "ListAPIKeysHandler"),
+			zap.String("operation", "listAPIKeys"),
+			zap.String("gameID", gameID),
+		)
+
+		var apiKeys []*models.APIKey
+		err := WithSegment("api-keys-list", c, func() error {
+			var err error
+			apiKeys, err = models.GetAPIKeysByGameID(app.Db(c.StdContext()), gameID)
+			if err != nil {
+				log.E(l, "Failed to list api keys.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+			}
+			return err
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		serialized := make([]map[string]interface{}, len(apiKeys))
+		for i, apiKey := range apiKeys {
+			serialized[i] = apiKey.Serialize()
+		}
+		return SucceedWith(map[string]interface{}{
+			"apiKeys": serialized,
+		}, c)
+	}
+}
+
+// RotateAPIKeyHandler is the handler responsible for replacing the key of a game api key
+func RotateAPIKeyHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RotateAPIKey")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		l := app.Logger.With(
+			zap.String("source", "RotateAPIKeyHandler"),
+			zap.String("operation", "rotateAPIKey"),
+			zap.String("gameID", gameID),
+			zap.String("apiKeyPublicID", publicID),
+		)
+
+		var apiKey *models.APIKey
+		var key string
+		err := WithSegment("api-key-rotate", c, func() error {
+			var err error
+			log.D(l, "Rotating api key...")
+			apiKey, key, err = models.RotateAPIKey(app.Db(c.StdContext()), gameID, publicID)
+			if err != nil {
+				log.E(l, "Failed to rotate the api key.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+			}
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Rotated api key successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		result := apiKey.Serialize()
+		result["key"] = key
+		return SucceedWith(result, c)
+	}
+}
+
+// RevokeAPIKeyHandler is the handler responsible for revoking a game api key
+func RevokeAPIKeyHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RevokeAPIKey")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		l := app.Logger.With(
+			zap.String("source", "RevokeAPIKeyHandler"),
+			zap.String("operation", "revokeAPIKey"),
+			zap.String("gameID", gameID),
+			zap.String("apiKeyPublicID", publicID),
+		)
+
+		var apiKey *models.APIKey
+		err := WithSegment("api-key-revoke", c, func() error {
+			var err error
+			log.D(l, "Revoking api key...")
+			apiKey, err = models.RevokeAPIKey(app.Db(c.StdContext()), gameID, publicID)
+			if err != nil {
+				log.E(l, "Failed to revoke the api key.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+			}
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Revoked api key successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(apiKey.Serialize(), c)
+	}
+}
@@ -0,0 +1,230 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("API Key API Handler", func() {
+	var db models.DB
+	var a *api.App
+	var game *models.Game
+
+	BeforeEach(func() {
+		a = GetDefaultTestApp()
+		db = a.Db(nil)
+
+		game = models.GameFactory.MustCreate().(*models.Game)
+		err := db.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	parse := func(body string) map[string]interface{} {
+		var result map[string]interface{}
+		err := json.Unmarshal([]byte(body), &result)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	Describe("Create API Key Handler", func() {
+		It("Should create an api key and return its key once", func() {
+			status, body := PostJSON(a, fmt.Sprintf("/games/%s/api-keys", game.PublicID), map[string]interface{}{
+				"name":  "backend",
+				"scope": models.APIKeyScopePlayerWrite,
+			})
+
+			Expect(status).To(Equal(http.StatusOK))
+			result := parse(body)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["name"]).To(Equal("backend"))
+			Expect(result["scope"]).To(Equal(models.APIKeyScopePlayerWrite))
+			key := result["key"].(string)
+			Expect(key).To(HavePrefix(result["keyPrefix"].(string)))
+
+			apiKey, err := models.GetAPIKeyByKey(db, key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apiKey.PublicID).To(Equal(result["publicID"]))
+			Expect(apiKey.KeyHash).NotTo(ContainSubstring(key))
+		})
+
+		It("Should not create an api key with an invalid scope", func() {
+			status, body := PostJSON(a, fmt.Sprintf("/games/%s/api-keys", game.PublicID), map[string]interface{}{
+				"scope": "owner",
+			})
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(parse(body)["reason"]).To(ContainSubstring("scope should be"))
+		})
+
+		It("Should fail with 404 if game does not exist", func() {
+			status, _ := PostJSON(a, fmt.Sprintf("/games/%s/api-keys", uuid.NewV4().String()), map[string]interface{}{
+				"scope": models.APIKeyScopeAdmin,
+			})
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("List, Rotate and Revoke API Key Handlers", func() {
+		var apiKey *models.APIKey
+		var key string
+
+		BeforeEach(func() {
+			var err error
+			apiKey, key, err = models.CreateAPIKey(db, game.PublicID, "backend", models.APIKeyScopeReadOnly)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should list the api keys without their keys", func() {
+			status, body := Get(a, fmt.Sprintf("/games/%s/api-keys", game.PublicID))
+
+			Expect(status).To(Equal(http.StatusOK))
+			apiKeys := parse(body)["apiKeys"].([]interface{})
+			Expect(apiKeys).To(HaveLen(1))
+			Expect(apiKeys[0].(map[string]interface{})["publicID"]).To(Equal(apiKey.PublicID))
+			Expect(apiKeys[0]).NotTo(HaveKey("key"))
+			Expect(body).NotTo(ContainSubstring(apiKey.KeyHash))
+		})
+
+		It("Should replace the key when rotating", func() {
+			status, body := Post(a, fmt.Sprintf("/games/%s/api-keys/%s/rotate", game.PublicID, apiKey.PublicID), "")
+
+			Expect(status).To(Equal(http.StatusOK))
+			newKey := parse(body)["key"].(string)
+			Expect(newKey).NotTo(Equal(key))
+
+			_, err := models.GetAPIKeyByKey(db, key)
+			Expect(err).To(HaveOccurred())
+			rotated, err := models.GetAPIKeyByKey(db, newKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated.PublicID).To(Equal(apiKey.PublicID))
+		})
+
+		It("Should revoke the api key", func() {
+			status, body := Delete(a, fmt.Sprintf("/games/%s/api-keys/%s", game.PublicID, apiKey.PublicID))
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(parse(body)["revokedAt"]).To(BeNumerically(">", 0))
+			_, err := models.GetAPIKeyByKey(db, key)
+			Expect(err).To(HaveOccurred())
+
+			status, _ = Post(a, fmt.Sprintf("/games/%s/api-keys/%s/rotate", game.PublicID, apiKey.PublicID), "")
+			Expect(status).To(Equal(http.StatusConflict))
+		})
+
+		It("Should fail with 404 if the api key does not exist", func() {
+			status, _ := Delete(a, fmt.Sprintf("/games/%s/api-keys/%s", game.PublicID, uuid.NewV4().String()))
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Auth Middleware", func() {
+		var authApp *api.App
+		superAdmin := map[string]string{
+			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:secret")),
+		}
+
+		BeforeEach(func() {
+			authApp = GetTestAppWithAPIKeys("admin", "secret")
+		})
+
+		withKey := func(scope string) map[string]string {
+			_, key, err := models.CreateAPIKey(db, game.PublicID, "", scope)
+			Expect(err).NotTo(HaveOccurred())
+			return map[string]string{api.APIKeyHeader: key}
+		}
+
+		It("Should require credentials", func() {
+			status, _ := DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s", game.PublicID), "", nil)
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			status, _ = DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s", game.PublicID), "", map[string]string{
+				api.APIKeyHeader: "khan_invalid",
+			})
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			status, _ = DoRequestWithHeaders(authApp, "GET", "/healthcheck", "", nil)
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("Should allow the super admin to use all routes", func() {
+			status, _ := DoRequestWithHeaders(authApp, "GET", "/games", "", superAdmin)
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s", game.PublicID), "", superAdmin)
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("Should not allow api keys to create games", func() {
+			payload, _ := json.Marshal(getGamePayload("", ""))
+			status, _ := DoRequestWithHeaders(authApp, "POST", "/games", string(payload), withKey(models.APIKeyScopeAdmin))
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("Should limit api keys to their game", func() {
+			other := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(other)
+			Expect(err).NotTo(HaveOccurred())
+
+			headers := withKey(models.APIKeyScopeAdmin)
+			status, _ := DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s", game.PublicID), "", headers)
+			Expect(status).To(Equal(http.StatusOK))
+			status, _ = DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s", other.PublicID), "", headers)
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("Should limit api keys to their scope", func() {
+			player := map[string]interface{}{
+				"publicID": uuid.NewV4().String(),
+				"name":     "player",
+				"metadata": map[string]interface{}{},
+			}
+			body, _ := json.Marshal(player)
+			route := fmt.Sprintf("/games/%s/players", game.PublicID)
+
+			readOnly := withKey(models.APIKeyScopeReadOnly)
+			status, _ := DoRequestWithHeaders(authApp, "POST", route, string(body), readOnly)
+			Expect(status).To(Equal(http.StatusForbidden))
+			status, _ = DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s/clans", game.PublicID), "", readOnly)
+			Expect(status).To(Equal(http.StatusOK))
+
+			playerWrite := withKey(models.APIKeyScopePlayerWrite)
+			status, _ = DoRequestWithHeaders(authApp, "POST", route, string(body), playerWrite)
+			Expect(status).To(Equal(http.StatusOK))
+			status, _ = DoRequestWithHeaders(authApp, "GET", fmt.Sprintf("/games/%s/hooks", game.PublicID), "", playerWrite)
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Required API Key Scope", func() {
+		It("Should return the scope required by each route", func() {
+			Expect(api.RequiredAPIKeyScope("POST", "/games")).To(Equal(""))
+			Expect(api.RequiredAPIKeyScope("GET", "/games")).To(Equal(""))
+			Expect(api.RequiredAPIKeyScope("GET", "/status")).To(Equal(""))
+			Expect(api.RequiredAPIKeyScope("GET", "/games/:gameID")).To(Equal(models.APIKeyScopeReadOnly))
+			Expect(api.RequiredAPIKeyScope("PUT", "/games/:gameID")).To(Equal(models.APIKeyScopeAdmin))
+			Expect(api.RequiredAPIKeyScope("GET", "/games/:gameID/clans/:clanPublicID")).To(Equal(models.APIKeyScopeReadOnly))
+			Expect(api.RequiredAPIKeyScope("GET", "/games/:gameID/clans-summary")).To(Equal(models.APIKeyScopeReadOnly))
+			Expect(api.RequiredAPIKeyScope("POST", "/games/:gameID/clans/:clanPublicID/memberships/application")).To(Equal(models.APIKeyScopePlayerWrite))
+			Expect(api.RequiredAPIKeyScope("PUT", "/games/:gameID/players/:playerPublicID")).To(Equal(models.APIKeyScopePlayerWrite))
+			Expect(api.RequiredAPIKeyScope("DELETE", "/games/:gameID/clans/:clanPublicID")).To(Equal(models.APIKeyScopePlayerWrite))
+			Expect(api.RequiredAPIKeyScope("POST", "/games/:gameID/clans/:clanPublicID/admin-disband")).To(Equal(models.APIKeyScopeAdmin))
+			Expect(api.RequiredAPIKeyScope("GET", "/games/:gameID/hooks")).To(Equal(models.APIKeyScopeAdmin))
+			Expect(api.RequiredAPIKeyScope("POST", "/games/:gameID/api-keys")).To(Equal(models.APIKeyScopeAdmin))
+		})
+	})
+})
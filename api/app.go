@@ -16,6 +16,7 @@ import (
 	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -65,6 +66,7 @@ type App struct {
 	Dispatcher     *Dispatcher
 	ESWorker       *models.ESWorker
 	MongoWorker    *models.MongoWorker
+	TopClansWorker *models.TopClansWorker
 	Logger         zap.Logger
 	ESClient       *es.Client
 	MongoDB        interfaces.MongoDB
@@ -75,6 +77,7 @@ type App struct {
 
 	getGameCache        *gocache.Cache
 	clansSummariesCache *caches.ClansSummaries
+	hooksCache          *caches.Hooks
 	db                  gorp.Database
 }
 
@@ -112,6 +115,7 @@ func (app *App) Configure() {
 	app.initDispatcher()
 	app.initESWorker()
 	app.initMongoWorker()
+	app.initTopClansWorker()
 	app.configureGoWorkers()
 	app.configureCaches()
 }
@@ -119,6 +123,7 @@ func (app *App) Configure() {
 func (app *App) configureCaches() {
 	app.configureGetGameCache()
 	app.configureClansSummariesCache()
+	app.configureHooksCache()
 }
 
 func (app *App) configureGetGameCache() {
@@ -278,8 +283,52 @@ func (app *App) setConfigurationDefaults() {
 	app.Config.SetDefault("postgres.port", 5432)
 	app.Config.SetDefault("postgres.sslMode", "disable")
 	app.Config.SetDefault("webhooks.timeout", 500)
+	app.Config.SetDefault("webhooks.preHooksTimeout", 200)
 	app.Config.SetDefault("webhooks.maxIdleConnsPerHost", http.DefaultMaxIdleConnsPerHost)
 	app.Config.SetDefault("webhooks.maxIdleConns", 100)
+	app.Config.SetDefault("webhooks.maxRetries", 5)
+	app.Config.SetDefault("webhooks.retryBackoff", 1000)
+	app.Config.SetDefault("webhooks.maxRetryBackoff", 300000)
+	app.Config.SetDefault("webhooks.deadLettersPageSize", 50)
+	app.Config.SetDefault("webhooks.maxBatchSize", 1000)
+	app.Config.SetDefault("webhooks.batchTTL", 86400000)
+	app.Config.SetDefault("outbox.batchSize", 100)
+	app.Config.SetDefault("outbox.pollInterval", time.Second)
+	app.Config.SetDefault("outbox.retention", 24*time.Hour)
+	app.Config.SetDefault("audit.pageSize", 50)
+	app.Config.SetDefault("audit.maxPageSize", 500)
+	app.Config.SetDefault("topClans.pageSize", 10)
+	app.Config.SetDefault("topClans.maxPageSize", 100)
+	app.Config.SetDefault("listClans.pageSize", 100)
+	app.Config.SetDefault("listClans.maxPageSize", 1000)
+	app.Config.SetDefault("search.engine", "mongo")
+	app.Config.SetDefault("autocomplete.pageSize", 10)
+	app.Config.SetDefault("autocomplete.maxPageSize", 50)
+	app.Config.SetDefault("indexVerifier.enabled", false)
+	app.Config.SetDefault("indexVerifier.interval", time.Hour)
+	app.Config.SetDefault("indexVerifier.repair", false)
+	app.Config.SetDefault("reconcileCounts.batchSize", 1000)
+	app.Config.SetDefault("apiKeys.enabled", false)
+	app.Config.SetDefault("playerTokens.enabled", false)
+	app.Config.SetDefault("idempotency.ttl", 24*time.Hour)
+	app.Config.SetDefault("idempotency.lockTimeout", 30*time.Second)
+	app.Config.SetDefault("rateLimit.enabled", false)
+	app.Config.SetDefault("rateLimit.classes.read.game.rate", 1000)
+	app.Config.SetDefault("rateLimit.classes.read.game.burst", 2000)
+	app.Config.SetDefault("rateLimit.classes.read.player.rate", 20)
+	app.Config.SetDefault("rateLimit.classes.read.player.burst", 40)
+	app.Config.SetDefault("rateLimit.classes.search.game.rate", 200)
+	app.Config.SetDefault("rateLimit.classes.search.game.burst", 400)
+	app.Config.SetDefault("rateLimit.classes.search.player.rate", 2)
+	app.Config.SetDefault("rateLimit.classes.search.player.burst", 10)
+	app.Config.SetDefault("rateLimit.classes.write.game.rate", 500)
+	app.Config.SetDefault("rateLimit.classes.write.game.burst", 1000)
+	app.Config.SetDefault("rateLimit.classes.write.player.rate", 10)
+	app.Config.SetDefault("rateLimit.classes.write.player.burst", 20)
+	app.Config.SetDefault("rateLimit.classes.membership.game.rate", 200)
+	app.Config.SetDefault("rateLimit.classes.membership.game.burst", 400)
+	app.Config.SetDefault("rateLimit.classes.membership.player.rate", 2)
+	app.Config.SetDefault("rateLimit.classes.membership.player.burst", 10)
 	app.Config.SetDefault("elasticsearch.host", "localhost")
 	app.Config.SetDefault("elasticsearch.port", 9234)
 	app.Config.SetDefault("elasticsearch.sniff", true)
@@ -393,13 +442,21 @@ func (app *App) configureApplication() {
 	_, w, _ := os.Pipe()
 	a.SetLogOutput(w)
 
+	if app.Config.GetBool("playerTokens.enabled") {
+		// has to run before the other auth middlewares, which skip player token requests
+		a.Use(NewPlayerTokenMiddleware(app).Serve)
+	}
+
 	basicAuthUser := app.Config.GetString("basicauth.username")
-	if basicAuthUser != "" {
+	if app.Config.GetBool("apiKeys.enabled") {
+		// the basic auth credential becomes the super admin one
+		a.Use(NewAuthMiddleware(app).Serve)
+	} else if basicAuthUser != "" {
 		basicAuthPass := app.Config.GetString("basicauth.password")
 
 		a.Use(middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
 			Skipper: func(c echo.Context) bool {
-				return c.Path() == "/healthcheck"
+				return c.Path() == "/healthcheck" || isPlayerTokenRequest(c)
 			},
 			Validator: func(username, password string) bool {
 				return username == basicAuthUser && password == basicAuthPass
@@ -407,12 +464,21 @@ func (app *App) configureApplication() {
 		}))
 	}
 
+	if app.Config.GetBool("rateLimit.enabled") {
+		// runs after the auth middlewares, so requests authenticated with player tokens are limited per player
+		a.Use(NewRateLimitMiddleware(app).Serve)
+	}
+
+	// runs after the auth and rate limit middlewares, so replays are authenticated and limited too
+	a.Use(NewIdempotencyMiddleware(app).Serve)
+
 	//NewRelicMiddleware has to stand out from all others
 	a.Use(NewNewRelicMiddleware(app, app.Logger).Serve)
 
 	a.Use(NewRecoveryMiddleware(app.onErrorHandler).Serve)
 	a.Use(extechomiddleware.NewResponseTimeMetricsMiddleware(app.DDStatsD).Serve)
 	a.Use(NewVersionMiddleware().Serve)
+	a.Use(NewRequestIDMiddleware().Serve)
 	a.Use(NewSentryMiddleware(app).Serve)
 	a.Use(NewLoggerMiddleware(app.Logger).Serve)
 	a.Use(NewBodyExtractionMiddleware().Serve)
@@ -421,27 +487,50 @@ func (app *App) configureApplication() {
 	a.Get("/status", StatusHandler(app))
 
 	// Game Routes
+	a.Get("/games", ListGamesHandler(app))
 	a.Post("/games", CreateGameHandler(app))
+	a.Get("/games/:gameID", RetrieveGameHandler(app))
 	a.Put("/games/:gameID", UpdateGameHandler(app))
+	a.Post("/games/:gameID/reconcile-counts", ReconcileCountsHandler(app))
+
+	// API Key Routes
+	a.Get("/games/:gameID/api-keys", ListAPIKeysHandler(app))
+	a.Post("/games/:gameID/api-keys", CreateAPIKeyHandler(app))
+	a.Post("/games/:gameID/api-keys/:publicID/rotate", RotateAPIKeyHandler(app))
+	a.Delete("/games/:gameID/api-keys/:publicID", RevokeAPIKeyHandler(app))
+
+	// Player Token Routes
+	a.Get("/games/:gameID/player-tokens", RetrievePlayerTokenConfigHandler(app))
+	a.Put("/games/:gameID/player-tokens", SetPlayerTokenConfigHandler(app))
+	a.Delete("/games/:gameID/player-tokens", DeletePlayerTokenConfigHandler(app))
 
 	// Hook Routes
+	a.Get("/games/:gameID/hooks", ListHooksHandler(app))
 	a.Post("/games/:gameID/hooks", CreateHookHandler(app))
 	a.Delete("/games/:gameID/hooks/:publicID", RemoveHookHandler(app))
+	a.Get("/games/:gameID/hooks/dead-letters", ListHookDeadLettersHandler(app))
+	a.Post("/games/:gameID/hooks/dead-letters/:publicID/replay", ReplayHookDeadLetterHandler(app))
 
 	// Player Routes
 	a.Post("/games/:gameID/players", CreatePlayerHandler(app))
 	a.Put("/games/:gameID/players/:playerPublicID", UpdatePlayerHandler(app))
 	a.Get("/games/:gameID/players/:playerPublicID", RetrievePlayerHandler(app))
+	a.Get("/games/:gameID/players/:playerPublicID/audit", RetrievePlayerAuditHandler(app))
 
 	// Clan Routes
 	a.Get("/games/:gameID/clans/search", SearchClansHandler(app))
+	a.Get("/games/:gameID/clans/top", RetrieveTopClansHandler(app))
+	a.Get("/games/:gameID/clans/autocomplete", AutocompleteClansHandler(app))
 	a.Get("/games/:gameID/clans", ListClansHandler(app))
 	a.Post("/games/:gameID/clans", CreateClanHandler(app))
 	a.Get("/games/:gameID/clans-summary", RetrieveClansSummariesHandler(app))
 	a.Get("/games/:gameID/clans/:clanPublicID", RetrieveClanHandler(app))
 	a.Get("/games/:gameID/clans/:clanPublicID/members", RetrieveClanMembersHandler(app))
 	a.Get("/games/:gameID/clans/:clanPublicID/summary", RetrieveClanSummaryHandler(app))
+	a.Get("/games/:gameID/clans/:clanPublicID/audit", RetrieveClanAuditHandler(app))
 	a.Put("/games/:gameID/clans/:clanPublicID", UpdateClanHandler(app))
+	a.Delete("/games/:gameID/clans/:clanPublicID", DisbandClanHandler(app, false))
+	a.Post("/games/:gameID/clans/:clanPublicID/admin-disband", DisbandClanHandler(app, true))
 	a.Post("/games/:gameID/clans/:clanPublicID/leave", LeaveClanHandler(app))
 	a.Post("/games/:gameID/clans/:clanPublicID/transfer-ownership", TransferOwnershipHandler(app))
 
@@ -453,6 +542,8 @@ func (app *App) configureApplication() {
 	a.Post("/games/:gameID/clans/:clanPublicID/memberships/delete", DeleteMembershipHandler(app))
 	a.Post("/games/:gameID/clans/:clanPublicID/memberships/promote", PromoteOrDemoteMembershipHandler(app, "promote"))
 	a.Post("/games/:gameID/clans/:clanPublicID/memberships/demote", PromoteOrDemoteMembershipHandler(app, "demote"))
+	a.Post("/games/:gameID/clans/:clanPublicID/memberships/ban", BanOrUnbanMembershipHandler(app, "ban"))
+	a.Post("/games/:gameID/clans/:clanPublicID/memberships/unban", BanOrUnbanMembershipHandler(app, "unban"))
 
 	// pprof
 	pprofHandlers := map[string]func(http.ResponseWriter, *http.Request){
@@ -479,40 +570,6 @@ func (app *App) addError() {
 	app.Errors.Update(1)
 }
 
-//GetHooks returns all available hooks
-func (app *App) GetHooks(ctx context.Context) map[string]map[int][]*models.Hook {
-	l := app.Logger.With(
-		zap.String("source", "app"),
-		zap.String("operation", "GetHooks"),
-	)
-
-	start := time.Now()
-	log.D(l, "Retrieving hooks...")
-	dbHooks, err := models.GetAllHooks(app.Db(ctx))
-	if err != nil {
-		log.E(l, "Retrieve hooks failed.", func(cm log.CM) {
-			cm.Write(zap.String("error", err.Error()))
-		})
-		return nil
-	}
-	log.D(l, "Hooks retrieved successfully.", func(cm log.CM) {
-		cm.Write(zap.Duration("hookRetrievalDuration", time.Now().Sub(start)))
-	})
-
-	hooks := make(map[string]map[int][]*models.Hook)
-	for _, hook := range dbHooks {
-		if hooks[hook.GameID] == nil {
-			hooks[hook.GameID] = make(map[int][]*models.Hook)
-		}
-		hooks[hook.GameID][hook.EventType] = append(
-			hooks[hook.GameID][hook.EventType],
-			hook,
-		)
-	}
-
-	return hooks
-}
-
 //GetGame returns a game by Public ID
 func (app *App) GetGame(ctx context.Context, gameID string) (*models.Game, error) {
 	l := app.Logger.With(
@@ -602,6 +659,7 @@ func (app *App) configureGoWorkers() {
 	workers.Process(queues.KhanQueue, app.Dispatcher.PerformDispatchHook, workerCount)
 	workers.Process(queues.KhanESQueue, app.ESWorker.PerformUpdateES, workerCount)
 	workers.Process(queues.KhanMongoQueue, app.MongoWorker.PerformUpdateMongo, workerCount)
+	workers.Process(queues.KhanTopClansQueue, app.TopClansWorker.PerformUpdateTopClans, workerCount)
 	l.Info("Worker configured.")
 }
 
@@ -617,14 +675,37 @@ func (app *App) StartWorkers() {
 		jobsStatsPort := app.Config.GetInt("webhooks.statsPort")
 		go workers.StatsServer(jobsStatsPort)
 	}
+	go app.listenHooksInvalidations()
+	app.startOutboxRelay()
+	app.startIndexVerifier()
 	workers.Run()
 }
 
 //NonblockingStartWorkers non-blocking
 func (app *App) NonblockingStartWorkers() {
+	go app.listenHooksInvalidations()
+	app.startOutboxRelay()
+	app.startIndexVerifier()
 	workers.Start()
 }
 
+// outboxRelayOnce keeps a single relay per process, since all apps share the workers queues
+var outboxRelayOnce sync.Once
+
+// startOutboxRelay publishes the outbox messages written by the handlers to the workers queues
+func (app *App) startOutboxRelay() {
+	outboxRelayOnce.Do(func() {
+		relay := models.NewOutboxRelay(
+			app.Logger,
+			app.Db(nil),
+			app.Config.GetInt("outbox.batchSize"),
+			app.Config.GetDuration("outbox.pollInterval"),
+			app.Config.GetDuration("outbox.retention"),
+		)
+		go relay.Run()
+	})
+}
+
 func (app *App) initESWorker() {
 	l := app.Logger.With(
 		zap.String("source", "app"),
@@ -649,6 +730,18 @@ func (app *App) initMongoWorker() {
 	app.MongoWorker = mongoWorker
 }
 
+func (app *App) initTopClansWorker() {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "initTopClansWorker"),
+	)
+
+	log.D(l, "Initializing top clans worker...")
+	topClansWorker := models.NewTopClansWorker(app.Logger, app.Db(nil))
+	log.I(l, "Top Clans Worker initialized successfully")
+	app.TopClansWorker = topClansWorker
+}
+
 func (app *App) initDispatcher() {
 	l := app.Logger.With(
 		zap.String("source", "app"),
@@ -670,7 +763,9 @@ func (app *App) initDispatcher() {
 }
 
 // DispatchHooks dispatches web hooks for a specific game and event type
-func (app *App) DispatchHooks(gameID string, eventType int, payload map[string]interface{}) error {
+// Hooks are dispatched through the outbox of db, so when db is a transaction
+// they are only called if it is committed.
+func (app *App) DispatchHooks(db models.DB, gameID string, eventType int, payload map[string]interface{}) error {
 	l := app.Logger.With(
 		zap.String("source", "app"),
 		zap.String("operation", "DispatchHooks"),
@@ -680,7 +775,13 @@ func (app *App) DispatchHooks(gameID string, eventType int, payload map[string]i
 
 	start := time.Now()
 	log.D(l, "Dispatching hook...")
-	app.Dispatcher.DispatchHook(gameID, eventType, payload)
+	err := app.Dispatcher.DispatchHook(db, gameID, eventType, payload)
+	if err != nil {
+		log.E(l, "Hook dispatch failed.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return err
+	}
 	log.D(l, "Hook dispatched successfully.", func(cm log.CM) {
 		cm.Write(zap.Duration("hookDispatchDuration", time.Now().Sub(start)))
 	})
@@ -770,6 +871,9 @@ func (app *App) Start() {
 		cm.Write(zap.String("host", app.Host), zap.Int("port", app.Port))
 	})
 
+	// pre hooks run in the API processes, so their hooks cache must follow the other processes changes as well
+	go app.listenHooksInvalidations()
+
 	go func() {
 		app.App.Run(app.Engine)
 	}()
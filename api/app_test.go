@@ -9,12 +9,17 @@ package api_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"time"
 
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/satori/go.uuid"
@@ -38,6 +43,7 @@ func startRouteHandler(routes []string, port int) *[]map[string]interface{} {
 			json.Unmarshal(bs, &payload)
 
 			response := map[string]interface{}{
+				"body":     bs,
 				"payload":  payload,
 				"request":  r,
 				"response": w,
@@ -111,10 +117,49 @@ var _ = Describe("API Application", func() {
 			app := GetDefaultTestApp()
 			app.NonblockingStartWorkers()
 
-			hooks := app.GetHooks(context.Background())
-			Expect(len(hooks[gameID])).To(Equal(2))
-			Expect(len(hooks[gameID][0])).To(Equal(2))
-			Expect(len(hooks[gameID][1])).To(Equal(2))
+			Expect(app.GetHooks(context.Background(), gameID, 0)).To(HaveLen(2))
+			Expect(app.GetHooks(context.Background(), gameID, 1)).To(HaveLen(2))
+			Expect(app.GetHooks(context.Background(), gameID, 2)).To(BeEmpty())
+		})
+
+		It("should reload the hooks of a game after they are invalidated", func() {
+			gameID := uuid.NewV4().String()
+			_, err := models.GetTestHooks(testDb, gameID, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			Expect(app.GetHooks(context.Background(), gameID, 0)).To(HaveLen(1))
+
+			_, err = models.CreateHook(testDb, gameID, 0, "http://test/created", "", "", 0, 0, "", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.GetHooks(context.Background(), gameID, 0)).To(HaveLen(1))
+
+			err = app.InvalidateHooks(gameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.GetHooks(context.Background(), gameID, 0)).To(HaveLen(2))
+		})
+
+		It("should reload the hooks of a game invalidated by another process", func() {
+			gameID := uuid.NewV4().String()
+			_, err := models.GetTestHooks(testDb, gameID, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			Expect(app.GetHooks(context.Background(), gameID, 0)).To(HaveLen(1))
+
+			_, err = models.CreateHook(testDb, gameID, 0, "http://test/created", "", "", 0, 0, "", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			other := GetDefaultTestApp()
+			Eventually(func() []*models.Hook {
+				err = other.InvalidateHooks(gameID)
+				Expect(err).NotTo(HaveOccurred())
+				return app.GetHooks(context.Background(), gameID, 0)
+			}).Should(HaveLen(2))
 		})
 	})
 
@@ -134,7 +179,7 @@ var _ = Describe("API Application", func() {
 				"success":  true,
 				"publicID": hooks[0].GameID,
 			}
-			err = app.DispatchHooks(hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
 			Expect(err).NotTo(HaveOccurred())
 
 			Eventually(func() int {
@@ -163,6 +208,7 @@ var _ = Describe("API Application", func() {
 				"publicID": hooks[0].GameID,
 			}
 			err = app.DispatchHooks(
+				testDb,
 				hooks[0].GameID,
 				models.GameUpdatedHook,
 				resultingPayload,
@@ -196,7 +242,7 @@ var _ = Describe("API Application", func() {
 				"success":  true,
 				"publicID": hooks[0].GameID,
 			}
-			err = app.DispatchHooks(hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
 			Expect(err).NotTo(HaveOccurred())
 			Eventually(func() int {
 				return len(*responses)
@@ -223,7 +269,7 @@ var _ = Describe("API Application", func() {
 					"publicID": hooks[0].GameID,
 				},
 			}
-			err = app.DispatchHooks(hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
 			Expect(err).NotTo(HaveOccurred())
 			Eventually(func() int {
 				return len(*responses)
@@ -249,12 +295,241 @@ var _ = Describe("API Application", func() {
 					"publicID": hooks[0].GameID,
 				},
 			}
-			err = app.DispatchHooks(hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
 			Expect(err).NotTo(HaveOccurred())
 
 			Consistently(func() int {
 				return len(*responses)
 			}, 50*time.Millisecond, 10*time.Millisecond).Should(Equal(0))
 		})
+
+		It("should sign hook payload if hook has secret", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/signed",
+				"http://localhost:52525/unsigned",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			hooks[0].Secret = "my-secret"
+			_, err = testDb.Update(hooks[0])
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/signed", "/unsigned"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			resultingPayload := map[string]interface{}{
+				"success":  true,
+				"publicID": hooks[0].GameID,
+			}
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(2))
+
+			for _, resp := range *responses {
+				req := resp["request"].(*http.Request)
+				timestamp := req.Header.Get(HookTimestampHeader)
+				Expect(timestamp).NotTo(BeEmpty())
+
+				signature := req.Header.Get(HookSignatureHeader)
+				if req.URL.Path == "/unsigned" {
+					Expect(signature).To(BeEmpty())
+					continue
+				}
+
+				mac := hmac.New(sha256.New, []byte("my-secret"))
+				mac.Write([]byte(timestamp + "."))
+				mac.Write(resp["body"].([]byte))
+				Expect(signature).To(Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+			}
+		})
+
+		It("should only dispatch hooks whose filter matches the payload", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/filtered-eu",
+				"http://localhost:52525/filtered-us",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			hooks[0].Filter = `game.metadata.region == "EU"`
+			hooks[1].Filter = `game.metadata.region == "US"`
+			for _, hook := range hooks {
+				_, err = testDb.Update(hook)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			responses := startRouteHandler([]string{"/filtered-eu", "/filtered-us"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			resultingPayload := map[string]interface{}{
+				"success":  true,
+				"publicID": hooks[0].GameID,
+				"game": map[string]interface{}{
+					"metadata": map[string]interface{}{"region": "EU"},
+				},
+			}
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+			Consistently(func() int {
+				return len(*responses)
+			}, 50*time.Millisecond, 10*time.Millisecond).Should(Equal(1))
+
+			req := (*responses)[0]["request"].(*http.Request)
+			Expect(req.URL.Path).To(Equal("/filtered-eu"))
+		})
+
+		It("should deliver batched hooks as a JSON array when the batch is full", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/batched",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			hooks[0].BatchSize = 2
+			hooks[0].BatchInterval = 60000
+			_, err = testDb.Update(hooks[0])
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/batched"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			batchKey := fmt.Sprintf("khan:hook-batch:%s:%s", hooks[0].GameID, hooks[0].PublicID)
+			batchLen := func() int {
+				conn := workers.Config.Pool.Get()
+				defer conn.Close()
+				length, err := redis.Int(conn.Do("LLEN", batchKey))
+				Expect(err).NotTo(HaveOccurred())
+				return length
+			}
+
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, map[string]interface{}{
+				"publicID": hooks[0].GameID,
+				"order":    1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(batchLen).Should(Equal(1))
+			Expect(len(*responses)).To(Equal(0))
+
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, map[string]interface{}{
+				"publicID": hooks[0].GameID,
+				"order":    2,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+			Expect(batchLen()).To(Equal(0))
+
+			var batch []map[string]interface{}
+			err = json.Unmarshal((*responses)[0]["body"].([]byte), &batch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch).To(HaveLen(2))
+			Expect(batch[0]["order"]).To(BeEquivalentTo(1))
+			Expect(batch[1]["order"]).To(BeEquivalentTo(2))
+		})
+
+		It("should not deliver batches while a failed batch of the hook is retried", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/held-batch",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			hooks[0].BatchSize = 1
+			_, err = testDb.Update(hooks[0])
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/held-batch"}, 52525)
+
+			app := GetDefaultTestApp()
+			app.NonblockingStartWorkers()
+
+			batchKey := fmt.Sprintf("khan:hook-batch:%s:%s", hooks[0].GameID, hooks[0].PublicID)
+			conn := workers.Config.Pool.Get()
+			defer conn.Close()
+			_, err = conn.Do("SET", batchKey+":hold", "failed-batch-id", "PX", 60000)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, map[string]interface{}{
+				"publicID": hooks[0].GameID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				length, err := redis.Int(conn.Do("LLEN", batchKey))
+				Expect(err).NotTo(HaveOccurred())
+				return length
+			}).Should(Equal(1))
+			Consistently(func() int {
+				return len(*responses)
+			}, 200*time.Millisecond, 20*time.Millisecond).Should(Equal(0))
+		})
+
+		It("should store batch as a single dead letter if it fails after all retries", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52526/unreachable-batch",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+			hooks[0].BatchSize = 1
+			hooks[0].BatchInterval = 60000
+			_, err = testDb.Update(hooks[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+			app.Config.Set("webhooks.maxRetries", 0)
+			app.NonblockingStartWorkers()
+
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, map[string]interface{}{
+				"publicID": hooks[0].GameID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				deadLetters, err := models.GetHookDeadLettersByGameID(testDb, hooks[0].GameID, 10)
+				Expect(err).NotTo(HaveOccurred())
+				return len(deadLetters)
+			}).Should(Equal(1))
+
+			deadLetters, err := models.GetHookDeadLettersByGameID(testDb, hooks[0].GameID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters[0].Batched).To(BeTrue())
+			Expect(deadLetters[0].Payload["events"]).To(HaveLen(1))
+		})
+
+		It("should store dead letter if hook fails after all retries", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52526/unreachable",
+			}, models.GameUpdatedHook)
+			Expect(err).NotTo(HaveOccurred())
+
+			app := GetDefaultTestApp()
+			app.Config.Set("webhooks.maxRetries", 0)
+			app.NonblockingStartWorkers()
+
+			resultingPayload := map[string]interface{}{
+				"success":  true,
+				"publicID": hooks[0].GameID,
+			}
+			err = app.DispatchHooks(testDb, hooks[0].GameID, models.GameUpdatedHook, resultingPayload)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				deadLetters, err := models.GetHookDeadLettersByGameID(testDb, hooks[0].GameID, 10)
+				Expect(err).NotTo(HaveOccurred())
+				return len(deadLetters)
+			}).Should(Equal(1))
+
+			deadLetters, err := models.GetHookDeadLettersByGameID(testDb, hooks[0].GameID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters[0].HookPublicID).To(Equal(hooks[0].PublicID))
+			Expect(deadLetters[0].URL).To(Equal(hooks[0].URL))
+			Expect(deadLetters[0].EventType).To(Equal(models.GameUpdatedHook))
+			Expect(deadLetters[0].Payload["publicID"]).To(Equal(hooks[0].GameID))
+			Expect(deadLetters[0].LastError).NotTo(BeEmpty())
+			Expect(deadLetters[0].Attempts).To(Equal(1))
+		})
 	})
 })
@@ -0,0 +1,193 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// createAuditEvent records the action in the audit trail using the transaction of the handler
+func createAuditEvent(
+	c echo.Context, tx interfaces.Transaction, gameID, action, actorPublicID, clanPublicID, playerPublicID string,
+	before, after map[string]interface{},
+) error {
+	return WithSegment("audit-create", c, func() error {
+		_, err := models.CreateAuditEvent(
+			tx, gameID, action, actorPublicID, clanPublicID, playerPublicID,
+			before, after, getRequestID(c),
+		)
+		return err
+	})
+}
+
+// clanAuditSnapshot returns the state of the clan stored in audit events
+func clanAuditSnapshot(clan *models.Clan, ownerPublicID string) map[string]interface{} {
+	if clan == nil {
+		return nil
+	}
+	snapshot := clan.Serialize()
+	delete(snapshot, "gameID")
+	snapshot["ownerPublicID"] = ownerPublicID
+	return snapshot
+}
+
+// membershipAuditSnapshot returns the state of the membership stored in audit events
+func membershipAuditSnapshot(membership *models.Membership) map[string]interface{} {
+	if membership == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"level":      membership.Level,
+		"approved":   membership.Approved,
+		"denied":     membership.Denied,
+		"banned":     membership.Banned,
+		"message":    membership.Message,
+		"approvedAt": membership.ApprovedAt,
+		"deniedAt":   membership.DeniedAt,
+		"deletedAt":  membership.DeletedAt,
+		"bannedAt":   membership.BannedAt,
+	}
+}
+
+// parseAuditPagination returns the limit and from query params of the audit endpoints
+func parseAuditPagination(app *App, c echo.Context) (int, int, error) {
+	limit := app.Config.GetInt("audit.pageSize")
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsedLimit, err := parseLimitString(c, limitStr)
+		if err != nil {
+			return 0, 0, err
+		}
+		limit = parsedLimit
+	}
+	if maxPageSize := app.Config.GetInt("audit.maxPageSize"); limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	fromIndex := 0
+	if fromIndexStr := c.QueryParam("from"); fromIndexStr != "" {
+		parsedFromIndex, err := parseFromIndexString(c, fromIndexStr)
+		if err != nil {
+			return 0, 0, err
+		}
+		fromIndex = parsedFromIndex
+	}
+	return limit, fromIndex, nil
+}
+
+func serializeAuditEvents(events []*models.AuditEvent) []map[string]interface{} {
+	serializedEvents := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		serializedEvents[i] = event.Serialize()
+	}
+	return serializedEvents
+}
+
+// RetrieveClanAuditHandler is the handler responsible for returning the audit trail of a clan
+func RetrieveClanAuditHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrieveClanAudit")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		clanPublicID := c.Param("clanPublicID")
+
+		limit, fromIndex, err := parseAuditPagination(app, c)
+		if err != nil {
+			return err
+		}
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "auditHandler"),
+			zap.String("operation", "retrieveClanAudit"),
+			zap.String("gameID", gameID),
+			zap.String("clanPublicID", clanPublicID),
+			zap.Int("limit", limit),
+			zap.Int("from", fromIndex),
+		)
+
+		var events []*models.AuditEvent
+		err = WithSegment("audit-retrieve", c, func() error {
+			log.D(l, "Retrieving clan audit events...")
+			events, err = models.GetClanAuditEvents(db, gameID, clanPublicID, limit, fromIndex)
+			if err != nil {
+				log.E(l, "Failed to retrieve clan audit events.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		log.D(l, "Clan audit events retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"auditEvents": serializeAuditEvents(events),
+		}, c)
+	}
+}
+
+// RetrievePlayerAuditHandler is the handler responsible for returning the audit trail of a player
+func RetrievePlayerAuditHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrievePlayerAudit")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		playerPublicID := c.Param("playerPublicID")
+
+		limit, fromIndex, err := parseAuditPagination(app, c)
+		if err != nil {
+			return err
+		}
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "auditHandler"),
+			zap.String("operation", "retrievePlayerAudit"),
+			zap.String("gameID", gameID),
+			zap.String("playerPublicID", playerPublicID),
+			zap.Int("limit", limit),
+			zap.Int("from", fromIndex),
+		)
+
+		var events []*models.AuditEvent
+		err = WithSegment("audit-retrieve", c, func() error {
+			log.D(l, "Retrieving player audit events...")
+			events, err = models.GetPlayerAuditEvents(db, gameID, playerPublicID, limit, fromIndex)
+			if err != nil {
+				log.E(l, "Failed to retrieve player audit events.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		log.D(l, "Player audit events retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"auditEvents": serializeAuditEvents(events),
+		}, c)
+	}
+}
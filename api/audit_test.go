@@ -0,0 +1,148 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Audit API Handler", func() {
+	var testDb models.DB
+	var a *api.App
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		a = GetDefaultTestApp()
+	})
+
+	getAuditEvents := func(route string) []interface{} {
+		status, body := Get(a, route)
+		Expect(status).To(Equal(http.StatusOK))
+		var result map[string]interface{}
+		json.Unmarshal([]byte(body), &result)
+		Expect(result["success"]).To(BeTrue())
+		return result["auditEvents"].([]interface{})
+	}
+
+	Describe("Retrieve Clan Audit Handler", func() {
+		It("Should record clan updates with the request id", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"name":             "new-name",
+				"ownerPublicID":    owner.PublicID,
+				"metadata":         map[string]interface{}{"new": "metadata"},
+				"allowApplication": clan.AllowApplication,
+				"autoJoin":         clan.AutoJoin,
+			})
+			ts := InitializeTestServer(a)
+			defer ts.Close()
+			req := GetRequest(a, ts, "PUT", GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID)), string(payload))
+			req.Header.Set(api.RequestIDHeader, "update-request-id")
+			status, _ := PerformRequest(ts, req)
+			Expect(status).To(Equal(http.StatusOK))
+
+			events := getAuditEvents(GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/audit", clan.PublicID)))
+			Expect(events).To(HaveLen(1))
+			event := events[0].(map[string]interface{})
+			Expect(event["action"]).To(Equal(models.AuditClanUpdated))
+			Expect(event["actorPublicID"]).To(Equal(owner.PublicID))
+			Expect(event["clanPublicID"]).To(Equal(clan.PublicID))
+			Expect(event["requestID"]).To(Equal("update-request-id"))
+			Expect(event["before"].(map[string]interface{})["name"]).To(Equal(clan.Name))
+			Expect(event["after"].(map[string]interface{})["name"]).To(Equal("new-name"))
+			Expect(event["after"].(map[string]interface{})["ownerPublicID"]).To(Equal(owner.PublicID))
+		})
+
+		It("Should not record failed actions", func() {
+			_, clan, _, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"name":             "new-name",
+				"ownerPublicID":    players[0].PublicID,
+				"metadata":         map[string]interface{}{},
+				"allowApplication": clan.AllowApplication,
+				"autoJoin":         clan.AutoJoin,
+			}
+			status, _ := PutJSON(a, GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID)), payload)
+			Expect(status).To(Equal(http.StatusNotFound))
+
+			events := getAuditEvents(GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/audit", clan.PublicID)))
+			Expect(events).To(BeEmpty())
+		})
+
+		It("Should paginate clan audit events", func() {
+			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				_, err = models.CreateAuditEvent(
+					testDb, clan.GameID, models.AuditClanUpdated, owner.PublicID, clan.PublicID, owner.PublicID,
+					nil, map[string]interface{}{"index": i}, "",
+				)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s/audit", clan.PublicID))
+			Expect(getAuditEvents(route + "?limit=2")).To(HaveLen(2))
+			events := getAuditEvents(route + "?limit=2&from=2")
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].(map[string]interface{})["after"].(map[string]interface{})["index"]).To(BeEquivalentTo(0))
+		})
+
+		It("Should fail if limit is not an integer", func() {
+			status, body := Get(a, GetGameRoute("game-id", "/clans/clan-id/audit?limit=invalid"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+	})
+
+	Describe("Retrieve Player Audit Handler", func() {
+		It("Should record membership actions performed by and on the player", func() {
+			_, clan, owner, players, memberships, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			memberships[0].Approved = true
+			_, err = testDb.Update(memberships[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, _ := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "promote"), payload)
+			Expect(status).To(Equal(http.StatusOK))
+
+			for _, player := range []*models.Player{owner, players[0]} {
+				events := getAuditEvents(GetGameRoute(clan.GameID, fmt.Sprintf("/players/%s/audit", player.PublicID)))
+				Expect(events).To(HaveLen(1))
+				event := events[0].(map[string]interface{})
+				Expect(event["action"]).To(Equal(models.AuditMembershipPromoted))
+				Expect(event["actorPublicID"]).To(Equal(owner.PublicID))
+				Expect(event["playerPublicID"]).To(Equal(players[0].PublicID))
+				Expect(event["requestID"]).NotTo(BeEmpty())
+				Expect(event["before"].(map[string]interface{})["level"]).To(Equal(memberships[0].Level))
+				Expect(event["after"].(map[string]interface{})["level"]).To(Equal("Elder"))
+			}
+		})
+	})
+})
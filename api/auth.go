@@ -0,0 +1,278 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// APIKeyHeader carries the game api key of the request
+const APIKeyHeader = "X-Khan-API-Key"
+
+const gameRoutesPrefix = "/games/:gameID"
+
+// RequiredAPIKeyScope returns the api key scope required by the route, or an empty string if
+// only the super admin can use it
+// Game routes can be read with read-only keys, players, clans and memberships can be changed
+// with player-write keys, and the game itself, its hooks and api keys require admin keys.
+func RequiredAPIKeyScope(method, path string) string {
+	if !strings.HasPrefix(path, gameRoutesPrefix) {
+		return ""
+	}
+	route := strings.TrimPrefix(path, gameRoutesPrefix)
+	if route != "" && !strings.HasPrefix(route, "/") {
+		return ""
+	}
+	read := method == echo.GET || method == echo.HEAD
+
+	// disbanding clans on behalf of the game bypasses the clan owner
+	if strings.HasSuffix(route, "/admin-disband") {
+		return models.APIKeyScopeAdmin
+	}
+	if strings.HasPrefix(route, "/players") || strings.HasPrefix(route, "/clans") {
+		if read {
+			return models.APIKeyScopeReadOnly
+		}
+		return models.APIKeyScopePlayerWrite
+	}
+	if route == "" && read {
+		return models.APIKeyScopeReadOnly
+	}
+	return models.APIKeyScopeAdmin
+}
+
+// NewAuthMiddleware returns a new auth middleware
+func NewAuthMiddleware(app *App) *AuthMiddleware {
+	return &AuthMiddleware{
+		App:               app,
+		SuperAdminUser:    app.Config.GetString("basicauth.username"),
+		SuperAdminPass:    app.Config.GetString("basicauth.password"),
+		SkipAuthForRoutes: map[string]bool{"/healthcheck": true},
+	}
+}
+
+// AuthMiddleware authenticates the requests with the super admin basic auth credential or
+// with game api keys, which can only use the routes of their game within their scope
+type AuthMiddleware struct {
+	App               *App
+	SuperAdminUser    string
+	SuperAdminPass    string
+	SkipAuthForRoutes map[string]bool
+}
+
+// Serve serves the middleware
+func (m *AuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		path := c.Path()
+		if m.SkipAuthForRoutes[path] || isPlayerTokenRequest(c) || m.isSuperAdmin(c) {
+			return next(c)
+		}
+
+		l := m.App.Logger.With(
+			zap.String("source", "AuthMiddleware"),
+			zap.String("operation", "Serve"),
+			zap.String("method", c.Request().Method()),
+			zap.String("path", path),
+		)
+
+		key := c.Request().Header().Get(APIKeyHeader)
+		if key == "" {
+			log.D(l, "Request without credentials.")
+			return FailWith(http.StatusUnauthorized, "Authentication required.", c)
+		}
+
+		apiKey, err := models.GetAPIKeyByKey(m.App.Db(c.StdContext()), key)
+		if err != nil {
+			log.W(l, "Request with an invalid api key.")
+			return FailWith(http.StatusUnauthorized, "Invalid api key.", c)
+		}
+
+		l = l.With(zap.String("gameID", apiKey.GameID), zap.String("apiKeyPublicID", apiKey.PublicID))
+		scope := RequiredAPIKeyScope(c.Request().Method(), path)
+		if scope == "" || apiKey.GameID != c.Param("gameID") || !apiKey.Allows(scope) {
+			log.W(l, "Api key not allowed to use the route.", func(cm log.CM) {
+				cm.Write(zap.String("requiredScope", scope), zap.String("scope", apiKey.Scope))
+			})
+			return FailWith(http.StatusForbidden, "Api key not allowed to use this route.", c)
+		}
+
+		c.Set("apiKey", apiKey)
+		return next(c)
+	}
+}
+
+func (m *AuthMiddleware) isSuperAdmin(c echo.Context) bool {
+	if m.SuperAdminUser == "" {
+		return false
+	}
+	auth := c.Request().Header().Get(echo.HeaderAuthorization)
+	if !strings.HasPrefix(auth, "Basic ") {
+		return false
+	}
+	credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+	if err != nil {
+		return false
+	}
+	userAndPass := strings.SplitN(string(credentials), ":", 2)
+	if len(userAndPass) != 2 {
+		return false
+	}
+	validUser := subtle.ConstantTimeCompare([]byte(userAndPass[0]), []byte(m.SuperAdminUser)) == 1
+	validPass := subtle.ConstantTimeCompare([]byte(userAndPass[1]), []byte(m.SuperAdminPass)) == 1
+	return validUser && validPass
+}
+
+// playerTokenRequestor tells where the public id of the player making a request is
+// An empty source means the route only reads game data and has no requestor.
+type playerTokenRequestor struct {
+	Source string
+	Name   string
+}
+
+// playerTokenRequestors maps the routes that can be used with player tokens to their requestor,
+// which must be the player of the token. Routes missing here can't be used with player tokens,
+// whatever the game config.
+var playerTokenRequestors = map[string]playerTokenRequestor{
+	"POST /games/:gameID/players":                                             {"body", "publicID"},
+	"PUT /games/:gameID/players/:playerPublicID":                              {"param", "playerPublicID"},
+	"GET /games/:gameID/players/:playerPublicID":                              {"param", "playerPublicID"},
+	"GET /games/:gameID/players/:playerPublicID/audit":                        {"param", "playerPublicID"},
+	"GET /games/:gameID/clans/search":                                         {},
+	"GET /games/:gameID/clans/top":                                            {},
+	"GET /games/:gameID/clans/autocomplete":                                   {},
+	"GET /games/:gameID/clans":                                                {},
+	"GET /games/:gameID/clans-summary":                                        {},
+	"GET /games/:gameID/clans/:clanPublicID":                                  {},
+	"GET /games/:gameID/clans/:clanPublicID/members":                          {},
+	"GET /games/:gameID/clans/:clanPublicID/summary":                          {},
+	"POST /games/:gameID/clans":                                               {"body", "ownerPublicID"},
+	"PUT /games/:gameID/clans/:clanPublicID":                                  {"body", "ownerPublicID"},
+	"DELETE /games/:gameID/clans/:clanPublicID":                               {"query", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/application":         {"body", "playerPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/application/:action": {"body", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/invitation":          {"body", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/invitation/:action":  {"body", "playerPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/delete":              {"body", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/promote":             {"body", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/demote":              {"body", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/ban":                 {"body", "requestorPublicID"},
+	"POST /games/:gameID/clans/:clanPublicID/memberships/unban":               {"body", "requestorPublicID"},
+}
+
+// IsPlayerTokenRoute returns whether the route, given as "METHOD /path", can be used with player tokens
+func IsPlayerTokenRoute(route string) bool {
+	_, ok := playerTokenRequestors[route]
+	return ok
+}
+
+func (r playerTokenRequestor) publicID(c echo.Context) (string, error) {
+	switch r.Source {
+	case "param":
+		return c.Param(r.Name), nil
+	case "query":
+		return c.QueryParam(r.Name), nil
+	case "body":
+		body, err := GetRequestBody(c)
+		if err != nil {
+			return "", err
+		}
+		var payload map[string]interface{}
+		if err = json.Unmarshal(body, &payload); err != nil {
+			return "", err
+		}
+		publicID, _ := payload[r.Name].(string)
+		return publicID, nil
+	}
+	return "", nil
+}
+
+func isPlayerTokenRequest(c echo.Context) bool {
+	return c.Get("playerPublicID") != nil
+}
+
+// NewPlayerTokenMiddleware returns a new player token middleware
+func NewPlayerTokenMiddleware(app *App) *PlayerTokenMiddleware {
+	return &PlayerTokenMiddleware{
+		App: app,
+	}
+}
+
+// PlayerTokenMiddleware authenticates requests made by game clients with player tokens, sent as
+// bearer tokens, and only lets them act as the player of the token on the routes their game allows
+// Requests without bearer tokens are left to the other auth middlewares.
+type PlayerTokenMiddleware struct {
+	App *App
+}
+
+// Serve serves the middleware
+func (m *PlayerTokenMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		auth := c.Request().Header().Get(echo.HeaderAuthorization)
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return next(c)
+		}
+
+		gameID := c.Param("gameID")
+		route := c.Request().Method() + " " + c.Path()
+		l := m.App.Logger.With(
+			zap.String("source", "PlayerTokenMiddleware"),
+			zap.String("operation", "Serve"),
+			zap.String("route", route),
+			zap.String("gameID", gameID),
+		)
+
+		requestor, ok := playerTokenRequestors[route]
+		if !ok {
+			log.D(l, "Route can't be used with player tokens.")
+			return FailWith(http.StatusForbidden, "Player tokens not allowed to use this route.", c)
+		}
+
+		config, err := models.GetPlayerTokenConfigByGameID(m.App.Db(c.StdContext()), gameID)
+		if err != nil {
+			log.W(l, "Player tokens are not configured for the game.")
+			return FailWith(http.StatusUnauthorized, "Invalid player token.", c)
+		}
+
+		playerPublicID, err := config.ParseToken(strings.TrimPrefix(auth, "Bearer "))
+		if err != nil {
+			log.W(l, "Request with an invalid player token.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(http.StatusUnauthorized, "Invalid player token.", c)
+		}
+		l = l.With(zap.String("playerPublicID", playerPublicID))
+
+		if !config.AllowsRoute(route) {
+			log.W(l, "Player tokens not allowed to use the route by the game.")
+			return FailWith(http.StatusForbidden, "Player tokens not allowed to use this route.", c)
+		}
+
+		requestorPublicID, err := requestor.publicID(c)
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+		if requestor.Source != "" && requestorPublicID != playerPublicID {
+			log.W(l, "Player token used on behalf of another player.", func(cm log.CM) {
+				cm.Write(zap.String("requestorPublicID", requestorPublicID))
+			})
+			return FailWith(http.StatusForbidden, "Player token does not belong to the requestor.", c)
+		}
+
+		c.Set("playerPublicID", playerPublicID)
+		return next(c)
+	}
+}
@@ -101,6 +101,14 @@ func CreateClanHandler(app *App) func(c echo.Context) error {
 			return FailWith(404, err.Error(), c)
 		}
 
+		err = runClanPreHooks(
+			app, c, gameID, models.ClanCreatedHook, payload.PublicID, payload.OwnerPublicID,
+			&payload.Name, &payload.Metadata, &payload.AllowApplication, &payload.AutoJoin,
+		)
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
 		var clan *models.Clan
 		var tx interfaces.Transaction
 
@@ -146,6 +154,21 @@ func CreateClanHandler(app *App) func(c echo.Context) error {
 				}
 				return err
 			}
+
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditClanCreated,
+				payload.OwnerPublicID, clan.PublicID, payload.OwnerPublicID,
+				nil, clanAuditSnapshot(clan, payload.OwnerPublicID),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Create clan audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
 			return nil
 		})
 		if err != nil {
@@ -169,7 +192,7 @@ func CreateClanHandler(app *App) func(c echo.Context) error {
 
 		err = WithSegment("hook-dispatch", c, func() error {
 			log.D(l, "Dispatching hooks")
-			err = app.DispatchHooks(gameID, models.ClanCreatedHook, result)
+			err = app.DispatchHooks(tx, gameID, models.ClanCreatedHook, result)
 			if err != nil {
 				txErr := rb(err)
 				if txErr == nil {
@@ -235,8 +258,32 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 			return FailWith(400, err.Error(), c)
 		}
 
+		version, checkVersion, err := getIfMatchVersion(c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		err = runClanPreHooks(
+			app, c, gameID, models.ClanUpdatedHook, publicID, payload.OwnerPublicID,
+			&payload.Name, &payload.Metadata, &payload.AllowApplication, &payload.AutoJoin,
+		)
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
 		var clan, beforeUpdateClan *models.Clan
 		var game *models.Game
+		var tx interfaces.Transaction
+
+		//rollback function
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Updating clan failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
 
 		err = WithSegment("clan-update", c, func() error {
 			err = WithSegment("game-retrieve", c, func() error {
@@ -253,13 +300,31 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 			}
 			log.D(l, "Game retrieved successfully")
 
+			err = WithSegment("tx-begin", c, func() error {
+				tx, err = app.BeginTrans(c.StdContext(), l)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			log.D(l, "DB Tx begun successful.")
+
 			err = WithSegment("clan-retrieve", c, func() error {
-				log.D(l, "Retrieving clan...")
-				beforeUpdateClan, err = models.GetClanByPublicID(db, gameID, publicID)
+				if checkVersion {
+					log.D(l, "Checking clan version...")
+					err = models.CheckClanVersion(tx, gameID, publicID, version)
+				}
+				if err == nil {
+					log.D(l, "Retrieving clan...")
+					beforeUpdateClan, err = models.GetClanByPublicID(tx, gameID, publicID)
+				}
 				if err != nil {
-					log.E(l, "Updating clan failed.", func(cm log.CM) {
-						cm.Write(zap.Error(err))
-					})
+					txErr := rb(err)
+					if txErr == nil {
+						log.E(l, "Updating clan failed.", func(cm log.CM) {
+							cm.Write(zap.Error(err))
+						})
+					}
 					return err
 				}
 				log.D(l, "Clan retrieved successfully")
@@ -272,7 +337,7 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 			err = WithSegment("clan-update-query", c, func() error {
 				log.D(l, "Updating clan...")
 				clan, err = models.UpdateClan(
-					db,
+					tx,
 					gameID,
 					publicID,
 					payload.Name,
@@ -284,9 +349,28 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 				return err
 			})
 			if err != nil {
-				log.E(l, "Updating clan failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Updating clan failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditClanUpdated,
+				payload.OwnerPublicID, publicID, payload.OwnerPublicID,
+				clanAuditSnapshot(beforeUpdateClan, payload.OwnerPublicID),
+				clanAuditSnapshot(clan, payload.OwnerPublicID),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Updating clan audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
 				return err
 			}
 			return nil
@@ -314,11 +398,14 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 			shouldDispatch := validateUpdateClanDispatch(game, beforeUpdateClan, clan, payload.Metadata, l)
 			if shouldDispatch {
 				log.D(l, "Dispatching clan update hooks...")
-				err = app.DispatchHooks(gameID, models.ClanUpdatedHook, result)
+				err = app.DispatchHooks(tx, gameID, models.ClanUpdatedHook, result)
 				if err != nil {
-					log.E(l, "Clan updated hook dispatch failed.", func(cm log.CM) {
-						cm.Write(zap.Error(err))
-					})
+					txErr := rb(err)
+					if txErr == nil {
+						log.E(l, "Clan updated hook dispatch failed.", func(cm log.CM) {
+							cm.Write(zap.Error(err))
+						})
+					}
 					return err
 				}
 			}
@@ -328,10 +415,18 @@ func UpdateClanHandler(app *App) func(c echo.Context) error {
 			return FailWith(500, err.Error(), c)
 		}
 
+		err = app.Commit(tx, "Clan updated", c, l)
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
 		log.D(l, "Clan updated successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
-		return SucceedWith(map[string]interface{}{}, c)
+		setVersionETag(c, clan.Version)
+		return SucceedWith(map[string]interface{}{
+			"version": clan.Version,
+		}, c)
 	}
 }
 
@@ -354,7 +449,7 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 		)
 
 		var tx interfaces.Transaction
-		var clan *models.Clan
+		var clan, beforeLeaveClan *models.Clan
 		var previousOwner, newOwner *models.Player
 		var err error
 
@@ -379,6 +474,11 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 			log.D(l, "DB Tx begun successful.")
 
 			err = WithSegment("clan-leave-query", c, func() error {
+				beforeLeaveClan, err = models.GetClanByPublicID(tx, gameID, publicID)
+				if err != nil {
+					return err
+				}
+
 				log.D(l, "Leaving clan...")
 				clan, previousOwner, newOwner, err = models.LeaveClan(
 					tx,
@@ -403,6 +503,25 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 				}
 				return err
 			}
+
+			var after map[string]interface{}
+			if newOwner != nil {
+				after = clanAuditSnapshot(clan, newOwner.PublicID)
+			}
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditClanLeft,
+				previousOwner.PublicID, publicID, previousOwner.PublicID,
+				clanAuditSnapshot(beforeLeaveClan, previousOwner.PublicID), after,
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Clan leave audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
 			return nil
 		})
 		if err != nil {
@@ -410,7 +529,7 @@ func LeaveClanHandler(app *App) func(c echo.Context) error {
 		}
 
 		err = WithSegment("hook-dispatch", c, func() error {
-			err = dispatchClanOwnershipChangeHook(app, models.ClanLeftHook, clan, previousOwner, newOwner)
+			err = dispatchClanOwnershipChangeHook(app, tx, models.ClanLeftHook, clan, previousOwner, newOwner)
 			if err != nil {
 				txErr := rb(err)
 				if txErr == nil {
@@ -514,7 +633,7 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 		}
 
 		var tx interfaces.Transaction
-		var clan *models.Clan
+		var clan, beforeTransferClan *models.Clan
 		var previousOwner, newOwner *models.Player
 
 		rb := func(err error) error {
@@ -536,6 +655,11 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 			}
 
 			err = WithSegment("clan-transfer-query", c, func() error {
+				beforeTransferClan, err = models.GetClanByPublicID(tx, gameID, publicID)
+				if err != nil {
+					return err
+				}
+
 				log.D(l, "Transferring clan ownership...")
 				clan, previousOwner, newOwner, err = models.TransferClanOwnership(
 					tx,
@@ -557,6 +681,22 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 				}
 				return err
 			}
+
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditClanOwnershipTransferred,
+				previousOwner.PublicID, publicID, newOwner.PublicID,
+				clanAuditSnapshot(beforeTransferClan, previousOwner.PublicID),
+				clanAuditSnapshot(clan, newOwner.PublicID),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Clan ownership transfer audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
 			return nil
 		})
 		if err != nil {
@@ -565,7 +705,7 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 
 		err = WithSegment("hook-dispatch", c, func() error {
 			err = dispatchClanOwnershipChangeHook(
-				app, models.ClanOwnershipTransferredHook,
+				app, tx, models.ClanOwnershipTransferredHook,
 				clan, previousOwner, newOwner,
 			)
 			if err != nil {
@@ -613,17 +753,168 @@ func TransferOwnershipHandler(app *App) func(c echo.Context) error {
 	}
 }
 
+// DisbandClanHandler is the handler responsible for disbanding a clan
+// Clans are disbanded by their owner or, if admin is true, on behalf of the game.
+func DisbandClanHandler(app *App, admin bool) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		route := "DisbandClan"
+		if admin {
+			route = "AdminDisbandClan"
+		}
+		c.Set("route", route)
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("clanPublicID")
+		requestorPublicID := ""
+		if !admin {
+			requestorPublicID = c.QueryParam("requestorPublicID")
+		}
+
+		l := app.Logger.With(
+			zap.String("source", "clanHandler"),
+			zap.String("operation", "disbandClan"),
+			zap.String("gameID", gameID),
+			zap.String("clanPublicID", publicID),
+			zap.String("requestorPublicID", requestorPublicID),
+			zap.Bool("admin", admin),
+		)
+
+		if !admin && requestorPublicID == "" {
+			return FailWith(400, "requestorPublicID is required to disband a clan.", c)
+		}
+
+		var tx interfaces.Transaction
+		var clan *models.Clan
+		var owner *models.Player
+		var members []*models.ClanMember
+		var err error
+
+		//rollback function
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Disbanding clan failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("clan-disband", c, func() error {
+			err = WithSegment("tx-begin", c, func() error {
+				tx, err = app.BeginTrans(c.StdContext(), l)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			log.D(l, "DB Tx begun successful.")
+
+			err = WithSegment("clan-disband-query", c, func() error {
+				log.D(l, "Disbanding clan...")
+				if admin {
+					clan, owner, members, err = models.AdminDisbandClan(tx, gameID, publicID)
+				} else {
+					clan, owner, members, err = models.DisbandClan(tx, gameID, publicID, requestorPublicID)
+				}
+				return err
+			})
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.W(l, "Clan disband failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+
+			before := clanAuditSnapshot(clan, owner.PublicID)
+			before["memberPublicIDs"] = clanMemberPublicIDs(members)
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditClanDisbanded,
+				requestorPublicID, publicID, "",
+				before, nil,
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Clan disband audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+
+			// the hook is written to the outbox in the disband transaction, so
+			// it is only relayed if the clan is disbanded
+			err = WithSegment("hook-dispatch", c, func() error {
+				return dispatchClanDisbandedHook(app, tx, clan, owner, members, requestorPublicID)
+			})
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Clan disband hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		err = app.Commit(tx, "Clan disbanded", c, l)
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		log.I(l, "Clan disbanded successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.String("ownerPublicID", owner.PublicID),
+				zap.Int("membersCount", len(members)),
+				zap.Duration("duration", time.Now().Sub(start)),
+			)
+		})
+		return SucceedWith(map[string]interface{}{}, c)
+	}
+}
+
 // ListClansHandler is the handler responsible for returning a list of all clans
+// Clients that send a cursor or a limit get a page of clans and the cursor of the next page.
 func ListClansHandler(app *App) func(c echo.Context) error {
 	return func(c echo.Context) error {
 		c.Set("route", "ListClans")
 		start := time.Now()
 		gameID := c.Param("gameID")
+		cursorStr := c.QueryParam("cursor")
+		limitStr := c.QueryParam("limit")
+		paginate := cursorStr != "" || limitStr != ""
+
+		limit := app.Config.GetInt("listClans.pageSize")
+		if limitStr != "" {
+			parsedLimit, err := parseLimitString(c, limitStr)
+			if err != nil {
+				return err
+			}
+			limit = parsedLimit
+		}
+		if maxPageSize := app.Config.GetInt("listClans.maxPageSize"); limit > maxPageSize {
+			limit = maxPageSize
+		}
+
+		cursor, err := parseClansCursorString(c, cursorStr)
+		if err != nil {
+			return err
+		}
 
 		l := app.Logger.With(
 			zap.String("source", "clanHandler"),
 			zap.String("operation", "ListClans"),
 			zap.String("gameID", gameID),
+			zap.String("cursor", cursorStr),
+			zap.Int("limit", limit),
 		)
 
 		log.D(l, "Getting DB connection...")
@@ -637,12 +928,17 @@ func ListClansHandler(app *App) func(c echo.Context) error {
 		log.D(l, "DB Connection successful.")
 
 		var clans []models.Clan
+		var nextCursor *models.ClansCursor
 		err = WithSegment("clan-get-all", c, func() error {
 			log.D(l, "Retrieving all clans...")
-			clans, err = models.GetAllClans(
-				db,
-				gameID,
-			)
+			if paginate {
+				clans, nextCursor, err = models.GetClansPage(db, gameID, cursor, limit)
+			} else {
+				clans, err = models.GetAllClans(
+					db,
+					gameID,
+				)
+			}
 
 			if err != nil {
 				log.E(l, "Retrieve all clans failed.", func(cm log.CM) {
@@ -666,9 +962,13 @@ func ListClansHandler(app *App) func(c echo.Context) error {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 
-		return SucceedWith(map[string]interface{}{
+		res := map[string]interface{}{
 			"clans": serializedClans,
-		}, c)
+		}
+		if paginate {
+			res["nextCursor"] = nextCursor.Encode()
+		}
+		return SucceedWith(res, c)
 	}
 }
 
@@ -708,6 +1008,129 @@ func parseFromIndexString(c echo.Context, fromIndexString string) (int, error) {
 	return parsedFromIndex, nil
 }
 
+func parseClansCursorString(c echo.Context, cursorStr string) (*models.ClansCursor, error) {
+	cursor, err := models.DecodeClansCursor(cursorStr)
+	if err != nil {
+		return nil, FailWith(400, err.Error(), c)
+	}
+	return cursor, nil
+}
+
+// searchEngineMongo and searchEngineElasticsearch are the stores clans can be searched in
+const searchEngineMongo = "mongo"
+const searchEngineElasticsearch = "elasticsearch"
+
+// parseOptionalBoolQueryParam returns nil if the param is not in the query string
+func parseOptionalBoolQueryParam(c echo.Context, param string) (*bool, error) {
+	valueStr := c.QueryParam(param)
+	if valueStr == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return nil, &models.InvalidArgumentError{Param: param, Expected: "'true' or 'false'", Got: valueStr}
+	}
+	return &value, nil
+}
+
+// parseOptionalIntQueryParam returns nil if the param is not in the query string
+func parseOptionalIntQueryParam(c echo.Context, param string) (*int, error) {
+	valueStr := c.QueryParam(param)
+	if valueStr == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return nil, &models.InvalidArgumentError{Param: param, Expected: "an integer", Got: valueStr}
+	}
+	return &value, nil
+}
+
+// parseFloatQueryParam returns an error if the param value is not a number
+func parseFloatQueryParam(param, valueStr string) (float64, error) {
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return 0, &models.InvalidArgumentError{Param: param, Expected: "a number", Got: valueStr}
+	}
+	return value, nil
+}
+
+// parseClanSearchFilters returns the clan filters in the query string
+// Metadata filters are given as metadata.<key>=<value>, minMetadata.<key>=<number>
+// and maxMetadata.<key>=<number>.
+func parseClanSearchFilters(c echo.Context) (*models.ClanSearchFilters, error) {
+	filters := &models.ClanSearchFilters{
+		Metadata:    map[string]interface{}{},
+		MinMetadata: map[string]float64{},
+		MaxMetadata: map[string]float64{},
+	}
+	var err error
+	if filters.AllowApplication, err = parseOptionalBoolQueryParam(c, "allowApplication"); err != nil {
+		return nil, err
+	}
+	if filters.AutoJoin, err = parseOptionalBoolQueryParam(c, "autoJoin"); err != nil {
+		return nil, err
+	}
+	if filters.MinMembershipCount, err = parseOptionalIntQueryParam(c, "minMembershipCount"); err != nil {
+		return nil, err
+	}
+	if filters.MaxMembershipCount, err = parseOptionalIntQueryParam(c, "maxMembershipCount"); err != nil {
+		return nil, err
+	}
+
+	for param, values := range c.QueryParams() {
+		if len(values) == 0 {
+			continue
+		}
+		var prefix string
+		for _, p := range []string{"metadata.", "minMetadata.", "maxMetadata."} {
+			if strings.HasPrefix(param, p) {
+				prefix = p
+			}
+		}
+		if prefix == "" {
+			continue
+		}
+		key := strings.TrimPrefix(param, prefix)
+		if key == "" {
+			return nil, &models.InvalidArgumentError{Param: param, Expected: "a metadata key", Got: ""}
+		}
+		switch prefix {
+		case "metadata.":
+			filters.Metadata[key] = values[0]
+		case "minMetadata.":
+			if filters.MinMetadata[key], err = parseFloatQueryParam(param, values[0]); err != nil {
+				return nil, err
+			}
+		case "maxMetadata.":
+			if filters.MaxMetadata[key], err = parseFloatQueryParam(param, values[0]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return filters, nil
+}
+
+// parseClanSearchSort returns the clan search sort in the query string
+// Clans are sorted in descending order by default, except by name.
+func parseClanSearchSort(c echo.Context) (*models.ClanSearchSort, error) {
+	sort := &models.ClanSearchSort{Field: c.QueryParam("sort")}
+	if sort.Field == "" {
+		sort.Field = models.ClanSearchSortByRelevance
+	}
+	switch order := c.QueryParam("order"); order {
+	case "":
+		sort.Ascending = sort.Field == models.ClanSearchSortByName
+	case "asc":
+		sort.Ascending = true
+	case "desc":
+		sort.Ascending = false
+	default:
+		return nil, &models.InvalidArgumentError{Param: "order", Expected: "'asc' or 'desc'", Got: order}
+	}
+	return sort, nil
+}
+
 // SearchClansHandler is the handler responsible for searching for clans
 func SearchClansHandler(app *App) func(c echo.Context) error {
 	return func(c echo.Context) error {
@@ -718,7 +1141,12 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 		useRegexSearchStr := c.QueryParam("useRegexSearch")
 		fromIndexStr := c.QueryParam("from")
 		limitStr := c.QueryParam("limit")
+		cursorStr := c.QueryParam("cursor")
+		engine := c.QueryParam("engine")
 		pageSize := app.Config.GetInt64("search.pageSize")
+		if engine == "" {
+			engine = app.Config.GetString("search.engine")
+		}
 
 		limit := pageSize
 		var err error
@@ -741,11 +1169,18 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 			fromIndex = parsedFromIndex
 		}
 
+		cursor, err := parseClansCursorString(c, cursorStr)
+		if err != nil {
+			return err
+		}
+
 		l := app.Logger.With(
 			zap.String("source", "clanHandler"),
 			zap.String("operation", "SearchClans"),
 			zap.String("gameID", gameID),
 			zap.String("term", term),
+			zap.String("cursor", cursorStr),
+			zap.String("engine", engine),
 		)
 
 		if term == "" {
@@ -753,6 +1188,32 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 			return FailWith(400, (&models.EmptySearchTermError{}).Error(), c)
 		}
 
+		if engine != searchEngineMongo && engine != searchEngineElasticsearch {
+			queryParamErr := &models.InvalidArgumentError{
+				Param:    "engine",
+				Expected: fmt.Sprintf("'%s' or '%s'", searchEngineMongo, searchEngineElasticsearch),
+				Got:      engine,
+			}
+			return FailWith(400, queryParamErr.Error(), c)
+		}
+		if engine == searchEngineElasticsearch && app.ESClient == nil {
+			log.W(l, "Clan search failed since elasticsearch is disabled.")
+			return FailWith(400, "Elasticsearch is not enabled", c)
+		}
+
+		filters, err := parseClanSearchFilters(c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+		hasFreeSlots, err := parseOptionalBoolQueryParam(c, "hasFreeSlots")
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+		sort, err := parseClanSearchSort(c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
 		searchMethod := lib.SearchMethodText
 		if useRegexSearchStr != "" {
 			useRegexSearch, err := strconv.ParseBool(useRegexSearchStr)
@@ -782,18 +1243,74 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 		}
 		log.D(l, "DB Connection successful.")
 
-		var clans []models.Clan
+		// the game is only needed to validate the filters and sort
+		if !filters.Empty() || hasFreeSlots != nil || !sort.Relevance() {
+			var game *models.Game
+			err = WithSegment("game-retrieve", c, func() error {
+				game, err = app.GetGame(c.StdContext(), gameID)
+				if err != nil {
+					log.W(l, "Could not find game.")
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				return FailWith(404, err.Error(), c)
+			}
+
+			if hasFreeSlots != nil {
+				filters.SetHasFreeSlots(game, *hasFreeSlots)
+			}
+			if err = filters.Validate(game); err != nil {
+				log.W(l, "Clan search failed due to invalid filters.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return FailWith(400, err.Error(), c)
+			}
+			if err = sort.Validate(game); err != nil {
+				log.W(l, "Clan search failed due to invalid sort.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return FailWith(400, err.Error(), c)
+			}
+		}
+
+		var hits []models.ClanSearchHit
+		var nextCursor *models.ClansCursor
 		err = WithSegment("clans-search", c, func() error {
 			log.D(l, "Searching clans...")
-			clans, err = models.SearchClan(
-				db,
-				app.MongoDB.WithContext(c.StdContext()),
-				gameID,
-				term,
-				fromIndex,
-				limit,
-				searchMethod,
-			)
+			if engine == searchEngineElasticsearch {
+				hits, nextCursor, err = models.SearchClanES(
+					c.StdContext(),
+					db,
+					app.ESClient,
+					gameID,
+					term,
+					filters,
+					sort,
+					cursor,
+					fromIndex,
+					limit,
+				)
+			} else {
+				var clans []models.Clan
+				clans, nextCursor, err = models.SearchClanPage(
+					db,
+					app.MongoDB.WithContext(c.StdContext()),
+					gameID,
+					term,
+					filters,
+					sort,
+					cursor,
+					fromIndex,
+					limit,
+					searchMethod,
+				)
+				hits = make([]models.ClanSearchHit, len(clans))
+				for i, clan := range clans {
+					hits[i].Clan = clan
+				}
+			}
 
 			if err != nil {
 				log.E(l, "Clan search failed.", func(cm log.CM) {
@@ -809,7 +1326,7 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 
 		var serializedClans []map[string]interface{}
 		WithSegment("response-serialize", c, func() error {
-			serializedClans = serializeClans(clans, true)
+			serializedClans = serializeClanSearchHits(hits)
 			return nil
 		})
 
@@ -818,7 +1335,8 @@ func SearchClansHandler(app *App) func(c echo.Context) error {
 		})
 
 		return SucceedWith(map[string]interface{}{
-			"clans": serializedClans,
+			"clans":      serializedClans,
+			"nextCursor": nextCursor.Encode(),
 		}, c)
 	}
 }
@@ -946,6 +1464,7 @@ func RetrieveClanHandler(app *App) func(c echo.Context) error {
 		log.D(l, "Clan details retrieved successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
+		setVersionETag(c, clan.Version)
 		return SucceedWith(clanResult, c)
 	}
 }
@@ -1139,3 +1658,165 @@ func RetrieveClansSummariesHandler(app *App) func(c echo.Context) error {
 		return SucceedWith(clansResponse, c)
 	}
 }
+
+// RetrieveTopClansHandler is the handler responsible for returning the best ranked clans in a dimension
+func RetrieveTopClansHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrieveTopClans")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		dimension := c.QueryParam("dimension")
+		limitStr := c.QueryParam("limit")
+
+		limit := app.Config.GetInt("topClans.pageSize")
+		if limitStr != "" {
+			parsedLimit, err := parseLimitString(c, limitStr)
+			if err != nil {
+				return err
+			}
+			limit = parsedLimit
+		}
+		if maxPageSize := app.Config.GetInt("topClans.maxPageSize"); limit > maxPageSize {
+			limit = maxPageSize
+		}
+		if dimension == "" {
+			dimension = models.TopClansByMembershipCount
+		}
+
+		l := app.Logger.With(
+			zap.String("source", "clanHandler"),
+			zap.String("operation", "retrieveTopClans"),
+			zap.String("gameID", gameID),
+			zap.String("dimension", dimension),
+			zap.Int("limit", limit),
+		)
+
+		var game *models.Game
+		var err error
+		err = WithSegment("game-retrieve", c, func() error {
+			game, err = app.GetGame(c.StdContext(), gameID)
+			if err != nil {
+				log.W(l, "Could not find game.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(404, err.Error(), c)
+		}
+
+		if !game.IsValidTopClansDimension(dimension) {
+			log.W(l, "Top clans retrieval failed due to invalid dimension.")
+			queryParamErr := &models.InvalidArgumentError{
+				Param:    "dimension",
+				Expected: fmt.Sprintf("one of '%s'", strings.Join(game.GetTopClansDimensions(), "', '")),
+				Got:      dimension,
+			}
+			return FailWith(400, queryParamErr.Error(), c)
+		}
+
+		db := app.Db(c.StdContext())
+
+		var topClans []*models.TopClan
+		err = WithSegment("top-clans-retrieve", c, func() error {
+			log.D(l, "Retrieving top clans...")
+			topClans, err = models.GetTopClans(db, gameID, dimension, limit)
+			if err != nil {
+				log.E(l, "Top clans retrieval failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		serializedClans := make([]map[string]interface{}, len(topClans))
+		for i, topClan := range topClans {
+			serializedClans[i] = topClan.Serialize()
+		}
+
+		log.D(l, "Top clans retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"dimension": dimension,
+			"clans":     serializedClans,
+		}, c)
+	}
+}
+
+// AutocompleteClansHandler is the handler responsible for suggesting clan names that start with a prefix
+func AutocompleteClansHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "AutocompleteClans")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		prefix := c.QueryParam("prefix")
+		limitStr := c.QueryParam("limit")
+
+		limit := app.Config.GetInt("autocomplete.pageSize")
+		if limitStr != "" {
+			parsedLimit, err := parseLimitString(c, limitStr)
+			if err != nil {
+				return err
+			}
+			limit = parsedLimit
+		}
+		if maxPageSize := app.Config.GetInt("autocomplete.maxPageSize"); limit > maxPageSize {
+			limit = maxPageSize
+		}
+
+		l := app.Logger.With(
+			zap.String("source", "clanHandler"),
+			zap.String("operation", "autocompleteClans"),
+			zap.String("gameID", gameID),
+			zap.String("prefix", prefix),
+			zap.Int("limit", limit),
+		)
+
+		if strings.TrimSpace(prefix) == "" {
+			log.W(l, "Clan autocomplete failed due to empty prefix.")
+			return FailWith(400, "A prefix was not provided to autocomplete clan names.", c)
+		}
+		if app.MongoDB == nil {
+			log.W(l, "Clan autocomplete failed since mongodb is disabled.")
+			return FailWith(400, "MongoDB is not enabled", c)
+		}
+
+		var suggestions []models.ClanSuggestion
+		var err error
+		err = WithSegment("clans-autocomplete", c, func() error {
+			log.D(l, "Autocompleting clan names...")
+			suggestions, err = models.AutocompleteClanNames(
+				app.MongoDB.WithContext(c.StdContext()), gameID, prefix, limit,
+			)
+			if err != nil {
+				log.E(l, "Clan autocomplete failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		serializedClans := make([]map[string]interface{}, len(suggestions))
+		for i := range suggestions {
+			serializedClans[i] = suggestions[i].Serialize()
+		}
+
+		log.D(l, "Clan names autocompleted successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"clans": serializedClans,
+		}, c)
+	}
+}
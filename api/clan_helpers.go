@@ -10,12 +10,13 @@ package api
 import (
 	"strings"
 
+	"github.com/labstack/echo"
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/models"
 	"github.com/uber-go/zap"
 )
 
-func dispatchClanOwnershipChangeHook(app *App, hookType int, clan *models.Clan, previousOwner *models.Player, newOwner *models.Player) error {
+func dispatchClanOwnershipChangeHook(app *App, db models.DB, hookType int, clan *models.Clan, previousOwner *models.Player, newOwner *models.Player) error {
 	newOwnerPublicID := ""
 	if newOwner != nil {
 		newOwnerPublicID = newOwner.PublicID
@@ -53,12 +54,96 @@ func dispatchClanOwnershipChangeHook(app *App, hookType int, clan *models.Clan,
 	}
 
 	log.D(l, "Dispatching hook...")
-	app.DispatchHooks(clan.GameID, hookType, result)
+	err := app.DispatchHooks(db, clan.GameID, hookType, result)
+	if err != nil {
+		return err
+	}
 	log.D(l, "Hook dispatch succeeded.")
 
 	return nil
 }
 
+func dispatchClanDisbandedHook(app *App, db models.DB, clan *models.Clan, owner *models.Player, members []*models.ClanMember, requestorPublicID string) error {
+	l := app.Logger.With(
+		zap.String("source", "clanHandler"),
+		zap.String("operation", "dispatchClanDisbandedHook"),
+		zap.String("gameID", clan.GameID),
+		zap.String("clanPublicID", clan.PublicID),
+		zap.String("ownerPublicID", owner.PublicID),
+	)
+
+	ownerJSON := owner.Serialize()
+	delete(ownerJSON, "gameID")
+
+	clanJSON := clan.Serialize()
+	delete(clanJSON, "gameID")
+
+	membersJSON := make([]map[string]interface{}, len(members))
+	for i, member := range members {
+		memberJSON := member.Player.Serialize()
+		delete(memberJSON, "gameID")
+		memberJSON["membershipLevel"] = member.Level
+		membersJSON[i] = memberJSON
+	}
+
+	result := map[string]interface{}{
+		"gameID":    clan.GameID,
+		"clan":      clanJSON,
+		"owner":     ownerJSON,
+		"members":   membersJSON,
+		"requestor": nil,
+	}
+	if requestorPublicID != "" {
+		result["requestor"] = ownerJSON
+	}
+
+	log.D(l, "Dispatching hook...")
+	err := app.DispatchHooks(db, clan.GameID, models.ClanDisbandedHook, result)
+	if err != nil {
+		return err
+	}
+	log.D(l, "Hook dispatch succeeded.")
+
+	return nil
+}
+
+// runClanPreHooks calls the pre hooks of the clan event and updates the clan fields changed by them
+func runClanPreHooks(
+	app *App, c echo.Context, gameID string, eventType int, clanPublicID, ownerPublicID string,
+	name *string, metadata *map[string]interface{}, allowApplication, autoJoin *bool,
+) error {
+	return WithSegment("hook-pre", c, func() error {
+		clan, err := app.RunPreHooks(c.StdContext(), gameID, eventType, map[string]interface{}{
+			"gameID": gameID,
+			"clan": map[string]interface{}{
+				"publicID":         clanPublicID,
+				"ownerPublicID":    ownerPublicID,
+				"name":             *name,
+				"metadata":         *metadata,
+				"allowApplication": *allowApplication,
+				"autoJoin":         *autoJoin,
+			},
+		}, "clan", clanPreHookFields)
+		if err != nil {
+			return err
+		}
+
+		*name = clan["name"].(string)
+		*metadata = clan["metadata"].(map[string]interface{})
+		*allowApplication = clan["allowApplication"].(bool)
+		*autoJoin = clan["autoJoin"].(bool)
+		return nil
+	})
+}
+
+func clanMemberPublicIDs(members []*models.ClanMember) []string {
+	publicIDs := make([]string, len(members))
+	for i, member := range members {
+		publicIDs[i] = member.Player.PublicID
+	}
+	return publicIDs
+}
+
 func serializeClans(clans []models.Clan, includePublicID bool) []map[string]interface{} {
 	serializedClans := make([]map[string]interface{}, len(clans))
 	for i, clan := range clans {
@@ -68,6 +153,18 @@ func serializeClans(clans []models.Clan, includePublicID bool) []map[string]inte
 	return serializedClans
 }
 
+func serializeClanSearchHits(hits []models.ClanSearchHit) []map[string]interface{} {
+	serializedClans := make([]map[string]interface{}, len(hits))
+	for i, hit := range hits {
+		serializedClans[i] = serializeClan(&hit.Clan, true)
+		if len(hit.Highlight) > 0 {
+			serializedClans[i]["highlight"] = hit.Highlight
+		}
+	}
+
+	return serializedClans
+}
+
 func serializeClan(clan *models.Clan, includePublicID bool) map[string]interface{} {
 	serial := map[string]interface{}{
 		"name":             clan.Name,
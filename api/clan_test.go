@@ -25,6 +25,7 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/khan/api"
 	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/queues"
 	"github.com/topfreegames/khan/testing"
 )
 
@@ -124,8 +125,7 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(res.Metadata).To(BeEquivalentTo(metadata))
 		})
 
-		// TODO: fix this when hardcoded boomforce is removed
-		XIt("Should index clan into ES when created", func() {
+		It("Should index clan into ES when created", func() {
 			es := GetTestES()
 			_, player, err := models.CreatePlayerFactory(testDb, "")
 			Expect(err).NotTo(HaveOccurred())
@@ -147,7 +147,7 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(result["success"]).To(BeTrue())
 			Expect(result["publicID"]).To(Equal(clanPublicID))
 
-			indexName := "khan-test"
+			indexName := a.ESClient.GetIndexName(player.GameID)
 
 			var res *elastic.GetResult
 			err = testing.WaitForFunc(10, func() error {
@@ -321,6 +321,114 @@ var _ = Describe("Clan API Handler", func() {
 		})
 	})
 
+	Describe("Disband Clan Handler", func() {
+		It("Should disband a clan if requestor is the owner", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 1, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?requestorPublicID=%s", clan.PublicID, owner.PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			_, err = models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).To(HaveOccurred())
+
+			dbPlayer, err := models.GetPlayerByID(db, players[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.MembershipCount).To(Equal(0))
+
+			dbPlayer, err = models.GetPlayerByID(db, owner.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.OwnershipCount).To(Equal(0))
+
+			events, err := models.GetClanAuditEvents(db, clan.GameID, clan.PublicID, 10, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(models.AuditClanDisbanded))
+			Expect(events[0].ActorPublicID).To(Equal(owner.PublicID))
+			Expect(events[0].Before["memberPublicIDs"]).To(ConsistOf(players[0].PublicID))
+		})
+
+		It("Should write the disband hook to the outbox only if the clan is disbanded", func() {
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			countDisbandHooks := func() int64 {
+				count, err := testDb.SelectInt(
+					"SELECT COUNT(*) FROM outbox WHERE queue=$1 AND args->>'gameID'=$2 AND args->'payload'->'clan'->>'publicID'=$3",
+					queues.KhanQueue, clan.GameID, clan.PublicID,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				return count
+			}
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?requestorPublicID=%s", clan.PublicID, players[0].PublicID))
+			status, _ := Delete(a, route)
+			Expect(status).To(Equal(http.StatusForbidden))
+			Expect(countDisbandHooks()).To(BeEquivalentTo(0))
+
+			route = GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?requestorPublicID=%s", clan.PublicID, owner.PublicID))
+			status, _ = Delete(a, route)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(countDisbandHooks()).To(BeEquivalentTo(1))
+		})
+
+		It("Should not disband a clan without requestor", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Delete(a, GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s", clan.PublicID)))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal("requestorPublicID is required to disband a clan."))
+
+			_, err = models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should disband a clan on behalf of the game", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, _ := Post(a, GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s/admin-disband", clan.PublicID)), "")
+			Expect(status).To(Equal(http.StatusOK))
+
+			_, err = models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should not disband a clan if requestor is not the owner", func() {
+			_, clan, _, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("clans/%s?requestorPublicID=%s", clan.PublicID, players[0].PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusForbidden))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+
+			_, err = models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should not disband a clan if invalid clan", func() {
+			status, body := Delete(a, GetGameRoute("game-id", fmt.Sprintf("clans/%s?requestorPublicID=%s", "random-id", "player-id")))
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(ContainSubstring("Clan was not found with id: random-id"))
+		})
+	})
+
 	Describe("Update Clan Handler", func() {
 		It("Should update clan", func() {
 			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
@@ -416,8 +524,7 @@ var _ = Describe("Clan API Handler", func() {
 			))
 		})
 
-		// TODO: fix this when hardcoded boomforce is removed
-		XIt("Should update ES if update clan", func() {
+		It("Should update ES if update clan", func() {
 			es := GetTestES()
 
 			_, clan, owner, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
@@ -429,7 +536,7 @@ var _ = Describe("Clan API Handler", func() {
 			ownerPublicID := owner.PublicID
 			metadata := map[string]interface{}{"new": "metadata"}
 
-			indexName := "khan-test"
+			indexName := a.ESClient.GetIndexName(gameID)
 			Eventually(func() *elastic.GetResult {
 				res, _ := es.Client.Get().Index(indexName).Type("clan").Id(publicID).Do(context.TODO())
 				return res
@@ -596,6 +703,44 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(result["success"]).To(BeTrue())
 			Expect(len(result["clans"].([]interface{}))).To(Equal(0))
 		})
+
+		It("Should paginate clans with a cursor", func() {
+			player, _, err := models.GetTestClans(testDb, "", "", 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			publicIDs := map[string]bool{}
+			route := GetGameRoute(player.GameID, "/clans?limit=2")
+			status, body := Get(a, route)
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["clans"]).To(HaveLen(2))
+			Expect(result["nextCursor"]).NotTo(BeEmpty())
+			for _, clanObj := range result["clans"].([]interface{}) {
+				publicIDs[clanObj.(map[string]interface{})["publicID"].(string)] = true
+			}
+
+			status, body = Get(a, fmt.Sprintf("%s&cursor=%s", route, result["nextCursor"]))
+			Expect(status).To(Equal(http.StatusOK))
+			result = map[string]interface{}{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["clans"]).To(HaveLen(1))
+			Expect(result["nextCursor"]).To(BeEmpty())
+			for _, clanObj := range result["clans"].([]interface{}) {
+				publicIDs[clanObj.(map[string]interface{})["publicID"].(string)] = true
+			}
+			Expect(publicIDs).To(HaveLen(3))
+		})
+
+		It("Should fail if cursor is invalid", func() {
+			status, body := Get(a, GetGameRoute("game-id", "/clans?cursor=invalid-cursor"))
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(ContainSubstring("Invalid argument for parameter 'cursor'"))
+		})
 	})
 
 	Describe("Retrieve Clan Handler", func() {
@@ -1171,6 +1316,316 @@ var _ = Describe("Clan API Handler", func() {
 				Expect(clan["allowApplication"]).To(Equal(expectedClan.AllowApplication))
 			}
 		})
+
+		Describe("Filters and sort", func() {
+			setClanSearchMetadataKeys := func(gameID, keys string, maxMembers int) {
+				game, err := models.GetGameByPublicID(testDb, gameID)
+				Expect(err).NotTo(HaveOccurred())
+				game.ClanSearchMetadataKeys = keys
+				game.MaxMembers = maxMembers
+				_, err = testDb.Update(game)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			getPublicIDs := func(body string) []string {
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeTrue())
+				publicIDs := []string{}
+				for _, cl := range result["clans"].([]interface{}) {
+					publicIDs = append(publicIDs, cl.(map[string]interface{})["publicID"].(string))
+				}
+				return publicIDs
+			}
+
+			It("Should filter and sort the clans", func() {
+				gameID := uuid.NewV4().String()
+				player, expectedClans, err := models.GetTestClans(testDb, gameID, "clan-apifilter", 4)
+				Expect(err).NotTo(HaveOccurred())
+				setClanSearchMetadataKeys(gameID, "region,trophies", 4)
+
+				err = testing.CreateClanNameTextIndexInMongo(GetTestMongo, gameID)
+				Expect(err).NotTo(HaveOccurred())
+				err = testing.CreateClanSearchIndexesInMongo(GetTestMongo, gameID, []string{"region", "trophies"})
+				Expect(err).NotTo(HaveOccurred())
+				for i, clan := range expectedClans {
+					clan.MembershipCount = i + 1
+					clan.Metadata = map[string]interface{}{"region": "eu", "trophies": i * 10}
+					_, err = testDb.Update(clan)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				route := "clans/search?term=APIFILTER&hasFreeSlots=true&metadata.region=eu&minMetadata.trophies=10&sort=metadata.trophies&order=asc"
+				Eventually(func() []string {
+					status, body := Get(a, GetGameRoute(player.GameID, route))
+					Expect(status).To(Equal(http.StatusOK))
+					return getPublicIDs(body)
+				}).Should(Equal([]string{expectedClans[1].PublicID, expectedClans[2].PublicID}))
+
+				status, body := Get(a, GetGameRoute(player.GameID, route+"&limit=1"))
+				Expect(status).To(Equal(http.StatusOK))
+				Expect(getPublicIDs(body)).To(Equal([]string{expectedClans[1].PublicID}))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				cursor := result["nextCursor"].(string)
+				Expect(cursor).NotTo(BeEmpty())
+
+				status, body = Get(a, GetGameRoute(player.GameID, fmt.Sprintf("%s&limit=1&cursor=%s", route, cursor)))
+				Expect(status).To(Equal(http.StatusOK))
+				Expect(getPublicIDs(body)).To(Equal([]string{expectedClans[2].PublicID}))
+			})
+
+			It("Should fail if a metadata key is not in the game clanSearchMetadataKeys", func() {
+				gameID := uuid.NewV4().String()
+				player, _, err := models.GetTestClans(testDb, gameID, "clan-apifilter-invalid", 1)
+				Expect(err).NotTo(HaveOccurred())
+				setClanSearchMetadataKeys(gameID, "region", 100)
+
+				status, body := Get(a, GetGameRoute(player.GameID, "clans/search?term=clan&metadata.language=en"))
+				Expect(status).To(Equal(http.StatusBadRequest))
+				Expect(body).To(ContainSubstring("metadata.language"))
+
+				status, body = Get(a, GetGameRoute(player.GameID, "clans/search?term=clan&sort=metadata.language"))
+				Expect(status).To(Equal(http.StatusBadRequest))
+				Expect(body).To(ContainSubstring("sort"))
+			})
+
+			It("Should fail if the order is invalid", func() {
+				gameID := uuid.NewV4().String()
+				status, body := Get(a, GetGameRoute(gameID, "clans/search?term=clan&sort=name&order=up"))
+				Expect(status).To(Equal(http.StatusBadRequest))
+				Expect(body).To(ContainSubstring("order"))
+			})
+
+			It("Should fail if the game does not exist", func() {
+				gameID := uuid.NewV4().String()
+				status, _ := Get(a, GetGameRoute(gameID, "clans/search?term=clan&autoJoin=true"))
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Describe("Elasticsearch engine", func() {
+			indexClansIntoES := func(clans []*models.Clan) {
+				for _, clan := range clans {
+					_, err := a.ESClient.Client.Index().
+						Index(a.ESClient.GetIndexName(clan.GameID)).
+						Type("clan").
+						Id(clan.PublicID).
+						BodyJson(clan).
+						Refresh("true").
+						Do(context.TODO())
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+
+			It("Should search for clans with fuzzy matching and highlighting", func() {
+				gameID := uuid.NewV4().String()
+				player, expectedClans, err := models.GetTestClans(testDb, gameID, "clan-elasticsearch", 3)
+				Expect(err).NotTo(HaveOccurred())
+				indexClansIntoES(expectedClans)
+
+				status, body := Get(a, GetGameRoute(player.GameID, "clans/search?term=elastisearch&engine=elasticsearch"))
+				Expect(status).To(Equal(http.StatusOK))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeTrue())
+
+				clans := result["clans"].([]interface{})
+				Expect(clans).To(HaveLen(3))
+				publicIDs := []string{}
+				for _, cl := range clans {
+					clan := cl.(map[string]interface{})
+					publicIDs = append(publicIDs, clan["publicID"].(string))
+					highlight := clan["highlight"].(map[string]interface{})
+					Expect(highlight["name"].([]interface{})[0]).To(ContainSubstring("<em>"))
+				}
+				Expect(publicIDs).To(ConsistOf(
+					expectedClans[0].PublicID, expectedClans[1].PublicID, expectedClans[2].PublicID,
+				))
+			})
+
+			It("Should search for clans with filters", func() {
+				gameID := uuid.NewV4().String()
+				player, expectedClans, err := models.GetTestClans(testDb, gameID, "clan-esfilter", 3)
+				Expect(err).NotTo(HaveOccurred())
+				game, err := models.GetGameByPublicID(testDb, gameID)
+				Expect(err).NotTo(HaveOccurred())
+				game.ClanSearchMetadataKeys = "region"
+				_, err = testDb.Update(game)
+				Expect(err).NotTo(HaveOccurred())
+				for i, clan := range expectedClans {
+					clan.AllowApplication = i > 0
+					clan.MembershipCount = i + 1
+					clan.Metadata = map[string]interface{}{"region": "eu"}
+				}
+				expectedClans[2].Metadata["region"] = "us"
+				indexClansIntoES(expectedClans)
+
+				route := "clans/search?term=esfilter&engine=elasticsearch&allowApplication=true&minMembershipCount=2&metadata.region=eu"
+				status, body := Get(a, GetGameRoute(player.GameID, route))
+				Expect(status).To(Equal(http.StatusOK))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+
+				clans := result["clans"].([]interface{})
+				Expect(clans).To(HaveLen(1))
+				Expect(clans[0].(map[string]interface{})["publicID"]).To(Equal(expectedClans[1].PublicID))
+			})
+
+			It("Should paginate the clans with the cursor", func() {
+				gameID := uuid.NewV4().String()
+				player, expectedClans, err := models.GetTestClans(testDb, gameID, "clan-escursor", 3)
+				Expect(err).NotTo(HaveOccurred())
+				indexClansIntoES(expectedClans)
+
+				publicIDs := []string{}
+				cursor := ""
+				for page := 0; page < 2; page++ {
+					route := fmt.Sprintf("clans/search?term=escursor&engine=elasticsearch&limit=2&cursor=%s", cursor)
+					status, body := Get(a, GetGameRoute(player.GameID, route))
+					Expect(status).To(Equal(http.StatusOK))
+					var result map[string]interface{}
+					json.Unmarshal([]byte(body), &result)
+					for _, cl := range result["clans"].([]interface{}) {
+						publicIDs = append(publicIDs, cl.(map[string]interface{})["publicID"].(string))
+					}
+					cursor = result["nextCursor"].(string)
+				}
+				Expect(cursor).To(Equal(""))
+				Expect(publicIDs).To(ConsistOf(
+					expectedClans[0].PublicID, expectedClans[1].PublicID, expectedClans[2].PublicID,
+				))
+			})
+
+			It("Should fail if the engine is invalid", func() {
+				gameID := uuid.NewV4().String()
+				status, body := Get(a, GetGameRoute(gameID, "clans/search?term=clan&engine=solr"))
+				Expect(status).To(Equal(http.StatusBadRequest))
+				Expect(body).To(ContainSubstring("engine"))
+			})
+
+			It("Should sort the clans", func() {
+				gameID := uuid.NewV4().String()
+				player, expectedClans, err := models.GetTestClans(testDb, gameID, "clan-essort", 3)
+				Expect(err).NotTo(HaveOccurred())
+				for i, clan := range expectedClans {
+					clan.MembershipCount = i + 1
+				}
+				indexClansIntoES(expectedClans)
+
+				route := "clans/search?term=essort&engine=elasticsearch&sort=membershipCount&limit=2"
+				status, body := Get(a, GetGameRoute(player.GameID, route))
+				Expect(status).To(Equal(http.StatusOK))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				clans := result["clans"].([]interface{})
+				Expect(clans).To(HaveLen(2))
+				Expect(clans[0].(map[string]interface{})["publicID"]).To(Equal(expectedClans[2].PublicID))
+				Expect(clans[1].(map[string]interface{})["publicID"]).To(Equal(expectedClans[1].PublicID))
+
+				status, body = Get(a, GetGameRoute(player.GameID, fmt.Sprintf("%s&cursor=%s", route, result["nextCursor"])))
+				Expect(status).To(Equal(http.StatusOK))
+				json.Unmarshal([]byte(body), &result)
+				clans = result["clans"].([]interface{})
+				Expect(clans).To(HaveLen(1))
+				Expect(clans[0].(map[string]interface{})["publicID"]).To(Equal(expectedClans[0].PublicID))
+			})
+		})
+	})
+
+	Describe("Retrieve Top Clans Handler", func() {
+		It("Should retrieve the clans with most members", func() {
+			gameID := uuid.NewV4().String()
+			_, clans, err := models.GetTestClans(testDb, gameID, "", 3)
+			Expect(err).NotTo(HaveOccurred())
+			for i, clan := range clans {
+				clan.MembershipCount = i + 1
+				_, err = testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			var result map[string]interface{}
+			Eventually(func() int {
+				status, body := Get(a, GetGameRoute(gameID, "clans/top?dimension=membershipCount&limit=2"))
+				Expect(status).To(Equal(http.StatusOK))
+				json.Unmarshal([]byte(body), &result)
+				topClans := result["clans"].([]interface{})
+				if len(topClans) == 0 {
+					return 0
+				}
+				return int(topClans[0].(map[string]interface{})["score"].(float64))
+			}).Should(Equal(3))
+
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["dimension"]).To(Equal("membershipCount"))
+			topClans := result["clans"].([]interface{})
+			Expect(topClans).To(HaveLen(2))
+			Expect(topClans[0].(map[string]interface{})["publicID"]).To(Equal(clans[2].PublicID))
+			Expect(topClans[1].(map[string]interface{})["publicID"]).To(Equal(clans[1].PublicID))
+		})
+
+		It("Should fail if dimension is not configured for the game", func() {
+			gameID := uuid.NewV4().String()
+			_, _, err := models.GetTestClans(testDb, gameID, "", 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(gameID, "clans/top?dimension=metadata.trophies"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(ContainSubstring("dimension"))
+		})
+
+		It("Should fail if game does not exist", func() {
+			status, _ := Get(a, GetGameRoute(uuid.NewV4().String(), "clans/top"))
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Autocomplete Clans Handler", func() {
+		It("Should suggest the clans whose names start with the prefix", func() {
+			gameID := uuid.NewV4().String()
+			_, clans, err := models.GetTestClans(testDb, gameID, "", 2)
+			Expect(err).NotTo(HaveOccurred())
+			err = testing.CreateClanNamePrefixesIndexInMongo(GetTestMongo, gameID)
+			Expect(err).NotTo(HaveOccurred())
+			for i, clan := range clans {
+				clan.Name = fmt.Sprintf("Águias %d", i)
+				clan.MembershipCount = i + 1
+				_, err = testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			var suggestions []interface{}
+			Eventually(func() []interface{} {
+				status, body := Get(a, GetGameRoute(gameID, "clans/autocomplete?prefix=agu"))
+				Expect(status).To(Equal(http.StatusOK))
+				var result map[string]interface{}
+				json.Unmarshal([]byte(body), &result)
+				Expect(result["success"]).To(BeTrue())
+				suggestions = result["clans"].([]interface{})
+				return suggestions
+			}).Should(HaveLen(2))
+
+			suggestion := suggestions[0].(map[string]interface{})
+			Expect(suggestion["publicID"]).To(Equal(clans[1].PublicID))
+			Expect(suggestion["name"]).To(Equal("Águias 1"))
+			Expect(suggestion["membershipCount"]).To(BeEquivalentTo(2))
+			Expect(suggestion).NotTo(HaveKey("metadata"))
+
+			status, body := Get(a, GetGameRoute(gameID, "clans/autocomplete?prefix=AGUIAS&limit=1"))
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["clans"]).To(HaveLen(1))
+		})
+
+		It("Should fail if the prefix is empty", func() {
+			status, body := Get(a, GetGameRoute(uuid.NewV4().String(), "clans/autocomplete?prefix="))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(body).To(ContainSubstring("prefix"))
+		})
 	})
 
 	Describe("Clan Hooks", func() {
@@ -1650,6 +2105,49 @@ var _ = Describe("Clan API Handler", func() {
 			Expect(rClan["newOwner"]).To(BeNil())
 		})
 
+		It("Should call disband clan hook with the former roster", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/clandisband",
+			}, models.ClanDisbandedHook)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/clandisband"}, 52525)
+
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 1, hooks[0].GameID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s?requestorPublicID=%s", clan.PublicID, owner.PublicID))
+			status, body := Delete(a, route)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+
+			rClan := (*responses)[0]["payload"].(map[string]interface{})
+			Expect(rClan["gameID"]).To(Equal(hooks[0].GameID))
+			Expect(rClan["type"].(float64)).To(BeEquivalentTo(15))
+
+			clanDetails := rClan["clan"].(map[string]interface{})
+			Expect(clanDetails["publicID"]).To(Equal(clan.PublicID))
+			Expect(clanDetails["membershipCount"]).To(BeEquivalentTo(0))
+
+			ownerDetails := rClan["owner"].(map[string]interface{})
+			Expect(ownerDetails["publicID"]).To(Equal(owner.PublicID))
+			Expect(ownerDetails["ownershipCount"]).To(BeEquivalentTo(0))
+			Expect(rClan["requestor"].(map[string]interface{})["publicID"]).To(Equal(owner.PublicID))
+
+			members := rClan["members"].([]interface{})
+			Expect(members).To(HaveLen(1))
+			member := members[0].(map[string]interface{})
+			Expect(member["publicID"]).To(Equal(players[0].PublicID))
+			Expect(member["membershipLevel"]).To(Equal("Member"))
+			Expect(member["membershipCount"]).To(BeEquivalentTo(0))
+		})
+
 		It("Should call transfer ownership hook", func() {
 			hooks, err := models.GetHooksForRoutes(testDb, []string{
 				"http://localhost:52525/clantransfer",
@@ -0,0 +1,150 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// ReconcileCounts recomputes the clans membership counts and the players membership and
+// ownership counts of the game from its memberships and clans, fixing the wrong ones
+// Each batch of batchSize clans or players is fixed in its own transaction, along with
+// the search index updates of the fixed clans.
+func (app *App) ReconcileCounts(ctx context.Context, gameID string, batchSize int) (*models.CountsReconciliation, error) {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "ReconcileCounts"),
+		zap.String("gameID", gameID),
+	)
+
+	reconciliation := models.NewCountsReconciliation(gameID)
+	var afterID int64
+	for {
+		var changes []*models.CounterChange
+		var count int
+		err := app.withTransaction(ctx, l, func(tx models.DB) error {
+			var err error
+			changes, count, afterID, err = models.ReconcileClanMembershipCounts(tx, gameID, afterID, batchSize)
+			return err
+		})
+		if err != nil {
+			return reconciliation, err
+		}
+		if count == 0 {
+			break
+		}
+		reconciliation.Clans += count
+		reconciliation.ClanMembershipCounts = append(reconciliation.ClanMembershipCounts, changes...)
+		log.D(l, "Clan counts reconciled.", func(cm log.CM) {
+			cm.Write(zap.Int("clans", reconciliation.Clans), zap.Int("fixed", len(changes)))
+		})
+	}
+
+	afterID = 0
+	for {
+		var membershipChanges, ownershipChanges []*models.CounterChange
+		var count int
+		err := app.withTransaction(ctx, l, func(tx models.DB) error {
+			var err error
+			membershipChanges, ownershipChanges, count, afterID, err = models.ReconcilePlayerCounts(tx, gameID, afterID, batchSize)
+			return err
+		})
+		if err != nil {
+			return reconciliation, err
+		}
+		if count == 0 {
+			break
+		}
+		reconciliation.Players += count
+		reconciliation.PlayerMembershipCounts = append(reconciliation.PlayerMembershipCounts, membershipChanges...)
+		reconciliation.PlayerOwnershipCounts = append(reconciliation.PlayerOwnershipCounts, ownershipChanges...)
+		log.D(l, "Player counts reconciled.", func(cm log.CM) {
+			cm.Write(
+				zap.Int("players", reconciliation.Players),
+				zap.Int("fixedMemberships", len(membershipChanges)),
+				zap.Int("fixedOwnerships", len(ownershipChanges)),
+			)
+		})
+	}
+
+	log.I(l, "Game counts reconciled.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("clans", reconciliation.Clans),
+			zap.Int("players", reconciliation.Players),
+			zap.Int("fixedClanMemberships", len(reconciliation.ClanMembershipCounts)),
+			zap.Int("fixedPlayerMemberships", len(reconciliation.PlayerMembershipCounts)),
+			zap.Int("fixedPlayerOwnerships", len(reconciliation.PlayerOwnershipCounts)),
+		)
+	})
+	return reconciliation, nil
+}
+
+// withTransaction runs f in a transaction, committed if f succeeds and rolled back otherwise
+func (app *App) withTransaction(ctx context.Context, l zap.Logger, f func(tx models.DB) error) error {
+	tx, err := app.BeginTrans(ctx, l)
+	if err != nil {
+		return err
+	}
+	if err = f(tx); err != nil {
+		if txErr := tx.Rollback(); txErr != nil {
+			log.E(l, "Failed to rollback transaction.", func(cm log.CM) {
+				cm.Write(zap.Error(txErr), zap.String("originalError", err.Error()))
+			})
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReconcileCountsHandler is the handler responsible for fixing the membership and ownership counts of a game
+func ReconcileCountsHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ReconcileCounts")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "ReconcileCountsHandler"),
+			zap.String("operation", "reconcileCounts"),
+			zap.String("gameID", gameID),
+		)
+
+		_, err := app.GetGame(c.StdContext(), gameID)
+		if err != nil {
+			log.W(l, "Could not find game.")
+			return FailWith(404, err.Error(), c)
+		}
+
+		var reconciliation *models.CountsReconciliation
+		err = WithSegment("counts-reconcile", c, func() error {
+			log.D(l, "Reconciling counts...")
+			reconciliation, err = app.ReconcileCounts(c.StdContext(), gameID, app.Config.GetInt("reconcileCounts.batchSize"))
+			if err != nil {
+				log.E(l, "Failed to reconcile counts.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
+		log.I(l, "Counts reconciled successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(reconciliation.Serialize(), c)
+	}
+}
@@ -0,0 +1,72 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Counts Reconciliation API Handler", func() {
+	var db models.DB
+	var a *api.App
+
+	BeforeEach(func() {
+		a = GetDefaultTestApp()
+		db = a.Db(nil)
+		a.NonblockingStartWorkers()
+	})
+
+	Describe("Reconcile Counts Handler", func() {
+		It("Should fix the wrong counts of the game", func() {
+			game, clan, owner, _, _, err := models.GetClanWithMemberships(db, 2, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec("UPDATE clans SET membership_count=0 WHERE id=$1", clan.ID)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec("UPDATE players SET ownership_count=3 WHERE id=$1", owner.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Post(a, fmt.Sprintf("/games/%s/reconcile-counts", game.PublicID), "")
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["gameID"]).To(Equal(game.PublicID))
+			Expect(result["clans"]).To(BeEquivalentTo(1))
+			Expect(result["players"]).To(BeEquivalentTo(3))
+			Expect(result["clanMembershipCounts"]).To(Equal([]interface{}{
+				map[string]interface{}{"publicID": clan.PublicID, "previous": float64(0), "current": float64(3)},
+			}))
+			Expect(result["playerMembershipCounts"]).To(BeEmpty())
+			Expect(result["playerOwnershipCounts"]).To(Equal([]interface{}{
+				map[string]interface{}{"publicID": owner.PublicID, "previous": float64(3), "current": float64(1)},
+			}))
+
+			dbClan, err := models.GetClanByID(db, clan.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.MembershipCount).To(Equal(3))
+		})
+
+		It("Should fail with 404 if game does not exist", func() {
+			status, body := Post(a, fmt.Sprintf("/games/%s/reconcile-counts", uuid.NewV4().String()), "")
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+	})
+})
@@ -10,7 +10,10 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,6 +21,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +32,7 @@ import (
 	ehttp "github.com/topfreegames/extensions/http"
 	"github.com/topfreegames/extensions/tracing"
 	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
 	"github.com/topfreegames/khan/queues"
 	"github.com/uber-go/zap"
 	"github.com/valyala/fasttemplate"
@@ -38,6 +43,14 @@ var httpClient *http.Client
 
 const hookInternalFailures = "hook_internal_failures"
 const requestingHookMilliseconds = "requesting_hook_milliseconds"
+const hookRetries = "hook_retries"
+const hookDeadLetters = "hook_dead_letters"
+
+//HookSignatureHeader carries the HMAC-SHA256 signature of deliveries to hooks with a secret
+const HookSignatureHeader = "X-Khan-Signature"
+
+//HookTimestampHeader carries the unix timestamp (in seconds) of the delivery
+const HookTimestampHeader = "X-Khan-Timestamp"
 
 //Dispatcher is responsible for sending web hooks to workers
 type Dispatcher struct {
@@ -94,20 +107,21 @@ func (d *Dispatcher) configureHTTPClient() {
 }
 
 //DispatchHook dispatches an event hook for eventType to gameID with the specified payload
-func (d *Dispatcher) DispatchHook(gameID string, eventType int, payload map[string]interface{}) {
+//The event is written to the outbox of db, so it is only dispatched if db is committed.
+func (d *Dispatcher) DispatchHook(db models.DB, gameID string, eventType int, payload map[string]interface{}) error {
 	payload["type"] = eventType
 	payload["id"] = uuid.NewV4()
 	payload["timestamp"] = time.Now().Format(time.RFC3339)
 
-	// Push the work onto the queue.
-	log.D(d.app.Logger, "Pushing work into dispatch queue.", func(cm log.CM) {
+	// Push the work onto the outbox.
+	log.D(d.app.Logger, "Pushing work into dispatch outbox.", func(cm log.CM) {
 		cm.Write(
 			zap.String("source", "dispatcher"),
 			zap.String("operation", "DispatchHook"),
 		)
 	})
 
-	workers.Enqueue(queues.KhanQueue, "Add", map[string]interface{}{
+	return models.EnqueueOutbox(db, queues.KhanQueue, map[string]interface{}{
 		"gameID":    gameID,
 		"eventType": eventType,
 		"payload":   payload,
@@ -165,160 +179,376 @@ func (d *Dispatcher) PerformDispatchHook(m *workers.Msg) {
 	defer tracing.LogPanic(span)
 	ctx := opentracing.ContextWithSpan(context.Background(), span)
 	app := d.app
-	statsd := app.DDStatsD
 
 	item := m.Args()
 	data := item.MustMap()
 
 	gameID := data["gameID"].(string)
 	eventType, _ := data["eventType"].(json.Number).Int64()
-	payload := data["payload"].(map[string]interface{})
+	payload, _ := data["payload"].(map[string]interface{})
+
+	// retries, replays and batch flushes target a single hook
+	hookPublicID, _ := data["hookPublicID"].(string)
+	var attempt int64
+	if val, ok := data["attempt"].(json.Number); ok {
+		attempt, _ = val.Int64()
+	}
+	batch, _ := data["batch"].([]interface{})
+	batchID, _ := data["batchID"].(string)
+	flush, _ := data["flush"].(bool)
 
 	l := d.app.Logger.With(
 		zap.String("source", "dispatcher"),
 		zap.String("operation", "PerformDispatchHook"),
 		zap.String("gameID", gameID),
 		zap.Int64("eventType", eventType),
+		zap.String("hookPublicID", hookPublicID),
+		zap.Int64("attempt", attempt),
 	)
 
-	hooks := app.GetHooks(ctx)
-	if _, ok := hooks[gameID]; !ok {
-		log.D(l, "No hooks found for game.")
-		return
-	}
-	if _, ok := hooks[gameID][int(eventType)]; !ok {
+	hooks := app.GetHooks(ctx, gameID, int(eventType))
+	if len(hooks) == 0 {
 		log.D(l, "No hooks found for event in specified game.")
 		return
 	}
 
-	for _, hook := range hooks[gameID][int(eventType)] {
-		log.D(app.Logger, "Sending webhook...", func(cm log.CM) {
-			cm.Write(zap.String("url", hook.URL))
-		})
+	for _, hook := range hooks {
+		// pre hooks are called by the handlers before the action happens
+		if hook.Pre() {
+			continue
+		}
 
-		requestURL, err := d.interpolateURL(hook.URL, payload)
-		if err != nil {
-			app.addError()
-			tags := []string{
-				"error:true",
-				fmt.Sprintf("url:%s", hook.URL),
-				fmt.Sprintf("game:%s", gameID),
-			}
-			statsd.Increment(hookInternalFailures, tags...)
+		if hookPublicID != "" && hook.PublicID != hookPublicID {
+			continue
+		}
 
-			log.E(l, "Could not interpolate webhook.", func(cm log.CM) {
-				cm.Write(
-					zap.String("requestURL", hook.URL),
-					zap.Error(err),
-				)
-			})
+		if flush {
+			d.flushHookBatch(ctx, l, gameID, int(eventType), hook)
 			continue
 		}
 
-		payloadJSON, _ := json.Marshal(payload)
+		if batch != nil {
+			d.sendHookBatch(ctx, l, gameID, int(eventType), hook, batch, int(attempt), batchID)
+			continue
+		}
 
-		log.D(l, "Requesting Hook URL...", func(cm log.CM) {
-			cm.Write(zap.String("requestURL", requestURL))
-		})
+		if !d.matchHookFilter(l, gameID, hook, payload) {
+			continue
+		}
 
-		req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(payloadJSON))
-		if err != nil {
-			log.E(l, "failed to create webhook request", func(cm log.CM) {
-				cm.Write(
-					zap.String("requestURL", hook.URL),
-					zap.Error(err),
-				)
-			})
+		if hookPublicID == "" && hook.Batched() {
+			d.addToHookBatch(ctx, l, gameID, int(eventType), hook, payload)
 			continue
 		}
-		req.Header.Set("Content-Type", "application/json")
-		req = req.WithContext(ctx)
 
-		parsedURL, err := url.Parse(requestURL)
+		err := d.sendHook(ctx, l, gameID, hook, payload, payload)
 		if err != nil {
-			app.addError()
-			tags := []string{
-				"error:true",
-				fmt.Sprintf("url:%s", hook.URL),
-				fmt.Sprintf("game:%s", gameID),
-			}
-			statsd.Increment(hookInternalFailures, tags...)
+			d.retryOrDeadLetterHook(ctx, l, gameID, int(eventType), hook, payload, nil, "", int(attempt), err)
+		}
+	}
 
-			log.E(l, "Could not parse request requestURL.", func(cm log.CM) {
-				cm.Write(
-					zap.String(requestURL, hook.URL),
-					zap.Error(err),
-				)
-			})
+	return
+}
+
+// matchHookFilter returns whether the payload satisfies the hook filter, if any
+func (d *Dispatcher) matchHookFilter(l zap.Logger, gameID string, hook *models.Hook, payload map[string]interface{}) bool {
+	matched, err := hook.MatchFilter(payload)
+	if err != nil {
+		d.app.addError()
+		tags := []string{
+			"error:true",
+			fmt.Sprintf("url:%s", hook.URL),
+			fmt.Sprintf("game:%s", gameID),
 		}
-		if parsedURL.User != nil {
-			username := parsedURL.User.Username()
-			password, setten := parsedURL.User.Password()
-			if setten == false {
-				password = ""
-			}
-			requestURL = fmt.Sprintf("%s://%s%s", parsedURL.Scheme, parsedURL.Host, parsedURL.RequestURI())
-			req.SetBasicAuth(username, password)
+		d.app.DDStatsD.Increment(hookInternalFailures, tags...)
+
+		log.E(l, "Could not parse webhook filter.", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hook.URL),
+				zap.Error(err),
+			)
+		})
+		return false
+	}
+
+	if !matched {
+		log.D(l, "Webhook filter did not match payload.", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hook.URL),
+				zap.String("filter", hook.Filter),
+			)
+		})
+		return false
+	}
+	return true
+}
+
+// sendHook requests the hook URL, interpolated with the given payload, with
+// the JSON encoded body. Only delivery failures are returned, since they are
+// the only ones worth retrying.
+func (d *Dispatcher) sendHook(
+	ctx context.Context, l zap.Logger, gameID string, hook *models.Hook,
+	payload map[string]interface{}, body interface{},
+) error {
+	app := d.app
+	statsd := app.DDStatsD
+
+	log.D(app.Logger, "Sending webhook...", func(cm log.CM) {
+		cm.Write(zap.String("url", hook.URL))
+	})
+
+	requestURL, err := d.interpolateURL(hook.URL, payload)
+	if err != nil {
+		app.addError()
+		tags := []string{
+			"error:true",
+			fmt.Sprintf("url:%s", hook.URL),
+			fmt.Sprintf("game:%s", gameID),
 		}
+		statsd.Increment(hookInternalFailures, tags...)
 
-		start := time.Now()
-		resp, err := d.httpClient.Do(req)
-		if err != nil {
-			app.addError()
-			tags := []string{
-				"error:true",
-				fmt.Sprintf("url:%s", hook.URL),
-				fmt.Sprintf("game:%s", gameID),
-				fmt.Sprintf("status:500"),
-			}
-			elapsed := time.Since(start)
-			statsd.Timing(requestingHookMilliseconds, elapsed, tags...)
-			statsd.Increment(hookInternalFailures, tags...)
+		log.E(l, "Could not interpolate webhook.", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hook.URL),
+				zap.Error(err),
+			)
+		})
+		return nil
+	}
 
-			log.E(l, "Could not request webhook.", func(cm log.CM) {
-				cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
-			})
-			continue
+	payloadJSON, _ := json.Marshal(body)
+
+	log.D(l, "Requesting Hook URL...", func(cm log.CM) {
+		cm.Write(zap.String("requestURL", requestURL))
+	})
+
+	req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		log.E(l, "failed to create webhook request", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hook.URL),
+				zap.Error(err),
+			)
+		})
+		return nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HookTimestampHeader, timestamp)
+	if hook.Secret != "" {
+		req.Header.Set(HookSignatureHeader, signHookPayload(hook.Secret, timestamp, payloadJSON))
+	}
+	req = req.WithContext(ctx)
+
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		app.addError()
+		tags := []string{
+			"error:true",
+			fmt.Sprintf("url:%s", hook.URL),
+			fmt.Sprintf("game:%s", gameID),
 		}
-		defer resp.Body.Close()
+		statsd.Increment(hookInternalFailures, tags...)
 
-		body, respErr := ioutil.ReadAll(resp.Body)
-		if respErr != nil {
-			log.E(l, "failed to read webhook response", func(cm log.CM) {
-				cm.Write(zap.String("requestURL", hook.URL), zap.Error(respErr))
-			})
-			continue
+		log.E(l, "Could not parse request requestURL.", func(cm log.CM) {
+			cm.Write(
+				zap.String(requestURL, hook.URL),
+				zap.Error(err),
+			)
+		})
+	}
+	if parsedURL.User != nil {
+		username := parsedURL.User.Username()
+		password, setten := parsedURL.User.Password()
+		if setten == false {
+			password = ""
 		}
+		requestURL = fmt.Sprintf("%s://%s%s", parsedURL.Scheme, parsedURL.Host, parsedURL.RequestURI())
+		req.SetBasicAuth(username, password)
+	}
 
+	start := time.Now()
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		app.addError()
 		tags := []string{
-			fmt.Sprintf("error:%t", resp.StatusCode > 399),
+			"error:true",
 			fmt.Sprintf("url:%s", hook.URL),
 			fmt.Sprintf("game:%s", gameID),
-			fmt.Sprintf("status:%d", resp.StatusCode),
+			fmt.Sprintf("status:500"),
 		}
 		elapsed := time.Since(start)
 		statsd.Timing(requestingHookMilliseconds, elapsed, tags...)
+		statsd.Increment(hookInternalFailures, tags...)
+
+		log.E(l, "Could not request webhook.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, respErr := ioutil.ReadAll(resp.Body)
+	if respErr != nil {
+		log.E(l, "failed to read webhook response", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(respErr))
+		})
+		return respErr
+	}
 
-		if resp.StatusCode > 399 {
-			app.addError()
-			log.E(l, "Could not request webhook.", func(cm log.CM) {
+	tags := []string{
+		fmt.Sprintf("error:%t", resp.StatusCode > 399),
+		fmt.Sprintf("url:%s", hook.URL),
+		fmt.Sprintf("game:%s", gameID),
+		fmt.Sprintf("status:%d", resp.StatusCode),
+	}
+	elapsed := time.Since(start)
+	statsd.Timing(requestingHookMilliseconds, elapsed, tags...)
+
+	if resp.StatusCode > 399 {
+		app.addError()
+		log.E(l, "Could not request webhook.", func(cm log.CM) {
+			cm.Write(
+				zap.String("requestURL", hook.URL),
+				zap.Int("statusCode", resp.StatusCode),
+				zap.String("body", string(respBody)),
+			)
+		})
+		return fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	log.D(l, "Webhook requested successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("statusCode", resp.StatusCode),
+			zap.String("requestURL", requestURL),
+			zap.String("body", string(respBody)),
+		)
+	})
+	return nil
+}
+
+// signHookPayload returns the signature of the timestamp and body of a delivery
+// in the "sha256=<hex digest>" format
+func signHookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// retryDelay returns the exponential backoff for the given attempt
+func (d *Dispatcher) retryDelay(attempt int) time.Duration {
+	backoff := time.Duration(d.app.Config.GetInt("webhooks.retryBackoff")) * time.Millisecond
+	maxBackoff := time.Duration(d.app.Config.GetInt("webhooks.maxRetryBackoff")) * time.Millisecond
+
+	delay := backoff << uint(attempt)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// retryOrDeadLetterHook schedules a new delivery of a failed hook or, if the
+// retry budget is exhausted, stores it as a dead letter. Batches are retried
+// as a unit. It returns whether a retry was scheduled.
+func (d *Dispatcher) retryOrDeadLetterHook(
+	ctx context.Context, l zap.Logger, gameID string, eventType int,
+	hook *models.Hook, payload map[string]interface{}, batch []interface{},
+	batchID string, attempt int, hookErr error,
+) bool {
+	app := d.app
+	statsd := app.DDStatsD
+	tags := []string{
+		fmt.Sprintf("url:%s", hook.URL),
+		fmt.Sprintf("game:%s", gameID),
+	}
+
+	maxRetries := app.Config.GetInt("webhooks.maxRetries")
+	if attempt < maxRetries {
+		delay := d.retryDelay(attempt)
+		args := map[string]interface{}{
+			"gameID":       gameID,
+			"eventType":    eventType,
+			"hookPublicID": hook.PublicID,
+			"attempt":      attempt + 1,
+		}
+		if batch != nil {
+			args["batch"] = batch
+			args["batchID"] = batchID
+		} else {
+			args["payload"] = payload
+		}
+		_, err := workers.EnqueueIn(queues.KhanQueue, "Add", delay.Seconds(), args)
+		if err == nil {
+			statsd.Increment(hookRetries, tags...)
+			log.I(l, "Webhook retry scheduled.", func(cm log.CM) {
 				cm.Write(
 					zap.String("requestURL", hook.URL),
-					zap.Int("statusCode", resp.StatusCode),
-					zap.String("body", string(body)),
+					zap.Duration("delay", delay),
 				)
 			})
-			continue
+			return true
 		}
+		log.E(l, "Failed to schedule webhook retry.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+	}
 
-		log.D(l, "Webhook requested successfully.", func(cm log.CM) {
-			cm.Write(
-				zap.Int("statusCode", resp.StatusCode),
-				zap.String("requestURL", requestURL),
-				zap.String("body", string(body)),
-			)
+	if batch != nil {
+		payload = map[string]interface{}{"events": batch}
+	}
+	deadLetter, err := models.CreateHookDeadLetter(
+		app.Db(ctx), gameID, hook.PublicID, eventType, hook.URL, payload, batch != nil, hookErr.Error(), attempt+1,
+	)
+	if err != nil {
+		app.addError()
+		statsd.Increment(hookInternalFailures, append(tags, "error:true")...)
+		log.E(l, "Failed to store webhook dead letter.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
 		})
+		return false
 	}
 
-	return
+	statsd.Increment(hookDeadLetters, tags...)
+	log.W(l, "Webhook retries exhausted. Dead letter stored.", func(cm log.CM) {
+		cm.Write(
+			zap.String("requestURL", hook.URL),
+			zap.String("deadLetterPublicID", deadLetter.PublicID),
+			zap.Error(hookErr),
+		)
+	})
+	return false
+}
+
+// ReplayHookDeadLetter enqueues a new delivery of a dead letter to its hook and removes it
+func (d *Dispatcher) ReplayHookDeadLetter(ctx context.Context, gameID, publicID string) error {
+	db := d.app.Db(ctx)
+	deadLetter, err := models.GetHookDeadLetterByPublicID(db, gameID, publicID)
+	if err != nil {
+		return err
+	}
+
+	_, err = models.GetHookByPublicID(db, gameID, deadLetter.HookPublicID)
+	if err != nil {
+		return err
+	}
+
+	args := map[string]interface{}{
+		"gameID":       gameID,
+		"eventType":    deadLetter.EventType,
+		"hookPublicID": deadLetter.HookPublicID,
+		"attempt":      0,
+	}
+	if deadLetter.Batched {
+		args["batch"] = deadLetter.Payload["events"]
+	} else {
+		args["payload"] = deadLetter.Payload
+	}
+	_, err = workers.Enqueue(queues.KhanQueue, "Add", args)
+	if err != nil {
+		return err
+	}
+
+	return models.RemoveHookDeadLetter(db, gameID, publicID)
 }
@@ -9,10 +9,12 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo"
+	"github.com/topfreegames/extensions/gorp/interfaces"
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/models"
 	"github.com/uber-go/zap"
@@ -77,6 +79,8 @@ func CreateGameHandler(app *App) func(c echo.Context) error {
 			false,
 			optional.clanUpdateMetadataFieldsHookTriggerWhitelist,
 			optional.playerUpdateMetadataFieldsHookTriggerWhitelist,
+			optional.topClansMetadataDimensions,
+			optional.clanSearchMetadataKeys,
 		)
 
 		if err != nil {
@@ -103,8 +107,6 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 		start := time.Now()
 		gameID := c.Param("gameID")
 
-		db := app.Db(c.StdContext())
-
 		l := app.Logger.With(
 			zap.String("source", "gameHandler"),
 			zap.String("operation", "updateGame"),
@@ -155,10 +157,42 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 			return FailWith(status, err.Error(), c)
 		}
 
+		version, checkVersion, err := getIfMatchVersion(c)
+		if err != nil {
+			return FailWith(400, err.Error(), c)
+		}
+
+		var game *models.Game
+		var tx interfaces.Transaction
+
+		//rollback function
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Updating game failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("tx-begin", c, func() error {
+			tx, err = app.BeginTrans(c.StdContext(), l)
+			return err
+		})
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
 		err = WithSegment("game-update", c, func() error {
+			if checkVersion {
+				log.D(l, "Checking game version...")
+				if err = models.CheckGameVersion(tx, gameID, version); err != nil {
+					return err
+				}
+			}
 			log.D(l, "Updating game...")
-			_, err = models.UpdateGame(
-				db,
+			game, err = models.UpdateGame(
+				tx,
 				gameID,
 				payload.Name,
 				payload.MembershipLevels,
@@ -178,15 +212,20 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 				optional.maxPendingInvites,
 				optional.clanUpdateMetadataFieldsHookTriggerWhitelist,
 				optional.playerUpdateMetadataFieldsHookTriggerWhitelist,
+				optional.topClansMetadataDimensions,
+				optional.clanSearchMetadataKeys,
 			)
 			return err
 		})
 
 		if err != nil {
-			log.E(l, "Game update failed.", func(cm log.CM) {
-				cm.Write(zap.Error(err))
-			})
-			return FailWith(500, err.Error(), c)
+			txErr := rb(err)
+			if txErr == nil {
+				log.E(l, "Game update failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+			}
+			return FailWithError(err, c)
 		}
 
 		successPayload := map[string]interface{}{
@@ -210,11 +249,14 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 		}
 
 		err = WithSegment("hook-dispatch", c, func() error {
-			dErr := app.DispatchHooks(gameID, models.GameUpdatedHook, successPayload)
+			dErr := app.DispatchHooks(tx, gameID, models.GameUpdatedHook, successPayload)
 			if dErr != nil {
-				log.E(l, "Game update hook dispatch failed.", func(cm log.CM) {
-					cm.Write(zap.Error(dErr))
-				})
+				txErr := rb(dErr)
+				if txErr == nil {
+					log.E(l, "Game update hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(dErr))
+					})
+				}
 				return dErr
 			}
 			return nil
@@ -223,10 +265,118 @@ func UpdateGameHandler(app *App) func(c echo.Context) error {
 			return FailWith(500, err.Error(), c)
 		}
 
+		err = app.Commit(tx, "Game updated", c, l)
+		if err != nil {
+			return FailWith(500, err.Error(), c)
+		}
+
 		log.I(l, "Game updated succesfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 
-		return SucceedWith(map[string]interface{}{}, c)
+		setVersionETag(c, game.Version)
+		return SucceedWith(map[string]interface{}{
+			"version": game.Version,
+		}, c)
+	}
+}
+
+//RetrieveGameHandler is the handler responsible for returning the configuration of a given game
+func RetrieveGameHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrieveGame")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "gameHandler"),
+			zap.String("operation", "retrieveGame"),
+			zap.String("gameID", gameID),
+		)
+
+		log.D(l, "Getting DB connection...")
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+		log.D(l, "DB Connection successful.")
+
+		var game *models.Game
+		err = WithSegment("game-retrieve", c, func() error {
+			log.D(l, "Retrieving game...")
+			game, err = models.GetGameByPublicID(db, gameID)
+			if err != nil {
+				log.E(l, "Retrieve game failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.D(l, "Game retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		setVersionETag(c, game.Version)
+		return SucceedWith(game.Serialize(), c)
+	}
+}
+
+//ListGamesHandler is the handler responsible for returning the configuration of all games
+func ListGamesHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListGames")
+		start := time.Now()
+
+		l := app.Logger.With(
+			zap.String("source", "gameHandler"),
+			zap.String("operation", "listGames"),
+		)
+
+		log.D(l, "Getting DB connection...")
+		db, err := app.GetCtxDB(c)
+		if err != nil {
+			log.E(l, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+		log.D(l, "DB Connection successful.")
+
+		var games []*models.Game
+		err = WithSegment("game-get-all", c, func() error {
+			log.D(l, "Retrieving all games...")
+			games, err = models.GetAllGames(db)
+			if err != nil {
+				log.E(l, "Retrieve all games failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		serializedGames := make([]map[string]interface{}, len(games))
+		for i, game := range games {
+			serializedGames[i] = game.Serialize()
+		}
+
+		log.D(l, "Retrieve all games completed successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(map[string]interface{}{
+			"games": serializedGames,
+		}, c)
 	}
 }
@@ -20,6 +20,8 @@ type optionalParams struct {
 	cooldownBeforeInvite                           int
 	clanUpdateMetadataFieldsHookTriggerWhitelist   string
 	playerUpdateMetadataFieldsHookTriggerWhitelist string
+	topClansMetadataDimensions                     string
+	clanSearchMetadataKeys                         string
 }
 
 func getOptionalParameters(app *App, c echo.Context) (*optionalParams, error) {
@@ -69,12 +71,28 @@ func getOptionalParameters(app *App, c echo.Context) (*optionalParams, error) {
 		playerWhitelist = ""
 	}
 
+	var topClansDimensions string
+	if val, ok := jsonPayload["topClansMetadataDimensions"]; ok {
+		topClansDimensions = val.(string)
+	} else {
+		topClansDimensions = ""
+	}
+
+	var clanSearchKeys string
+	if val, ok := jsonPayload["clanSearchMetadataKeys"]; ok {
+		clanSearchKeys = val.(string)
+	} else {
+		clanSearchKeys = ""
+	}
+
 	return &optionalParams{
 		maxPendingInvites:                              maxPendingInvites,
 		cooldownBeforeInvite:                           cooldownBeforeInvite,
 		cooldownBeforeApply:                            cooldownBeforeApply,
 		clanUpdateMetadataFieldsHookTriggerWhitelist:   clanWhitelist,
 		playerUpdateMetadataFieldsHookTriggerWhitelist: playerWhitelist,
+		topClansMetadataDimensions:                     topClansDimensions,
+		clanSearchMetadataKeys:                         clanSearchKeys,
 	}, nil
 }
 
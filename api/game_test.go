@@ -293,6 +293,63 @@ var _ = Describe("Game API Handler", func() {
 		})
 	})
 
+	Describe("Retrieve Game Handler", func() {
+		It("Should retrieve game", func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, fmt.Sprintf("/games/%s", game.PublicID))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["publicID"]).To(Equal(game.PublicID))
+			Expect(result["name"]).To(Equal(game.Name))
+			Expect(int(result["maxMembers"].(float64))).To(Equal(game.MaxMembers))
+			Expect(int(result["maxClansPerPlayer"].(float64))).To(Equal(game.MaxClansPerPlayer))
+			Expect(int(result["minLevelToAcceptApplication"].(float64))).To(Equal(game.MinLevelToAcceptApplication))
+			Expect(int(result["cooldownAfterDeny"].(float64))).To(Equal(game.CooldownAfterDeny))
+			Expect(result["membershipLevels"]).To(HaveLen(len(game.MembershipLevels)))
+			Expect(result["clanHookFieldsWhitelist"]).To(Equal(game.ClanUpdateMetadataFieldsHookTriggerWhitelist))
+			Expect(result["playerHookFieldsWhitelist"]).To(Equal(game.PlayerUpdateMetadataFieldsHookTriggerWhitelist))
+		})
+
+		It("Should fail with 404 if game does not exist", func() {
+			status, body := Get(a, fmt.Sprintf("/games/%s", uuid.NewV4().String()))
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+	})
+
+	Describe("List Games Handler", func() {
+		It("Should list all games", func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, "/games")
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			games := result["games"].([]interface{})
+			found := false
+			for _, g := range games {
+				if g.(map[string]interface{})["publicID"] == game.PublicID {
+					found = true
+				}
+			}
+			Expect(found).To(BeTrue())
+		})
+	})
+
 	Describe("Game Hooks", func() {
 		Describe("Update Game Hook", func() {
 			It("Should call update game hook", func() {
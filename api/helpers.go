@@ -57,6 +57,14 @@ func FailWithError(err error, c echo.Context) error {
 		"*models.AlreadyHasValidMembershipError":                     http.StatusConflict,
 		"*models.CannotApproveOrDenyMembershipAlreadyProcessedError": http.StatusConflict,
 		"*models.CannotPromoteOrDemoteMemberLevelError":              http.StatusConflict,
+		"*models.PlayerIsBannedFromClanError":                        http.StatusForbidden,
+		"*models.PlayerIsNotBannedFromClanError":                     http.StatusConflict,
+		"*models.PreHookDeniedError":                                 http.StatusForbidden,
+		"*models.PreHookFailedError":                                 http.StatusServiceUnavailable,
+		"*models.InvalidAPIKeyScopeError":                            http.StatusBadRequest,
+		"*models.APIKeyRevokedError":                                 http.StatusConflict,
+		"*models.InvalidPlayerTokenConfigError":                      http.StatusBadRequest,
+		"*models.VersionMismatchError":                               http.StatusPreconditionFailed,
 	}[t.String()]
 
 	if !ok {
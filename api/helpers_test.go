@@ -83,6 +83,44 @@ func GetTestAppWithBasicAuth(username, password string) *api.App {
 	return app
 }
 
+// GetTestAppWithAPIKeys returns a new Khan API application bound to 0.0.0.0:8888 for test with api keys
+// enabled and the given super admin credential
+func GetTestAppWithAPIKeys(username, password string) *api.App {
+	l := kt.NewMockLogger()
+	app := api.GetApp("0.0.0.0", 8888, "../config/test.yaml", true, l, false, true)
+	app.Config.Set("basicauth.username", username)
+	app.Config.Set("basicauth.password", password)
+	app.Config.Set("apiKeys.enabled", true)
+	app.Configure()
+	return app
+}
+
+// GetTestAppWithPlayerTokens returns a new Khan API application bound to 0.0.0.0:8888 for test with player
+// tokens and api keys enabled and the given super admin credential
+func GetTestAppWithPlayerTokens(username, password string) *api.App {
+	l := kt.NewMockLogger()
+	app := api.GetApp("0.0.0.0", 8888, "../config/test.yaml", true, l, false, true)
+	app.Config.Set("basicauth.username", username)
+	app.Config.Set("basicauth.password", password)
+	app.Config.Set("apiKeys.enabled", true)
+	app.Config.Set("playerTokens.enabled", true)
+	app.Configure()
+	return app
+}
+
+// GetTestAppWithRateLimit returns a new Khan API application bound to 0.0.0.0:8888 for test with rate
+// limits enabled and the given rate limit settings
+func GetTestAppWithRateLimit(settings map[string]interface{}) *api.App {
+	l := kt.NewMockLogger()
+	app := api.GetApp("0.0.0.0", 8888, "../config/test.yaml", true, l, false, true)
+	app.Config.Set("rateLimit.enabled", true)
+	for key, value := range settings {
+		app.Config.Set(key, value)
+	}
+	app.Configure()
+	return app
+}
+
 //Get from server
 func Get(app *api.App, url string) (int, string) {
 	return doRequest(app, "GET", url, "")
@@ -164,12 +202,38 @@ func PerformRequest(ts *httptest.Server, req *http.Request) (int, string) {
 }
 
 func doRequest(app *api.App, method, url, body string) (int, string) {
+	return DoRequestWithHeaders(app, method, url, body, nil)
+}
+
+// DoRequestWithHeaders performs a request to the server with the given headers
+func DoRequestWithHeaders(app *api.App, method, url, body string, headers map[string]string) (int, string) {
+	status, responseBody, _ := DoRequestWithResponseHeaders(app, method, url, body, headers)
+	return status, responseBody
+}
+
+// DoRequestWithResponseHeaders performs a request to the server with the given headers, returning
+// the response headers along with its status and body
+func DoRequestWithResponseHeaders(
+	app *api.App, method, url, body string, headers map[string]string,
+) (int, string, http.Header) {
 	ts := InitializeTestServer(app)
 	defer transport.CloseIdleConnections()
 	defer ts.Close()
 
 	req := GetRequest(app, ts, method, url, body)
-	return PerformRequest(ts, req)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	res, err := client.Do(req)
+	//Wait for port of httptest to be reclaimed by OS
+	time.Sleep(50 * time.Millisecond)
+	Expect(err).NotTo(HaveOccurred())
+
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	Expect(err).NotTo(HaveOccurred())
+
+	return res.StatusCode, string(b), res.Header
 }
 
 // GetGameRoute returns a clan route for the given game id.
@@ -17,6 +17,17 @@ import (
 	"github.com/uber-go/zap"
 )
 
+// invalidateHooks makes the dispatchers reload the hooks of the game
+// The hooks are also reloaded periodically, so a failure does not fail the request.
+func invalidateHooks(app *App, l zap.Logger, gameID string) {
+	err := app.InvalidateHooks(gameID)
+	if err != nil {
+		log.W(l, "Failed to invalidate the cached hooks.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+	}
+}
+
 //CreateHookHandler is the handler responsible for creating new hooks
 func CreateHookHandler(app *App) func(c echo.Context) error {
 	return func(c echo.Context) error {
@@ -56,6 +67,12 @@ func CreateHookHandler(app *App) func(c echo.Context) error {
 				gameID,
 				payload.Type,
 				payload.HookURL,
+				payload.Secret,
+				payload.Filter,
+				payload.BatchSize,
+				payload.BatchInterval,
+				payload.Kind,
+				payload.FailOpen,
 			)
 
 			if err != nil {
@@ -71,6 +88,8 @@ func CreateHookHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
+		invalidateHooks(app, l, gameID)
+
 		log.I(l, "Created hook successfully.", func(cm log.CM) {
 			cm.Write(
 				zap.String("hookPublicID", hook.PublicID),
@@ -121,9 +140,151 @@ func RemoveHookHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
+		invalidateHooks(app, l, gameID)
+
 		log.I(l, "Hook removed successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 		return SucceedWith(map[string]interface{}{}, c)
 	}
 }
+
+// ListHooksHandler is the handler responsible for listing the hooks registered for a game
+func ListHooksHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListHooks")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "ListHooksHandler"),
+			zap.String("operation", "listHooks"),
+			zap.String("gameID", gameID),
+		)
+
+		var hooks []*models.Hook
+		var err error
+		err = WithSegment("hook-list", c, func() error {
+			log.D(l, "Retrieving hooks...")
+			hooks, err = models.GetHooksByGameID(db, gameID)
+			if err != nil {
+				log.E(l, "Failed to retrieve hooks.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		serializedHooks := make([]map[string]interface{}, len(hooks))
+		for i, hook := range hooks {
+			serializedHooks[i] = hook.Serialize()
+		}
+
+		log.D(l, "Hooks retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"hooks": serializedHooks,
+		}, c)
+	}
+}
+
+// ListHookDeadLettersHandler is the handler responsible for listing hook deliveries that exhausted their retries
+func ListHookDeadLettersHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ListHookDeadLetters")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		limitStr := c.QueryParam("limit")
+
+		limit := app.Config.GetInt("webhooks.deadLettersPageSize")
+		if limitStr != "" {
+			parsedLimit, err := parseLimitString(c, limitStr)
+			if err != nil {
+				return err
+			}
+			limit = parsedLimit
+		}
+
+		db := app.Db(c.StdContext())
+
+		l := app.Logger.With(
+			zap.String("source", "ListHookDeadLettersHandler"),
+			zap.String("operation", "listHookDeadLetters"),
+			zap.String("gameID", gameID),
+			zap.Int("limit", limit),
+		)
+
+		var deadLetters []*models.HookDeadLetter
+		var err error
+		err = WithSegment("hook-dead-letters-list", c, func() error {
+			log.D(l, "Retrieving hook dead letters...")
+			deadLetters, err = models.GetHookDeadLettersByGameID(db, gameID, limit)
+			if err != nil {
+				log.E(l, "Failed to retrieve hook dead letters.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		serializedDeadLetters := make([]map[string]interface{}, len(deadLetters))
+		for i, deadLetter := range deadLetters {
+			serializedDeadLetters[i] = deadLetter.Serialize()
+		}
+
+		log.D(l, "Hook dead letters retrieved successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{
+			"deadLetters": serializedDeadLetters,
+		}, c)
+	}
+}
+
+// ReplayHookDeadLetterHandler is the handler responsible for sending a hook dead letter again
+func ReplayHookDeadLetterHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "ReplayHookDeadLetter")
+		start := time.Now()
+		gameID := c.Param("gameID")
+		publicID := c.Param("publicID")
+
+		l := app.Logger.With(
+			zap.String("source", "ReplayHookDeadLetterHandler"),
+			zap.String("operation", "replayHookDeadLetter"),
+			zap.String("gameID", gameID),
+			zap.String("deadLetterPublicID", publicID),
+		)
+
+		err := WithSegment("hook-dead-letter-replay", c, func() error {
+			log.D(l, "Replaying hook dead letter...")
+			err := app.Dispatcher.ReplayHookDeadLetter(c.StdContext(), gameID, publicID)
+			if err != nil {
+				log.E(l, "Failed to replay hook dead letter.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Hook dead letter replayed successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{}, c)
+	}
+}
@@ -0,0 +1,263 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/queues"
+	"github.com/uber-go/zap"
+)
+
+const hookBatches = "hook_batches"
+
+// releaseHookBatchLock only releases the lock, or hold, if it is still owned by the given token
+var releaseHookBatchLock = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// hookBatchKey returns the redis list that buffers the events of a batched hook
+func hookBatchKey(gameID, hookPublicID string) string {
+	return fmt.Sprintf("%skhan:hook-batch:%s:%s", workers.Config.Namespace, gameID, hookPublicID)
+}
+
+// hookBatchHoldKey returns the redis key that holds the flushes of a batched hook while one of its
+// batches is being retried, so the following batches are not delivered before it
+func hookBatchHoldKey(gameID, hookPublicID string) string {
+	return fmt.Sprintf("%s:hold", hookBatchKey(gameID, hookPublicID))
+}
+
+// hookBatchSize returns the maximum number of events delivered in each batch of the hook
+func (d *Dispatcher) hookBatchSize(hook *models.Hook) int {
+	maxBatchSize := d.app.Config.GetInt("webhooks.maxBatchSize")
+	if hook.BatchSize <= 0 || hook.BatchSize > maxBatchSize {
+		return maxBatchSize
+	}
+	return hook.BatchSize
+}
+
+// addToHookBatch buffers the payload in the hook batch and schedules a flush
+// when the batch starts or gets full
+func (d *Dispatcher) addToHookBatch(
+	ctx context.Context, l zap.Logger, gameID string, eventType int,
+	hook *models.Hook, payload map[string]interface{},
+) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	key := hookBatchKey(gameID, hook.PublicID)
+	payloadJSON, _ := json.Marshal(payload)
+
+	conn.Send("MULTI")
+	conn.Send("RPUSH", key, payloadJSON)
+	conn.Send("PEXPIRE", key, d.app.Config.GetInt("webhooks.batchTTL"))
+	replies, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		log.E(l, "Could not add webhook to batch. Sending it alone.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+		d.sendHookBatch(ctx, l, gameID, eventType, hook, []interface{}{payload}, 0, "")
+		return
+	}
+
+	size, _ := redis.Int(replies[0], nil)
+	switch {
+	case size%d.hookBatchSize(hook) == 0:
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, 0)
+	case size == 1 && hook.BatchInterval > 0:
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, time.Duration(hook.BatchInterval)*time.Millisecond)
+	}
+}
+
+// hookBatchRescheduleDelay returns how long a flush that can't run now waits to be tried again
+func (d *Dispatcher) hookBatchRescheduleDelay(hook *models.Hook) time.Duration {
+	if hook.BatchInterval > 0 {
+		return time.Duration(hook.BatchInterval) * time.Millisecond
+	}
+	return d.retryDelay(0)
+}
+
+// holdHookBatches holds the flushes of the hook until the batch with the given id is delivered
+// or dead lettered
+func (d *Dispatcher) holdHookBatches(l zap.Logger, gameID string, hook *models.Hook, batchID string) {
+	if batchID == "" {
+		return
+	}
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	maxRetries := d.app.Config.GetInt("webhooks.maxRetries")
+	maxBackoff := time.Duration(d.app.Config.GetInt("webhooks.maxRetryBackoff")) * time.Millisecond
+	ttl := time.Duration(maxRetries+1) * (maxBackoff + time.Minute)
+	_, err := conn.Do("SET", hookBatchHoldKey(gameID, hook.PublicID), batchID, "PX", ttl.Nanoseconds()/1e6)
+	if err != nil {
+		log.E(l, "Could not hold webhook batches.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+	}
+}
+
+// releaseHookBatches releases the flushes of the hook held by the batch with the given id
+func (d *Dispatcher) releaseHookBatches(l zap.Logger, gameID string, hook *models.Hook, batchID string) {
+	if batchID == "" {
+		return
+	}
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	_, err := releaseHookBatchLock.Do(conn, hookBatchHoldKey(gameID, hook.PublicID), batchID)
+	if err != nil {
+		log.E(l, "Could not release webhook batches.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+	}
+}
+
+// scheduleHookBatchFlush enqueues a flush of the hook batch after the given delay
+func (d *Dispatcher) scheduleHookBatchFlush(
+	l zap.Logger, gameID string, eventType int, hook *models.Hook, delay time.Duration,
+) {
+	args := map[string]interface{}{
+		"gameID":       gameID,
+		"eventType":    eventType,
+		"hookPublicID": hook.PublicID,
+		"flush":        true,
+	}
+
+	var err error
+	if delay > 0 {
+		_, err = workers.EnqueueIn(queues.KhanQueue, "Add", delay.Seconds(), args)
+	} else {
+		_, err = workers.Enqueue(queues.KhanQueue, "Add", args)
+	}
+	if err != nil {
+		log.E(l, "Failed to schedule webhook batch flush.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+	}
+}
+
+// flushHookBatch delivers the oldest buffered events of the hook as a single
+// batch. Flushes of the same hook hold a lock while delivering, and wait while
+// a failed batch is retried, so batches are sent in order.
+func (d *Dispatcher) flushHookBatch(ctx context.Context, l zap.Logger, gameID string, eventType int, hook *models.Hook) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	key := hookBatchKey(gameID, hook.PublicID)
+	lockKey := fmt.Sprintf("%s:lock", key)
+	token := uuid.NewV4().String()
+	delay := d.hookBatchRescheduleDelay(hook)
+	lockTTL := 2*d.app.Config.GetInt("webhooks.timeout") + 1000
+
+	_, err := redis.String(conn.Do("SET", lockKey, token, "NX", "PX", lockTTL))
+	if err == redis.ErrNil {
+		log.D(l, "Webhook batch is already being flushed. Rescheduling.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL))
+		})
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, delay)
+		return
+	}
+	if err != nil {
+		log.E(l, "Could not lock webhook batch.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+		return
+	}
+
+	held, err := redis.Bool(conn.Do("EXISTS", hookBatchHoldKey(gameID, hook.PublicID)))
+	if err != nil || held {
+		releaseHookBatchLock.Do(conn, lockKey, token)
+		log.D(l, "Webhook batch is held by a batch being retried. Rescheduling.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL))
+		})
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, delay)
+		return
+	}
+
+	batchSize := d.hookBatchSize(hook)
+	conn.Send("MULTI")
+	conn.Send("LRANGE", key, 0, batchSize-1)
+	conn.Send("LTRIM", key, batchSize, -1)
+	conn.Send("LLEN", key)
+	replies, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		releaseHookBatchLock.Do(conn, lockKey, token)
+		log.E(l, "Could not read webhook batch.", func(cm log.CM) {
+			cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+		})
+		return
+	}
+	items, _ := redis.ByteSlices(replies[0], nil)
+	remaining, _ := redis.Int(replies[2], nil)
+
+	batch := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		var event map[string]interface{}
+		if err := json.Unmarshal(item, &event); err != nil {
+			log.E(l, "Could not decode batched webhook event.", func(cm log.CM) {
+				cm.Write(zap.String("requestURL", hook.URL), zap.Error(err))
+			})
+			continue
+		}
+		batch = append(batch, event)
+	}
+	retrying := d.sendHookBatch(ctx, l, gameID, eventType, hook, batch, 0, uuid.NewV4().String())
+	releaseHookBatchLock.Do(conn, lockKey, token)
+
+	switch {
+	case remaining >= batchSize && retrying:
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, delay)
+	case remaining >= batchSize:
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, 0)
+	case remaining > 0 && hook.BatchInterval > 0:
+		d.scheduleHookBatchFlush(l, gameID, eventType, hook, delay)
+	}
+}
+
+// sendHookBatch delivers the events as a JSON array. The batch is retried
+// and dead lettered as a unit and, if it has a batchID, the following
+// batches of the hook are held until then. It returns whether the batch
+// is being retried.
+func (d *Dispatcher) sendHookBatch(
+	ctx context.Context, l zap.Logger, gameID string, eventType int,
+	hook *models.Hook, batch []interface{}, attempt int, batchID string,
+) bool {
+	if len(batch) == 0 {
+		d.releaseHookBatches(l, gameID, hook, batchID)
+		return false
+	}
+
+	d.app.DDStatsD.Increment(hookBatches, fmt.Sprintf("url:%s", hook.URL), fmt.Sprintf("game:%s", gameID))
+
+	// the url is interpolated with the first event of the batch
+	first, _ := batch[0].(map[string]interface{})
+	err := d.sendHook(ctx, l, gameID, hook, first, batch)
+	if err == nil {
+		d.releaseHookBatches(l, gameID, hook, batchID)
+		return false
+	}
+
+	d.holdHookBatches(l, gameID, hook, batchID)
+	if !d.retryOrDeadLetterHook(ctx, l, gameID, eventType, hook, nil, batch, batchID, attempt, err) {
+		d.releaseHookBatches(l, gameID, hook, batchID)
+		return false
+	}
+	return true
+}
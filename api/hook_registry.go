@@ -0,0 +1,124 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	"github.com/topfreegames/khan/caches"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// hooksInvalidationChannel returns the redis channel that carries the ids of the games whose hooks changed
+func hooksInvalidationChannel() string {
+	return fmt.Sprintf("%skhan:hooks:invalidations", workers.Config.Namespace)
+}
+
+func (app *App) configureHooksCache() {
+	refreshIntervalKey := "caches.hooks.refreshInterval"
+	app.Config.SetDefault(refreshIntervalKey, time.Minute)
+	refreshInterval := app.Config.GetDuration(refreshIntervalKey)
+	if refreshInterval <= 0 {
+		refreshInterval = time.Minute
+	}
+
+	app.hooksCache = caches.NewHooks(refreshInterval)
+}
+
+//GetHooks returns the hooks of the game for the event type
+func (app *App) GetHooks(ctx context.Context, gameID string, eventType int) []*models.Hook {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "GetHooks"),
+		zap.String("gameID", gameID),
+		zap.Int("eventType", eventType),
+	)
+
+	hooks, err := app.getHooks(ctx, l, gameID, eventType)
+	if err != nil {
+		return nil
+	}
+	return hooks
+}
+
+// getHooks returns the hooks of the game for the event type or the error retrieving them
+func (app *App) getHooks(ctx context.Context, l zap.Logger, gameID string, eventType int) ([]*models.Hook, error) {
+	start := time.Now()
+	log.D(l, "Retrieving hooks...")
+	hooks, err := app.hooksCache.GetHooks(app.Db(ctx), gameID, eventType)
+	if err != nil {
+		log.E(l, "Retrieve hooks failed.", func(cm log.CM) {
+			cm.Write(zap.String("error", err.Error()))
+		})
+		return nil, err
+	}
+	log.D(l, "Hooks retrieved successfully.", func(cm log.CM) {
+		cm.Write(zap.Duration("hookRetrievalDuration", time.Now().Sub(start)))
+	})
+	return hooks, nil
+}
+
+// InvalidateHooks drops the cached hooks of the game in this process and in the other API and worker processes
+func (app *App) InvalidateHooks(gameID string) error {
+	app.hooksCache.Invalidate(gameID)
+
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("PUBLISH", hooksInvalidationChannel(), gameID)
+	return err
+}
+
+// listenHooksInvalidations keeps the hooks cache in sync with the hooks changed by other processes
+func (app *App) listenHooksInvalidations() {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "listenHooksInvalidations"),
+	)
+
+	for {
+		err := app.receiveHooksInvalidations(l)
+		log.W(l, "Hooks invalidations subscription lost.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+
+		// invalidations may have been published while unsubscribed
+		app.hooksCache.InvalidateAll()
+		time.Sleep(time.Second)
+	}
+}
+
+func (app *App) receiveHooksInvalidations(l zap.Logger) error {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	psc := redis.PubSubConn{Conn: conn}
+	err := psc.Subscribe(hooksInvalidationChannel())
+	if err != nil {
+		return err
+	}
+	log.D(l, "Subscribed to hooks invalidations.")
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			gameID := string(v.Data)
+			log.D(l, "Invalidating hooks...", func(cm log.CM) {
+				cm.Write(zap.String("gameID", gameID))
+			})
+			app.hooksCache.Invalidate(gameID)
+		case error:
+			return v
+		}
+	}
+}
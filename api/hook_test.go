@@ -11,9 +11,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
 	"github.com/topfreegames/khan/models"
 )
 
@@ -56,6 +58,194 @@ var _ = Describe("Hook API Handler", func() {
 			Expect(dbHook.URL).To(Equal(payload["hookURL"]))
 		})
 
+		It("Should create hook with signing secret", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":    models.GameUpdatedHook,
+				"hookURL": "http://test/create-signed",
+				"secret":  "my-secret",
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.Secret).To(Equal("my-secret"))
+		})
+
+		It("Should create hook with filter", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":    models.ClanUpdatedHook,
+				"hookURL": "http://test/create-filtered",
+				"filter":  `clan.metadata.region == "EU"`,
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.Filter).To(Equal(payload["filter"]))
+		})
+
+		It("Should not create hook if filter is invalid", func() {
+			a := GetDefaultTestApp()
+			route := GetGameRoute("game-id", "/hooks")
+			payload := map[string]interface{}{
+				"type":    models.ClanUpdatedHook,
+				"hookURL": "http://test/create-filtered",
+				"filter":  `clan.metadata.region = "EU"`,
+			}
+			status, body := PostJSON(a, route, payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(ContainSubstring("Hook filter"))
+		})
+
+		It("Should create hook with batching", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":          models.PlayerUpdatedHook,
+				"hookURL":       "http://test/create-batched",
+				"batchSize":     100,
+				"batchInterval": 5000,
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.BatchSize).To(Equal(100))
+			Expect(dbHook.BatchInterval).To(Equal(5000))
+		})
+
+		It("Should create hook batched by size only", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":      models.PlayerUpdatedHook,
+				"hookURL":   "http://test/create-batched",
+				"batchSize": 100,
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.Batched()).To(BeTrue())
+			Expect(dbHook.BatchInterval).To(Equal(0))
+		})
+
+		It("Should create fail-open pre hook", func() {
+			a := GetDefaultTestApp()
+			db := a.Db(nil)
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"type":     models.ClanCreatedHook,
+				"hookURL":  "http://test/create-pre",
+				"kind":     models.HookKindPre,
+				"failOpen": true,
+			}
+			status, body := PostJSON(a, GetGameRoute(game.PublicID, "/hooks"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			dbHook, err := models.GetHookByPublicID(
+				db, game.PublicID, result["publicID"].(string),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHook.Kind).To(Equal(models.HookKindPre))
+			Expect(dbHook.FailOpen).To(BeTrue())
+		})
+
+		It("Should not create hook if pre hook is batched", func() {
+			a := GetDefaultTestApp()
+			route := GetGameRoute("game-id", "/hooks")
+			payload := map[string]interface{}{
+				"type":          models.ClanCreatedHook,
+				"hookURL":       "http://test/create-pre",
+				"kind":          models.HookKindPre,
+				"batchSize":     100,
+				"batchInterval": 5000,
+			}
+			status, body := PostJSON(a, route, payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("pre hooks can't be batched"))
+		})
+
+		It("Should not create hook if secret is too long", func() {
+			a := GetDefaultTestApp()
+			route := GetGameRoute("game-id", "/hooks")
+			payload := map[string]interface{}{
+				"type":    models.GameUpdatedHook,
+				"hookURL": "http://test/create-signed",
+				"secret":  strings.Repeat("a", 256),
+			}
+			status, body := PostJSON(a, route, payload)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("secret should have at most 255 characters"))
+		})
+
 		It("Should not create hook if missing parameters", func() {
 			a := GetDefaultTestApp()
 			route := GetGameRoute("game-id", "/hooks")
@@ -101,4 +291,121 @@ var _ = Describe("Hook API Handler", func() {
 			Expect(number == 0).To(BeTrue())
 		})
 	})
+
+	Describe("List Hooks Handler", func() {
+		It("Should list hooks for the game", func() {
+			a := GetDefaultTestApp()
+
+			gameID := uuid.NewV4().String()
+			hooks, err := models.GetTestHooks(testDb, gameID, 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(gameID, "/hooks"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			rHooks := result["hooks"].([]interface{})
+			Expect(rHooks).To(HaveLen(len(hooks)))
+			publicIDs := map[string]bool{}
+			for _, hook := range hooks {
+				publicIDs[hook.PublicID] = true
+			}
+			for _, rHook := range rHooks {
+				hook := rHook.(map[string]interface{})
+				Expect(hook["gameID"]).To(Equal(gameID))
+				Expect(publicIDs[hook["publicID"].(string)]).To(BeTrue())
+				Expect(hook["hookURL"]).NotTo(BeEmpty())
+			}
+		})
+
+		It("Should return empty list if game has no hooks", func() {
+			a := GetDefaultTestApp()
+
+			status, body := Get(a, GetGameRoute(uuid.NewV4().String(), "/hooks"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+			Expect(result["hooks"]).To(BeEmpty())
+		})
+	})
+
+	Describe("List Hook Dead Letters Handler", func() {
+		It("Should list hook dead letters for the game", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.ClanCreatedHook, "http://test/created")
+			Expect(err).NotTo(HaveOccurred())
+			deadLetter, err := models.CreateHookDeadLetter(
+				testDb, hook.GameID, hook.PublicID, hook.EventType, hook.URL,
+				map[string]interface{}{"publicID": "clan-id"}, false, "status 500", 6,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Get(a, GetGameRoute(hook.GameID, "/hooks/dead-letters"))
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			deadLetters := result["deadLetters"].([]interface{})
+			Expect(deadLetters).To(HaveLen(1))
+			rDeadLetter := deadLetters[0].(map[string]interface{})
+			Expect(rDeadLetter["publicID"]).To(Equal(deadLetter.PublicID))
+			Expect(rDeadLetter["hookPublicID"]).To(Equal(hook.PublicID))
+			Expect(rDeadLetter["hookURL"]).To(Equal(hook.URL))
+			Expect(rDeadLetter["lastError"]).To(Equal("status 500"))
+			Expect(int(rDeadLetter["attempts"].(float64))).To(Equal(6))
+		})
+
+		It("Should fail if invalid limit", func() {
+			a := GetDefaultTestApp()
+
+			status, _ := Get(a, GetGameRoute("game-id", "/hooks/dead-letters?limit=invalid"))
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Replay Hook Dead Letter Handler", func() {
+		It("Should replay and remove hook dead letter", func() {
+			a := GetDefaultTestApp()
+
+			hook, err := models.CreateHookFactory(testDb, "", models.ClanCreatedHook, "http://test/created")
+			Expect(err).NotTo(HaveOccurred())
+			deadLetter, err := models.CreateHookDeadLetter(
+				testDb, hook.GameID, hook.PublicID, hook.EventType, hook.URL,
+				map[string]interface{}{"publicID": "clan-id"}, false, "status 500", 6,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := GetGameRoute(hook.GameID, fmt.Sprintf("/hooks/dead-letters/%s/replay", deadLetter.PublicID))
+			status, body := Post(a, route, "")
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			_, err = models.GetHookDeadLetterByPublicID(testDb, hook.GameID, deadLetter.PublicID)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail with 404 if hook dead letter does not exist", func() {
+			a := GetDefaultTestApp()
+
+			route := GetGameRoute("game-id", fmt.Sprintf("/hooks/dead-letters/%s/replay", uuid.NewV4().String()))
+			status, body := Post(a, route, "")
+
+			Expect(status).To(Equal(http.StatusNotFound))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+	})
 })
@@ -0,0 +1,247 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// IdempotencyKeyHeader carries the key that identifies retries of the same request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set in responses replayed from a previous request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const idempotentReplays = "idempotent_replays"
+
+const maxIdempotencyKeyLength = 255
+
+// idempotentReplayedHeaders are the response headers stored with the response and replayed
+var idempotentReplayedHeaders = []string{ETagHeader, "Location"}
+
+// reserveIdempotencyKey returns the request hash, status, body and headers stored for the key or, if
+// there is none, reserves the key for the request with the hash in ARGV[1] for ARGV[2] milliseconds
+var reserveIdempotencyKey = redis.NewScript(1, `
+local stored = redis.call("HMGET", KEYS[1], "hash", "status", "body", "headers")
+if stored[1] then
+	return stored
+end
+redis.call("HSET", KEYS[1], "hash", ARGV[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[2])
+return false
+`)
+
+// idempotencyKey returns the redis hash that stores the response of the request with the given key
+// Keys are scoped by game and credential, so clients sharing a game can't replay each other's responses.
+func idempotencyKey(gameID, credential, key string) string {
+	return fmt.Sprintf("%skhan:idempotency:%s:%s:%s", workers.Config.Namespace, gameID, credential, key)
+}
+
+// idempotencyCredential returns the credential the request was authenticated with
+// Requests authenticated by basic auth, or not authenticated, share the same credential.
+func idempotencyCredential(c echo.Context) string {
+	if apiKey, ok := c.Get("apiKey").(*models.APIKey); ok {
+		return "api-key:" + apiKey.PublicID
+	}
+	if playerPublicID, ok := c.Get("playerPublicID").(string); ok {
+		return "player:" + playerPublicID
+	}
+	return "basic"
+}
+
+// hashIdempotentRequest returns a hash of the request method, uri and body
+func hashIdempotentRequest(c echo.Context) (string, error) {
+	body, err := GetRequestBody(c)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", c.Request().Method(), c.Request().URI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+//NewIdempotencyMiddleware returns a new idempotency middleware
+func NewIdempotencyMiddleware(app *App) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		App: app,
+	}
+}
+
+//IdempotencyMiddleware replays the stored response of POST and PUT requests retried with the same
+//Idempotency-Key header. Keys are stored per game and credential in redis for idempotency.ttl, and
+//only responses that don't fail with server errors are stored, so those requests can be retried.
+type IdempotencyMiddleware struct {
+	App *App
+}
+
+// Serve serves the middleware
+func (m *IdempotencyMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method()
+		key := c.Request().Header().Get(IdempotencyKeyHeader)
+		if key == "" || (method != echo.POST && method != echo.PUT) {
+			return next(c)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return FailWith(
+				http.StatusBadRequest,
+				fmt.Sprintf("%s should have at most %d characters.", IdempotencyKeyHeader, maxIdempotencyKeyLength),
+				c,
+			)
+		}
+
+		gameID := c.Param("gameID")
+		route := method + " " + c.Path()
+		l := m.App.Logger.With(
+			zap.String("source", "IdempotencyMiddleware"),
+			zap.String("operation", "Serve"),
+			zap.String("route", route),
+			zap.String("gameID", gameID),
+			zap.String("idempotencyKey", key),
+		)
+
+		hash, err := hashIdempotentRequest(c)
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		redisKey := idempotencyKey(gameID, idempotencyCredential(c), key)
+		stored, err := m.reserve(redisKey, hash)
+		if err != nil && err != redis.ErrNil {
+			log.E(l, "Could not reserve idempotency key. Handling request anyway.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return next(c)
+		}
+
+		if err == nil {
+			if stored[0] != hash {
+				log.W(l, "Idempotency key used with a different request.")
+				return FailWith(http.StatusConflict, "Idempotency key was already used with a different request.", c)
+			}
+			if stored[1] == "" {
+				log.W(l, "Request with the idempotency key still in progress.")
+				return FailWith(http.StatusConflict, "A request with this idempotency key is in progress.", c)
+			}
+
+			status, headers, err := parseIdempotentResponse(stored)
+			if err != nil {
+				log.E(l, "Could not parse response of idempotency key. Handling request anyway.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				m.release(l, redisKey)
+				return next(c)
+			}
+
+			log.D(l, "Replaying response of idempotency key.")
+			m.App.DDStatsD.Increment(idempotentReplays, fmt.Sprintf("route:%s", route), fmt.Sprintf("game:%s", gameID))
+			for name, value := range headers {
+				c.Response().Header().Set(name, value)
+			}
+			c.Response().Header().Set(IdempotentReplayedHeader, "true")
+			return c.JSONBlob(status, []byte(stored[2]))
+		}
+
+		body, err := getBodyFromNext(c, next)
+		status := c.Response().Status()
+		if err != nil || !c.Response().Committed() || status >= http.StatusInternalServerError {
+			m.release(l, redisKey)
+			return err
+		}
+
+		headers := map[string]string{}
+		for _, name := range idempotentReplayedHeaders {
+			if value := c.Response().Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+		if err := m.store(redisKey, status, body, headers); err != nil {
+			log.E(l, "Could not store response of idempotency key.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+		return nil
+	}
+}
+
+// reserve returns the stored request hash, status, body and headers of the key or reserves it
+// The connection is released before the request is handled, so it is not held while the handler runs.
+func (m *IdempotencyMiddleware) reserve(redisKey, hash string) ([]string, error) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	return redis.Strings(reserveIdempotencyKey.Do(
+		conn, redisKey, hash, m.App.Config.GetDuration("idempotency.lockTimeout").Nanoseconds()/1e6,
+	))
+}
+
+// release deletes the key, so the request can be retried
+func (m *IdempotencyMiddleware) release(l zap.Logger, redisKey string) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	if _, err := conn.Do("DEL", redisKey); err != nil {
+		log.E(l, "Could not release idempotency key.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+	}
+}
+
+// store stores the response of the key for idempotency.ttl
+func (m *IdempotencyMiddleware) store(redisKey string, status int, body string, headers map[string]string) error {
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return err
+	}
+
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	err = conn.Send("MULTI")
+	if err != nil {
+		return err
+	}
+	err = conn.Send("HMSET", redisKey, "status", status, "body", body, "headers", headersJSON)
+	if err != nil {
+		return err
+	}
+	err = conn.Send("PEXPIRE", redisKey, m.App.Config.GetDuration("idempotency.ttl").Nanoseconds()/1e6)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Do("EXEC")
+	return err
+}
+
+// parseIdempotentResponse returns the status and headers of the response stored for a key
+func parseIdempotentResponse(stored []string) (int, map[string]string, error) {
+	var status int
+	if _, err := fmt.Sscanf(stored[1], "%d", &status); err != nil {
+		return 0, nil, err
+	}
+	if status < 100 {
+		return 0, nil, fmt.Errorf("invalid stored status %d", status)
+	}
+	headers := map[string]string{}
+	if err := json.Unmarshal([]byte(stored[3]), &headers); err != nil {
+		return 0, nil, err
+	}
+	return status, headers, nil
+}
@@ -0,0 +1,192 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Idempotency Middleware", func() {
+	var db models.DB
+	var a *api.App
+	var game *models.Game
+
+	BeforeEach(func() {
+		a = GetDefaultTestApp()
+		db = a.Db(nil)
+
+		game = models.GameFactory.MustCreate().(*models.Game)
+		err := db.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	createPlayer := func(gameID, publicID, idempotencyKey string) (int, string, http.Header) {
+		return DoRequestWithResponseHeaders(
+			a, "POST", fmt.Sprintf("/games/%s/players", gameID),
+			fmt.Sprintf(`{"publicID": "%s", "name": "player", "metadata": {}}`, publicID),
+			map[string]string{api.IdempotencyKeyHeader: idempotencyKey},
+		)
+	}
+
+	It("Should replay the response of retried requests", func() {
+		key := uuid.NewV4().String()
+		publicID := uuid.NewV4().String()
+
+		status, body, headers := createPlayer(game.PublicID, publicID, key)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(headers.Get(api.IdempotentReplayedHeader)).To(BeEmpty())
+
+		replayedStatus, replayedBody, replayedHeaders := createPlayer(game.PublicID, publicID, key)
+		Expect(replayedStatus).To(Equal(status))
+		Expect(strings.TrimSpace(replayedBody)).To(Equal(strings.TrimSpace(body)))
+		Expect(replayedHeaders.Get(api.IdempotentReplayedHeader)).To(Equal("true"))
+
+		count, err := db.SelectInt(
+			"SELECT COUNT(*) FROM players WHERE game_id=$1 AND public_id=$2", game.PublicID, publicID,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(BeEquivalentTo(1))
+	})
+
+	It("Should replay client errors", func() {
+		key := uuid.NewV4().String()
+
+		status, body, _ := createPlayer(game.PublicID, "", key)
+		Expect(status).To(Equal(http.StatusBadRequest))
+
+		replayedStatus, replayedBody, replayedHeaders := createPlayer(game.PublicID, "", key)
+		Expect(replayedStatus).To(Equal(http.StatusBadRequest))
+		Expect(strings.TrimSpace(replayedBody)).To(Equal(strings.TrimSpace(body)))
+		Expect(replayedHeaders.Get(api.IdempotentReplayedHeader)).To(Equal("true"))
+	})
+
+	It("Should fail if the key is used with a different request", func() {
+		key := uuid.NewV4().String()
+
+		status, _, _ := createPlayer(game.PublicID, uuid.NewV4().String(), key)
+		Expect(status).To(Equal(http.StatusOK))
+
+		status, body, _ := createPlayer(game.PublicID, uuid.NewV4().String(), key)
+		Expect(status).To(Equal(http.StatusConflict))
+		Expect(body).To(ContainSubstring("different request"))
+	})
+
+	It("Should store keys per game", func() {
+		otherGame := models.GameFactory.MustCreate().(*models.Game)
+		err := db.Insert(otherGame)
+		Expect(err).NotTo(HaveOccurred())
+		key := uuid.NewV4().String()
+		publicID := uuid.NewV4().String()
+
+		status, _, _ := createPlayer(game.PublicID, publicID, key)
+		Expect(status).To(Equal(http.StatusOK))
+
+		status, _, headers := createPlayer(otherGame.PublicID, publicID, key)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(headers.Get(api.IdempotentReplayedHeader)).To(BeEmpty())
+	})
+
+	It("Should replay the headers of the response", func() {
+		player := models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+			"GameID": game.PublicID,
+		}).(*models.Player)
+		err := db.Insert(player)
+		Expect(err).NotTo(HaveOccurred())
+
+		key := uuid.NewV4().String()
+		route := fmt.Sprintf("/games/%s/players/%s", game.PublicID, player.PublicID)
+		payload := `{"name": "new name", "metadata": {}}`
+		headers := map[string]string{api.IdempotencyKeyHeader: key}
+
+		status, _, resHeaders := DoRequestWithResponseHeaders(a, "PUT", route, payload, headers)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(resHeaders.Get(api.ETagHeader)).To(Equal(`"2"`))
+
+		status, _, resHeaders = DoRequestWithResponseHeaders(a, "PUT", route, payload, headers)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(resHeaders.Get(api.IdempotentReplayedHeader)).To(Equal("true"))
+		Expect(resHeaders.Get(api.ETagHeader)).To(Equal(`"2"`))
+	})
+
+	It("Should handle the request if the stored response is corrupt", func() {
+		player := models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+			"GameID": game.PublicID,
+		}).(*models.Player)
+		err := db.Insert(player)
+		Expect(err).NotTo(HaveOccurred())
+
+		key := uuid.NewV4().String()
+		route := fmt.Sprintf("/games/%s/players/%s", game.PublicID, player.PublicID)
+		payload := `{"name": "new name", "metadata": {}}`
+		headers := map[string]string{api.IdempotencyKeyHeader: key}
+
+		status, _, _ := DoRequestWithResponseHeaders(a, "PUT", route, payload, headers)
+		Expect(status).To(Equal(http.StatusOK))
+
+		conn := workers.Config.Pool.Get()
+		defer conn.Close()
+		redisKeys, err := redis.Strings(conn.Do("KEYS", fmt.Sprintf("*khan:idempotency:%s:*:%s", game.PublicID, key)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redisKeys).To(HaveLen(1))
+		_, err = conn.Do("HSET", redisKeys[0], "status", "corrupt")
+		Expect(err).NotTo(HaveOccurred())
+
+		status, _, resHeaders := DoRequestWithResponseHeaders(a, "PUT", route, payload, headers)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(resHeaders.Get(api.IdempotentReplayedHeader)).To(BeEmpty())
+		Expect(resHeaders.Get(api.ETagHeader)).To(Equal(`"3"`))
+
+		exists, err := redis.Bool(conn.Do("EXISTS", redisKeys[0]))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("Should store keys per credential", func() {
+		authApp := GetTestAppWithAPIKeys("admin", "secret")
+		_, firstKey, err := models.CreateAPIKey(db, game.PublicID, "first", models.APIKeyScopePlayerWrite)
+		Expect(err).NotTo(HaveOccurred())
+		_, secondKey, err := models.CreateAPIKey(db, game.PublicID, "second", models.APIKeyScopePlayerWrite)
+		Expect(err).NotTo(HaveOccurred())
+
+		key := uuid.NewV4().String()
+		route := fmt.Sprintf("/games/%s/players", game.PublicID)
+		payload := fmt.Sprintf(`{"publicID": "%s", "name": "player", "metadata": {}}`, uuid.NewV4().String())
+
+		status, _, _ := DoRequestWithResponseHeaders(authApp, "POST", route, payload, map[string]string{
+			api.IdempotencyKeyHeader: key, api.APIKeyHeader: firstKey,
+		})
+		Expect(status).To(Equal(http.StatusOK))
+
+		status, _, headers := DoRequestWithResponseHeaders(authApp, "POST", route, payload, map[string]string{
+			api.IdempotencyKeyHeader: key, api.APIKeyHeader: secondKey,
+		})
+		Expect(headers.Get(api.IdempotentReplayedHeader)).To(BeEmpty())
+		Expect(status).NotTo(Equal(http.StatusOK))
+	})
+
+	It("Should not replay requests without idempotency keys", func() {
+		publicID := uuid.NewV4().String()
+		route := fmt.Sprintf("/games/%s/players", game.PublicID)
+		payload := fmt.Sprintf(`{"publicID": "%s", "name": "player", "metadata": {}}`, publicID)
+
+		status, _ := Post(a, route, payload)
+		Expect(status).To(Equal(http.StatusOK))
+		status, _ = Post(a, route, payload)
+		Expect(status).NotTo(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,188 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+const indexDriftDocuments = "index_drift_documents"
+
+// VerifyIndexes compares the clans of the games in Postgres with the documents in the given
+// index stores by id and updatedAt, returning one drift per game and store
+// Stores that are not configured are skipped. With repair, the jobs that fix the drifted
+// documents are enqueued to the mongo and elasticsearch workers.
+func (app *App) VerifyIndexes(ctx context.Context, gameIDs, stores []string, repair bool) ([]*models.IndexDrift, error) {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "VerifyIndexes"),
+		zap.Bool("repair", repair),
+	)
+
+	db := app.Db(ctx)
+	drifts := []*models.IndexDrift{}
+	for _, gameID := range gameIDs {
+		expected, err := models.GetClanVersions(db, gameID)
+		if err != nil {
+			return drifts, err
+		}
+
+		for _, store := range stores {
+			var actual map[string]int64
+			switch {
+			case store == models.IndexStoreMongo && app.MongoDB != nil:
+				collection := fmt.Sprintf(app.Config.GetString("mongodb.collectionTemplate"), gameID)
+				actual, err = models.GetMongoDBClanVersions(app.MongoDB, collection)
+			case store == models.IndexStoreElasticsearch && app.ESClient != nil:
+				actual, err = models.GetElasticSearchClanVersions(ctx, app.ESClient, app.ESClient.GetIndexName(gameID))
+			default:
+				continue
+			}
+			if err != nil {
+				return drifts, err
+			}
+
+			drift := models.CompareClanVersions(gameID, store, expected, actual)
+			drifts = append(drifts, drift)
+			app.reportIndexDrift(drift)
+
+			if drift.Empty() {
+				continue
+			}
+			log.W(l, "Index drift found.", func(cm log.CM) {
+				cm.Write(
+					zap.String("gameID", gameID),
+					zap.String("store", store),
+					zap.Int("missing", len(drift.Missing)),
+					zap.Int("extra", len(drift.Extra)),
+					zap.Int("stale", len(drift.Stale)),
+				)
+			})
+			if !repair {
+				continue
+			}
+			enqueued, err := models.RepairIndexDrift(db, app.ESClient, drift)
+			if err != nil {
+				return drifts, err
+			}
+			log.I(l, "Index drift repair enqueued.", func(cm log.CM) {
+				cm.Write(zap.String("gameID", gameID), zap.String("store", store), zap.Int("enqueued", enqueued))
+			})
+		}
+	}
+	return drifts, nil
+}
+
+func (app *App) reportIndexDrift(drift *models.IndexDrift) {
+	if app.DDStatsD == nil {
+		return
+	}
+	kinds := map[string][]string{"missing": drift.Missing, "extra": drift.Extra, "stale": drift.Stale}
+	for kind, publicIDs := range kinds {
+		app.DDStatsD.Gauge(
+			indexDriftDocuments,
+			float64(len(publicIDs)),
+			fmt.Sprintf("game:%s", drift.GameID),
+			fmt.Sprintf("store:%s", drift.Store),
+			fmt.Sprintf("kind:%s", kind),
+		)
+	}
+}
+
+// indexVerifierOnce keeps a single index verifier per process
+var indexVerifierOnce sync.Once
+
+// indexVerifierLockKey returns the redis key that elects the worker verifying the indexes in each interval
+func indexVerifierLockKey() string {
+	return fmt.Sprintf("%skhan:index-verifier:lock", workers.Config.Namespace)
+}
+
+// startIndexVerifier verifies the indexes of all games every indexVerifier.interval, if enabled
+func (app *App) startIndexVerifier() {
+	if !app.Config.GetBool("indexVerifier.enabled") {
+		return
+	}
+	indexVerifierOnce.Do(func() {
+		go app.runIndexVerifier(app.Config.GetDuration("indexVerifier.interval"), app.Config.GetBool("indexVerifier.repair"))
+	})
+}
+
+func (app *App) runIndexVerifier(interval time.Duration, repair bool) {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "runIndexVerifier"),
+	)
+
+	stores := []string{models.IndexStoreMongo, models.IndexStoreElasticsearch}
+	for {
+		time.Sleep(interval)
+
+		// only the worker that takes the lock verifies the indexes in this interval, the lock
+		// is left to expire so the other workers skip the verification until the next one
+		elected, err := app.takeIndexVerifierLock(interval)
+		if err != nil {
+			log.E(l, "Could not lock index verifier.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			continue
+		}
+		if !elected {
+			log.D(l, "Indexes are being verified by another worker. Skipping.")
+			continue
+		}
+
+		games, err := models.GetAllGames(app.Db(nil))
+		if err != nil {
+			log.E(l, "Failed to load games.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			continue
+		}
+		gameIDs := make([]string, len(games))
+		for i, game := range games {
+			gameIDs[i] = game.PublicID
+		}
+
+		start := time.Now()
+		if _, err := app.VerifyIndexes(context.Background(), gameIDs, stores, repair); err != nil {
+			log.E(l, "Failed to verify indexes.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			continue
+		}
+		log.D(l, "Indexes verified.", func(cm log.CM) {
+			cm.Write(zap.Int("games", len(gameIDs)), zap.Duration("duration", time.Since(start)))
+		})
+	}
+}
+
+// takeIndexVerifierLock returns whether this worker took the index verifier lock for the interval
+func (app *App) takeIndexVerifierLock(interval time.Duration) (bool, error) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	ttl := int64(interval / time.Millisecond)
+	if ttl < 1 {
+		ttl = 1
+	}
+	_, err := redis.String(conn.Do("SET", indexVerifierLockKey(), uuid.NewV4().String(), "NX", "PX", ttl))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	return err == nil, err
+}
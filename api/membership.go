@@ -53,13 +53,21 @@ func ApplyForMembershipHandler(app *App) func(c echo.Context) error {
 			return FailWith(400, err.Error(), c)
 		}
 
+		err = runMembershipPreHooks(
+			app, c, gameID, clanPublicID, payload.PlayerPublicID, payload.PlayerPublicID,
+			&payload.Level, &optional.Message,
+		)
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
 		l = l.With(
 			zap.String("level", payload.Level),
 			zap.String("playerPublicID", payload.PlayerPublicID),
 		)
 
 		var game *models.Game
-		var membership *models.Membership
+		var membership, beforeMembership *models.Membership
 		err = WithSegment("game-retrieve", c, func() error {
 			game, err = app.GetGame(c.StdContext(), gameID)
 			if err != nil {
@@ -84,6 +92,8 @@ func ApplyForMembershipHandler(app *App) func(c echo.Context) error {
 			log.D(l, "DB Tx begun successful.")
 
 			err = WithSegment("membership-apply-query", c, func() error {
+				beforeMembership, _ = models.GetMembershipByClanAndPlayerPublicID(tx, gameID, clanPublicID, payload.PlayerPublicID)
+
 				log.D(l, "Applying for membership...")
 				membership, err = models.CreateMembership(
 					tx,
@@ -109,6 +119,23 @@ func ApplyForMembershipHandler(app *App) func(c echo.Context) error {
 				})
 				return err
 			}
+
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditMembershipApplied,
+				payload.PlayerPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if err != nil {
+				txErr := app.Rollback(tx, "Membership application failed", c, l, err)
+				if txErr != nil {
+					return txErr
+				}
+
+				log.E(l, "Membership application audit failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
 			return nil
 		})
 		if err != nil {
@@ -160,7 +187,7 @@ func InviteForMembershipHandler(app *App) func(c echo.Context) error {
 		var optional *membershipOptionalParams
 		var err error
 		var game *models.Game
-		var membership *models.Membership
+		var membership, beforeMembership *models.Membership
 		var tx interfaces.Transaction
 
 		c.Set("route", "InviteForMembership")
@@ -189,6 +216,14 @@ func InviteForMembershipHandler(app *App) func(c echo.Context) error {
 			return FailWith(400, err.Error(), c)
 		}
 
+		err = runMembershipPreHooks(
+			app, c, gameID, clanPublicID, payload.PlayerPublicID, payload.RequestorPublicID,
+			&payload.Level, &optional.Message,
+		)
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
 		l = l.With(
 			zap.String("level", payload.Level),
 			zap.String("playerPublicID", payload.PlayerPublicID),
@@ -218,6 +253,8 @@ func InviteForMembershipHandler(app *App) func(c echo.Context) error {
 			log.D(l, "DB Tx begun successful.")
 
 			err = WithSegment("membership-invite-query", c, func() error {
+				beforeMembership, _ = models.GetMembershipByClanAndPlayerPublicID(tx, gameID, clanPublicID, payload.PlayerPublicID)
+
 				log.D(l, "Inviting for membership...")
 				membership, err = models.CreateMembership(
 					tx,
@@ -242,6 +279,23 @@ func InviteForMembershipHandler(app *App) func(c echo.Context) error {
 				})
 				return err
 			}
+
+			err = createAuditEvent(
+				c, tx, gameID, models.AuditMembershipInvited,
+				payload.RequestorPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if err != nil {
+				txErr := app.Rollback(tx, "Membership invitation failed", c, l, err)
+				if txErr != nil {
+					return txErr
+				}
+
+				log.E(l, "Membership invitation audit failed.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
 			return nil
 		})
 		if err != nil {
@@ -336,7 +390,7 @@ func ApproveOrDenyMembershipApplicationHandler(app *App) func(c echo.Context) er
 			return app.Rollback(tx, "Approving/Denying membership application failed", c, l, rbErr)
 		}
 
-		var membership *models.Membership
+		var membership, beforeMembership *models.Membership
 		var requestor *models.Player
 		err = WithSegment("membership-approve-deny", c, func() error {
 			txErr := WithSegment("tx-begin", c, func() error {
@@ -350,6 +404,8 @@ func ApproveOrDenyMembershipApplicationHandler(app *App) func(c echo.Context) er
 			log.D(l, "DB Tx begun successful.")
 
 			qErr := WithSegment("membership-approve-deny-query", c, func() error {
+				beforeMembership, _ = models.GetValidMembershipByClanAndPlayerPublicID(tx, gameID, clanPublicID, payload.PlayerPublicID)
+
 				log.D(l, "Approving/Denying membership application.")
 				var mErr error
 				membership, mErr = models.ApproveOrDenyMembershipApplication(
@@ -377,6 +433,25 @@ func ApproveOrDenyMembershipApplicationHandler(app *App) func(c echo.Context) er
 				return qErr
 			}
 
+			auditAction := models.AuditMembershipApplicationApproved
+			if action == "deny" {
+				auditAction = models.AuditMembershipApplicationDenied
+			}
+			aErr := createAuditEvent(
+				c, tx, gameID, auditAction,
+				payload.RequestorPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if aErr != nil {
+				txErr := rb(aErr)
+				if txErr == nil {
+					log.E(l, "Approving/Denying membership application audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(aErr))
+					})
+				}
+				return aErr
+			}
+
 			return WithSegment("player-retrieve", c, func() error {
 				log.D(l, "Retrieving requestor details.")
 				var gErr error
@@ -442,7 +517,7 @@ func ApproveOrDenyMembershipApplicationHandler(app *App) func(c echo.Context) er
 func ApproveOrDenyMembershipInvitationHandler(app *App) func(c echo.Context) error {
 	return func(c echo.Context) error {
 		var game *models.Game
-		var membership *models.Membership
+		var membership, beforeMembership *models.Membership
 		var err error
 		var tx interfaces.Transaction
 
@@ -503,7 +578,9 @@ func ApproveOrDenyMembershipInvitationHandler(app *App) func(c echo.Context) err
 			}
 			log.D(l, "DB Tx begun successful.")
 
-			return WithSegment("memership-approve-deny-query", c, func() error {
+			err = WithSegment("memership-approve-deny-query", c, func() error {
+				beforeMembership, _ = models.GetValidMembershipByClanAndPlayerPublicID(tx, gameID, clanPublicID, payload.PlayerPublicID)
+
 				log.D(l, "Approving/Denying membership invitation...")
 				membership, err = models.ApproveOrDenyMembershipInvitation(
 					tx,
@@ -524,6 +601,29 @@ func ApproveOrDenyMembershipInvitationHandler(app *App) func(c echo.Context) err
 				}
 				return nil
 			})
+			if err != nil {
+				return err
+			}
+
+			auditAction := models.AuditMembershipInvitationApproved
+			if action == "deny" {
+				auditAction = models.AuditMembershipInvitationDenied
+			}
+			err = createAuditEvent(
+				c, tx, gameID, auditAction,
+				payload.PlayerPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Membership invitation approval/deny audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
 		})
 		if err != nil {
 			return FailWithError(err, c)
@@ -575,7 +675,7 @@ func DeleteMembershipHandler(app *App) func(c echo.Context) error {
 		var status int
 		var payload *BasePayloadWithRequestorAndPlayerPublicIDs
 		var game *models.Game
-		var membership *models.Membership
+		var membership, beforeMembership *models.Membership
 		var tx interfaces.Transaction
 
 		c.Set("route", "DeleteMembership")
@@ -624,6 +724,8 @@ func DeleteMembershipHandler(app *App) func(c echo.Context) error {
 			}
 			log.D(l, "DB Tx began successfully.")
 
+			beforeMembership, _ = models.GetValidMembershipByClanAndPlayerPublicID(tx, game.PublicID, clanPublicID, payload.PlayerPublicID)
+
 			log.D(l, "Deleting membership...")
 			membership, err = models.DeleteMembership(
 				tx,
@@ -643,6 +745,21 @@ func DeleteMembershipHandler(app *App) func(c echo.Context) error {
 				}
 				return err
 			}
+
+			err = createAuditEvent(
+				c, tx, game.PublicID, models.AuditMembershipDeleted,
+				payload.RequestorPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Membership delete audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
 			return nil
 		})
 		if err != nil {
@@ -688,17 +805,16 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 	return func(c echo.Context) error {
 		var payload *BasePayloadWithRequestorAndPlayerPublicIDs
 		var game *models.Game
-		var membership *models.Membership
+		var membership, beforeMembership *models.Membership
 		var requestor *models.Player
 		var status int
 		var err error
+		var tx interfaces.Transaction
 
 		c.Set("route", "PromoteOrDemoteMember")
 		start := time.Now()
 		clanPublicID := c.Param("clanPublicID")
 
-		db := app.Db(c.StdContext())
-
 		l := app.Logger.With(
 			zap.String("source", "membershipHandler"),
 			zap.String("operation", "promoteOrDemoteMembership"),
@@ -723,11 +839,31 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 			zap.String("requestorPublicID", payload.RequestorPublicID),
 		)
 
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Promoting/Demoting member failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
 		err = WithSegment("membership-promote-demote", c, func() error {
+			err = WithSegment("tx-begin", c, func() error {
+				tx, err = app.BeginTrans(c.StdContext(), l)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			log.D(l, "DB Tx begun successful.")
+
 			err = WithSegment("membership-promote-demote-query", c, func() error {
+				beforeMembership, _ = models.GetValidMembershipByClanAndPlayerPublicID(tx, game.PublicID, clanPublicID, payload.PlayerPublicID)
+
 				log.D(l, "Promoting/Demoting member...")
 				membership, err = models.PromoteOrDemoteMember(
-					db,
+					tx,
 					game,
 					game.PublicID,
 					payload.PlayerPublicID,
@@ -737,9 +873,12 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 				)
 
 				if err != nil {
-					log.E(l, "Member promotion/demotion failed.", func(cm log.CM) {
-						cm.Write(zap.Error(err))
-					})
+					txErr := rb(err)
+					if txErr == nil {
+						log.E(l, "Member promotion/demotion failed.", func(cm log.CM) {
+							cm.Write(zap.Error(err))
+						})
+					}
 					return err
 				}
 				log.D(l, "Member promoted/demoted successful.")
@@ -750,13 +889,35 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 				return err
 			}
 
+			auditAction := models.AuditMembershipPromoted
+			if action == "demote" {
+				auditAction = models.AuditMembershipDemoted
+			}
+			err = createAuditEvent(
+				c, tx, game.PublicID, auditAction,
+				payload.RequestorPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Member promotion/demotion audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+
 			err = WithSegment("player-retrieve", c, func() error {
 				log.D(l, "Retrieving promoter/demoter member...")
-				requestor, err = models.GetPlayerByPublicID(db, membership.GameID, payload.RequestorPublicID)
+				requestor, err = models.GetPlayerByPublicID(tx, membership.GameID, payload.RequestorPublicID)
 				if err != nil {
-					log.E(l, "Promoter/Demoter member retrieval failed.", func(cm log.CM) {
-						cm.Write(zap.Error(err))
-					})
+					txErr := rb(err)
+					if txErr == nil {
+						log.E(l, "Promoter/Demoter member retrieval failed.", func(cm log.CM) {
+							cm.Write(zap.Error(err))
+						})
+					}
 					return err
 				}
 				return nil
@@ -775,14 +936,17 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 			}
 
 			err = dispatchMembershipHookByID(
-				app, db, hookType,
+				app, tx, hookType,
 				membership.GameID, membership.ClanID, membership.PlayerID,
 				requestor.ID, membership.Message, membership.Level,
 			)
 			if err != nil {
-				log.E(l, "Promote/Demote member hook dispatch failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Promote/Demote member hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
 				return err
 			}
 			return nil
@@ -792,6 +956,11 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
+		err = app.Commit(tx, "Member promotion/demotion", c, l)
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
 		log.I(l, "Member promoted/demoted successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
@@ -801,3 +970,148 @@ func PromoteOrDemoteMembershipHandler(app *App, action string) func(c echo.Conte
 		}, c)
 	}
 }
+
+// BanOrUnbanMembershipHandler is the handler responsible for banning or unbanning a player from a clan
+func BanOrUnbanMembershipHandler(app *App, action string) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		var payload *BasePayloadWithRequestorAndPlayerPublicIDs
+		var game *models.Game
+		var membership, beforeMembership *models.Membership
+		var status int
+		var err error
+		var tx interfaces.Transaction
+
+		c.Set("route", "BanOrUnbanMember")
+		start := time.Now()
+		clanPublicID := c.Param("clanPublicID")
+
+		l := app.Logger.With(
+			zap.String("source", "membershipHandler"),
+			zap.String("operation", "banOrUnbanMembership"),
+			zap.String("clanPublicID", clanPublicID),
+			zap.String("action", action),
+		)
+
+		err = WithSegment("payload", c, func() error {
+			payload, game, status, err = getPayloadAndGame(app, c, l)
+			if err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(status, err.Error(), c)
+		}
+
+		l = l.With(
+			zap.String("gameID", game.PublicID),
+			zap.String("playerPublicID", payload.PlayerPublicID),
+			zap.String("requestorPublicID", payload.RequestorPublicID),
+		)
+
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Banning/Unbanning member failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("membership-ban-unban", c, func() error {
+			err = WithSegment("tx-begin", c, func() error {
+				tx, err = app.BeginTrans(c.StdContext(), l)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			log.D(l, "DB Tx begun successful.")
+
+			beforeMembership, _ = models.GetMembershipByClanAndPlayerPublicID(tx, game.PublicID, clanPublicID, payload.PlayerPublicID)
+
+			log.D(l, "Banning/Unbanning member...")
+			banOrUnban := models.BanMember
+			if action == "unban" {
+				banOrUnban = models.UnbanMember
+			}
+			membership, err = banOrUnban(
+				tx,
+				game,
+				game.PublicID,
+				payload.PlayerPublicID,
+				clanPublicID,
+				payload.RequestorPublicID,
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Member ban/unban failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+
+			auditAction := models.AuditMembershipBanned
+			if action == "unban" {
+				auditAction = models.AuditMembershipUnbanned
+			}
+			err = createAuditEvent(
+				c, tx, game.PublicID, auditAction,
+				payload.RequestorPublicID, clanPublicID, payload.PlayerPublicID,
+				membershipAuditSnapshot(beforeMembership), membershipAuditSnapshot(membership),
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Member ban/unban audit failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		err = WithSegment("hook-dispatch", c, func() error {
+			hookType := models.MembershipBannedHook
+			if action == "unban" {
+				hookType = models.MembershipUnbannedHook
+			}
+
+			err = dispatchMembershipHookByPublicID(
+				app, tx, hookType,
+				game.PublicID, clanPublicID, payload.PlayerPublicID,
+				payload.RequestorPublicID, membership.Level,
+			)
+			if err != nil {
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Ban/Unban member hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		err = app.Commit(tx, "Member ban/unban", c, l)
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
+		log.I(l, "Member banned/unbanned successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+
+		return SucceedWith(map[string]interface{}{}, c)
+	}
+}
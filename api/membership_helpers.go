@@ -44,6 +44,33 @@ func getMembershipOptionalParameters(app *App, c echo.Context) (*membershipOptio
 	}, nil
 }
 
+// runMembershipPreHooks calls the pre hooks of membership applications and invitations
+// and updates the membership fields changed by them
+func runMembershipPreHooks(
+	app *App, c echo.Context, gameID, clanPublicID, playerPublicID, requestorPublicID string,
+	level, message *string,
+) error {
+	return WithSegment("hook-pre", c, func() error {
+		membership, err := app.RunPreHooks(c.StdContext(), gameID, models.MembershipApplicationCreatedHook, map[string]interface{}{
+			"gameID":    gameID,
+			"clan":      map[string]interface{}{"publicID": clanPublicID},
+			"player":    map[string]interface{}{"publicID": playerPublicID},
+			"requestor": map[string]interface{}{"publicID": requestorPublicID},
+			"membership": map[string]interface{}{
+				"level":   *level,
+				"message": *message,
+			},
+		}, "membership", membershipPreHookFields)
+		if err != nil {
+			return err
+		}
+
+		*level = membership["level"].(string)
+		*message = membership["message"].(string)
+		return nil
+	})
+}
+
 func dispatchMembershipHookByPublicID(app *App, db models.DB, hookType int, gameID, clanID, playerID, requestorID, membershipLevel string) error {
 	clan, err := models.GetClanByPublicID(db, gameID, clanID)
 	if err != nil {
@@ -142,9 +169,7 @@ func dispatchMembershipHook(app *App, db models.DB, hookType int, gameID string,
 	if message != "" {
 		result["message"] = message
 	}
-	app.DispatchHooks(gameID, hookType, result)
-
-	return nil
+	return app.DispatchHooks(db, gameID, hookType, result)
 }
 
 func dispatchApproveDenyMembershipHook(app *App, db models.DB, hookType int, gameID string, clan *models.Clan, player *models.Player, requestor *models.Player, creator *models.Player, message, playerMembershipLevel string) error {
@@ -172,9 +197,7 @@ func dispatchApproveDenyMembershipHook(app *App, db models.DB, hookType int, gam
 	if message != "" {
 		result["message"] = message
 	}
-	app.DispatchHooks(gameID, hookType, result)
-
-	return nil
+	return app.DispatchHooks(db, gameID, hookType, result)
 }
 
 func getPayloadAndGame(app *App, c echo.Context, l zap.Logger) (*BasePayloadWithRequestorAndPlayerPublicIDs, *models.Game, int, error) {
@@ -893,6 +893,92 @@ var _ = Describe("Membership API Handler", func() {
 		})
 	})
 
+	Describe("Ban Or Unban Member Handler", func() {
+		It("Should ban and unban member", func() {
+			game, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "ban"), payload)
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			membership, err := models.GetMembershipByClanAndPlayerPublicID(db, game.PublicID, clan.PublicID, players[0].PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(membership.BannedAt).To(BeNumerically(">", 0))
+
+			invitePayload := map[string]interface{}{
+				"level":             "Member",
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, body = PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "invitation"), invitePayload)
+			Expect(status).To(Equal(http.StatusForbidden))
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal(fmt.Sprintf("Player %s is banned from clan %s.", players[0].PublicID, clan.PublicID)))
+
+			status, _ = PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "unban"), payload)
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "invitation"), invitePayload)
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("Should not ban member if requestor does not have enough level", func() {
+			game, clan, _, players, _, err := models.GetClanWithMemberships(testDb, 2, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": players[1].PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "ban"), payload)
+			Expect(status).To(Equal(http.StatusForbidden))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+		})
+
+		It("Should not ban the clan owner", func() {
+			game, clan, owner, players, memberships, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			memberships[0].Level = "CoLeader"
+			_, err = testDb.Update(memberships[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    owner.PublicID,
+				"requestorPublicID": players[0].PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "ban"), payload)
+			Expect(status).To(Equal(http.StatusForbidden))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeFalse())
+
+			_, err = models.GetMembershipByClanAndPlayerPublicID(db, game.PublicID, clan.PublicID, owner.PublicID)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should not unban player that is not banned", func() {
+			game, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, _ := PostJSON(a, CreateMembershipRoute(game.PublicID, clan.PublicID, "unban"), payload)
+			Expect(status).To(Equal(http.StatusConflict))
+		})
+	})
+
 	Describe("Membership Hooks", func() {
 		It("Apply should call membership application created hook with non empty message", func() {
 			hooks, err := models.GetHooksForRoutes(testDb, []string{
@@ -1252,5 +1338,37 @@ var _ = Describe("Membership API Handler", func() {
 			response := (*responses)[0]["payload"].(map[string]interface{})
 			validateMembershipHookResponse(response, gameID, clan, players[0], owner)
 		})
+
+		It("should call membership banned hook", func() {
+			hooks, err := models.GetHooksForRoutes(testDb, []string{
+				"http://localhost:52525/membershipbanned",
+			}, models.MembershipBannedHook)
+			Expect(err).NotTo(HaveOccurred())
+			responses := startRouteHandler([]string{"/membershipbanned"}, 52525)
+
+			_, clan, owner, players, _, err := models.GetClanWithMemberships(testDb, 1, 0, 0, 0, hooks[0].GameID, "", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			gameID := hooks[0].GameID
+			clanPublicID := clan.PublicID
+
+			payload := map[string]interface{}{
+				"playerPublicID":    players[0].PublicID,
+				"requestorPublicID": owner.PublicID,
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(gameID, clanPublicID, "ban"), payload)
+
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["success"]).To(BeTrue())
+
+			Eventually(func() int {
+				return len(*responses)
+			}).Should(Equal(1))
+
+			response := (*responses)[0]["payload"].(map[string]interface{})
+			validateMembershipHookResponse(response, gameID, clan, players[0], owner)
+		})
 	})
 })
@@ -17,6 +17,7 @@ import (
 
 	"github.com/getsentry/raven-go"
 	"github.com/labstack/echo"
+	"github.com/satori/go.uuid"
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/util"
 	"github.com/uber-go/zap"
@@ -237,6 +238,7 @@ func (l *LoggerMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 			zap.String("ip", ip),
 			zap.String("method", method),
 			zap.String("path", path),
+			zap.String("requestID", getRequestID(c)),
 		)
 
 		//request failed
@@ -293,3 +295,34 @@ func (nr *NewRelicMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 		return nil
 	}
 }
+
+// RequestIDHeader is the header that identifies a request
+const RequestIDHeader = "X-Request-ID"
+
+//NewRequestIDMiddleware returns a new request id middleware
+func NewRequestIDMiddleware() *RequestIDMiddleware {
+	return &RequestIDMiddleware{}
+}
+
+//RequestIDMiddleware identifies each request with the X-Request-ID header sent by the client
+//or with a newly generated one
+type RequestIDMiddleware struct{}
+
+// Serve serves the middleware
+func (r *RequestIDMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header().Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > 255 {
+			requestID = uuid.NewV4().String()
+		}
+		c.Set("requestID", requestID)
+		c.Response().Header().Set(RequestIDHeader, requestID)
+		return next(c)
+	}
+}
+
+// getRequestID returns the id of the current request
+func getRequestID(c echo.Context) string {
+	requestID, _ := c.Get("requestID").(string)
+	return requestID
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 
 	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
 	"github.com/topfreegames/khan/util"
 	"github.com/uber-go/zap"
 )
@@ -333,13 +334,112 @@ func (admip *ApproveOrDenyMembershipInvitationPayload) Validate() []string {
 
 //HookPayload maps the payload required to create or update hooks
 type HookPayload struct {
-	Type    int    `json:"type"`
-	HookURL string `json:"hookURL"`
+	Type          int    `json:"type"`
+	HookURL       string `json:"hookURL"`
+	Secret        string `json:"secret"`
+	Filter        string `json:"filter"`
+	BatchSize     int    `json:"batchSize"`
+	BatchInterval int    `json:"batchInterval"`
+	Kind          string `json:"kind"`
+	FailOpen      bool   `json:"failOpen"`
 }
 
 //Validate all the required fields
 func (hp *HookPayload) Validate() []string {
 	v := NewValidation()
 	v.validateRequiredString("hookURL", hp.HookURL)
+	v.validateCustom("secret", func() []string {
+		if len(hp.Secret) > 255 {
+			return []string{"secret should have at most 255 characters"}
+		}
+		return []string{}
+	})
+	v.validateCustom("filter", func() []string {
+		if hp.Filter == "" {
+			return []string{}
+		}
+		if _, err := models.ParseHookFilter(hp.Filter); err != nil {
+			return []string{err.Error()}
+		}
+		return []string{}
+	})
+	v.validateCustom("batchInterval", func() []string {
+		if hp.BatchSize < 0 || hp.BatchInterval < 0 {
+			return []string{"batchSize and batchInterval should be greater or equal to 0"}
+		}
+		return []string{}
+	})
+	v.validateCustom("kind", func() []string {
+		switch hp.Kind {
+		case "", models.HookKindPost:
+			return []string{}
+		case models.HookKindPre:
+			if hp.BatchSize > 0 || hp.BatchInterval > 0 {
+				return []string{"pre hooks can't be batched"}
+			}
+			return []string{}
+		}
+		return []string{fmt.Sprintf("kind should be %s or %s", models.HookKindPost, models.HookKindPre)}
+	})
+	return v.Errors()
+}
+
+//APIKeyPayload maps the payload required to create api keys
+type APIKeyPayload struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+//Validate all the required fields
+func (kp *APIKeyPayload) Validate() []string {
+	v := NewValidation()
+	v.validateRequiredString("scope", kp.Scope)
+	v.validateCustom("scope", func() []string {
+		if kp.Scope != "" && !models.IsValidAPIKeyScope(kp.Scope) {
+			return []string{fmt.Sprintf(
+				"scope should be %s, %s or %s",
+				models.APIKeyScopeReadOnly, models.APIKeyScopePlayerWrite, models.APIKeyScopeAdmin,
+			)}
+		}
+		return []string{}
+	})
+	v.validateCustom("name", func() []string {
+		if len(kp.Name) > 255 {
+			return []string{"name should have at most 255 characters"}
+		}
+		return []string{}
+	})
+	return v.Errors()
+}
+
+//PlayerTokenConfigPayload maps the payload required to configure player tokens
+type PlayerTokenConfigPayload struct {
+	Algorithm     string   `json:"algorithm"`
+	Key           string   `json:"key"`
+	Issuer        string   `json:"issuer"`
+	Audience      string   `json:"audience"`
+	AllowedRoutes []string `json:"allowedRoutes"`
+}
+
+//Validate all the required fields
+func (pp *PlayerTokenConfigPayload) Validate() []string {
+	v := NewValidation()
+	v.validateRequiredString("algorithm", pp.Algorithm)
+	v.validateRequiredString("key", pp.Key)
+	v.validateCustom("issuer", func() []string {
+		if len(pp.Issuer) > 255 || len(pp.Audience) > 255 {
+			return []string{"issuer and audience should have at most 255 characters"}
+		}
+		return []string{}
+	})
+	v.validateCustom("allowedRoutes", func() []string {
+		errors := []string{}
+		for _, route := range pp.AllowedRoutes {
+			if !IsPlayerTokenRoute(route) {
+				errors = append(errors, fmt.Sprintf("route %q can't be used with player tokens", route))
+			}
+		}
+		return errors
+	})
 	return v.Errors()
 }
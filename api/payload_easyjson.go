@@ -782,6 +782,18 @@ func easyjsonA8a797f8DecodeGithubComTopfreegamesKhanApi6(in *jlexer.Lexer, out *
 			out.Type = int(in.Int())
 		case "hookURL":
 			out.HookURL = string(in.String())
+		case "secret":
+			out.Secret = string(in.String())
+		case "filter":
+			out.Filter = string(in.String())
+		case "batchSize":
+			out.BatchSize = int(in.Int())
+		case "batchInterval":
+			out.BatchInterval = int(in.Int())
+		case "kind":
+			out.Kind = string(in.String())
+		case "failOpen":
+			out.FailOpen = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -816,6 +828,66 @@ func easyjsonA8a797f8EncodeGithubComTopfreegamesKhanApi6(out *jwriter.Writer, in
 		}
 		out.String(string(in.HookURL))
 	}
+	{
+		const prefix string = ",\"secret\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Secret))
+	}
+	{
+		const prefix string = ",\"filter\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Filter))
+	}
+	{
+		const prefix string = ",\"batchSize\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.BatchSize))
+	}
+	{
+		const prefix string = ",\"batchInterval\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.BatchInterval))
+	}
+	{
+		const prefix string = ",\"kind\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Kind))
+	}
+	{
+		const prefix string = ",\"failOpen\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.FailOpen))
+	}
 	out.RawByte('}')
 }
 
@@ -1618,3 +1690,209 @@ func (v ApplyForMembershipPayload) MarshalEasyJSON(w *jwriter.Writer) {
 func (v *ApplyForMembershipPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonA8a797f8DecodeGithubComTopfreegamesKhanApi12(l, v)
 }
+func easyjsonA8a797f8DecodeGithubComTopfreegamesKhanApi13(in *jlexer.Lexer, out *APIKeyPayload) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "scope":
+			out.Scope = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA8a797f8EncodeGithubComTopfreegamesKhanApi13(out *jwriter.Writer, in APIKeyPayload) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"scope\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Scope))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeyPayload) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA8a797f8EncodeGithubComTopfreegamesKhanApi13(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeyPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA8a797f8DecodeGithubComTopfreegamesKhanApi13(l, v)
+}
+func easyjsonA8a797f8DecodeGithubComTopfreegamesKhanApi14(in *jlexer.Lexer, out *PlayerTokenConfigPayload) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "algorithm":
+			out.Algorithm = string(in.String())
+		case "key":
+			out.Key = string(in.String())
+		case "issuer":
+			out.Issuer = string(in.String())
+		case "audience":
+			out.Audience = string(in.String())
+		case "allowedRoutes":
+			if in.IsNull() {
+				in.Skip()
+				out.AllowedRoutes = nil
+			} else {
+				in.Delim('[')
+				if out.AllowedRoutes == nil {
+					if !in.IsDelim(']') {
+						out.AllowedRoutes = make([]string, 0, 4)
+					} else {
+						out.AllowedRoutes = []string{}
+					}
+				} else {
+					out.AllowedRoutes = (out.AllowedRoutes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.AllowedRoutes = append(out.AllowedRoutes, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA8a797f8EncodeGithubComTopfreegamesKhanApi14(out *jwriter.Writer, in PlayerTokenConfigPayload) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"algorithm\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Algorithm))
+	}
+	{
+		const prefix string = ",\"key\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"issuer\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Issuer))
+	}
+	{
+		const prefix string = ",\"audience\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Audience))
+	}
+	{
+		const prefix string = ",\"allowedRoutes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.AllowedRoutes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.AllowedRoutes {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PlayerTokenConfigPayload) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA8a797f8EncodeGithubComTopfreegamesKhanApi14(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PlayerTokenConfigPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA8a797f8DecodeGithubComTopfreegamesKhanApi14(l, v)
+}
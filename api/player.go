@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo"
+	"github.com/topfreegames/extensions/gorp/interfaces"
 	"github.com/topfreegames/khan/log"
 	"github.com/topfreegames/khan/models"
 	"github.com/uber-go/zap"
@@ -25,8 +26,6 @@ func CreatePlayerHandler(app *App) func(c echo.Context) error {
 		start := time.Now()
 		gameID := c.Param("gameID")
 
-		db := app.Db(c.StdContext())
-
 		l := app.Logger.With(
 			zap.String("source", "playerHandler"),
 			zap.String("operation", "createPlayer"),
@@ -45,10 +44,30 @@ func CreatePlayerHandler(app *App) func(c echo.Context) error {
 		}
 
 		var player *models.Player
+		var tx interfaces.Transaction
+
+		//rollback function
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Creating player failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("tx-begin", c, func() error {
+			tx, err = app.BeginTrans(c.StdContext(), l)
+			return err
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
 		err = WithSegment("player-create", c, func() error {
 			log.D(l, "Creating player...")
 			player, err = models.CreatePlayer(
-				db,
+				tx,
 				gameID,
 				payload.PublicID,
 				payload.Name,
@@ -57,9 +76,12 @@ func CreatePlayerHandler(app *App) func(c echo.Context) error {
 			)
 
 			if err != nil {
-				log.E(l, "Player creation failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Player creation failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
 				return err
 			}
 			return nil
@@ -77,11 +99,14 @@ func CreatePlayerHandler(app *App) func(c echo.Context) error {
 		}
 
 		err = WithSegment("hook-dispatch", c, func() error {
-			err = app.DispatchHooks(gameID, models.PlayerCreatedHook, player.Serialize())
+			err = app.DispatchHooks(tx, gameID, models.PlayerCreatedHook, player.Serialize())
 			if err != nil {
-				log.E(l, "Player creation hook dispatch failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Player creation hook dispatch failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
 				return err
 			}
 			return nil
@@ -90,6 +115,11 @@ func CreatePlayerHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
+		err = app.Commit(tx, "Player created", c, l)
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
 		log.D(l, "Player created successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
@@ -123,6 +153,11 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusBadRequest, err.Error(), c)
 		}
 
+		version, checkVersion, err := getIfMatchVersion(c)
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
 		var player, beforeUpdatePlayer *models.Player
 		var game *models.Game
 
@@ -153,11 +188,37 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusBadRequest, err.Error(), c)
 		}
 
+		var tx interfaces.Transaction
+
+		//rollback function
+		rb := func(err error) error {
+			txErr := app.Rollback(tx, "Updating player failed", c, l, err)
+			if txErr != nil {
+				return txErr
+			}
+
+			return nil
+		}
+
+		err = WithSegment("tx-begin", c, func() error {
+			tx, err = app.BeginTrans(c.StdContext(), l)
+			return err
+		})
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
 		err = WithSegment("player-update", c, func() error {
 			err = WithSegment("player-update-query", c, func() error {
+				if checkVersion {
+					log.D(l, "Checking player version...")
+					if err = models.CheckPlayerVersion(tx, gameID, playerPublicID, version); err != nil {
+						return err
+					}
+				}
 				log.D(l, "Updating player...")
 				player, err = models.UpdatePlayer(
-					db,
+					tx,
 					gameID,
 					playerPublicID,
 					payload.Name,
@@ -167,26 +228,32 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 			})
 
 			if err != nil {
-				log.E(l, "Updating player failed.", func(cm log.CM) {
-					cm.Write(zap.Error(err))
-				})
+				txErr := rb(err)
+				if txErr == nil {
+					log.E(l, "Updating player failed.", func(cm log.CM) {
+						cm.Write(zap.Error(err))
+					})
+				}
 				return err
 			}
 			return nil
 		})
 		if err != nil {
-			return FailWith(http.StatusInternalServerError, err.Error(), c)
+			return FailWithError(err, c)
 		}
 
 		err = WithSegment("hook-dispatch", c, func() error {
 			shouldDispatch := validateUpdatePlayerDispatch(game, beforeUpdatePlayer, player, payload.Metadata, l)
 			if shouldDispatch {
 				log.D(l, "Dispatching player update hooks...")
-				err = app.DispatchHooks(gameID, models.PlayerUpdatedHook, player.Serialize())
+				err = app.DispatchHooks(tx, gameID, models.PlayerUpdatedHook, player.Serialize())
 				if err != nil {
-					log.E(l, "Update player hook dispatch failed.", func(cm log.CM) {
-						cm.Write(zap.Error(err))
-					})
+					txErr := rb(err)
+					if txErr == nil {
+						log.E(l, "Update player hook dispatch failed.", func(cm log.CM) {
+							cm.Write(zap.Error(err))
+						})
+					}
 					return err
 				}
 			}
@@ -196,10 +263,18 @@ func UpdatePlayerHandler(app *App) func(c echo.Context) error {
 			return FailWith(http.StatusInternalServerError, err.Error(), c)
 		}
 
+		err = app.Commit(tx, "Player updated", c, l)
+		if err != nil {
+			return FailWith(http.StatusInternalServerError, err.Error(), c)
+		}
+
 		log.D(l, "Player updated successfully.", func(cm log.CM) {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
-		return SucceedWith(map[string]interface{}{}, c)
+		setVersionETag(c, player.Version)
+		return SucceedWith(map[string]interface{}{
+			"version": player.Version,
+		}, c)
 	}
 }
 
@@ -257,6 +332,7 @@ func RetrievePlayerHandler(app *App) func(c echo.Context) error {
 			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
 		})
 
+		setVersionETag(c, player["version"].(int64))
 		return SucceedWith(player, c)
 	}
 }
@@ -0,0 +1,148 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// SetPlayerTokenConfigHandler is the handler responsible for configuring the game player tokens
+func SetPlayerTokenConfigHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "SetPlayerTokenConfig")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "SetPlayerTokenConfigHandler"),
+			zap.String("operation", "setPlayerTokenConfig"),
+			zap.String("gameID", gameID),
+		)
+
+		var payload PlayerTokenConfigPayload
+		err := WithSegment("payload", c, func() error {
+			if err := LoadJSONPayload(&payload, c, l); err != nil {
+				log.E(l, "Failed to parse json payload.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWith(http.StatusBadRequest, err.Error(), c)
+		}
+
+		_, err = app.GetGame(c.StdContext(), gameID)
+		if err != nil {
+			log.W(l, "Could not find game.")
+			return FailWith(http.StatusNotFound, err.Error(), c)
+		}
+
+		var config *models.PlayerTokenConfig
+		err = WithSegment("player-token-config-set", c, func() error {
+			log.D(l, "Setting player token config...")
+			config, err = models.SetPlayerTokenConfig(
+				app.Db(c.StdContext()),
+				gameID,
+				payload.Algorithm,
+				payload.Key,
+				payload.Issuer,
+				payload.Audience,
+				payload.AllowedRoutes,
+			)
+			if err != nil {
+				log.E(l, "Failed to set the player token config.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Set player token config successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.String("algorithm", config.Algorithm),
+				zap.Duration("duration", time.Now().Sub(start)),
+			)
+		})
+		return SucceedWith(config.Serialize(), c)
+	}
+}
+
+// RetrievePlayerTokenConfigHandler is the handler responsible for returning the game player token config
+func RetrievePlayerTokenConfigHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "RetrievePlayerTokenConfig")
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "RetrievePlayerTokenConfigHandler"),
+			zap.String("operation", "retrievePlayerTokenConfig"),
+			zap.String("gameID", gameID),
+		)
+
+		var config *models.PlayerTokenConfig
+		err := WithSegment("player-token-config-retrieve", c, func() error {
+			var err error
+			config, err = models.GetPlayerTokenConfigByGameID(app.Db(c.StdContext()), gameID)
+			if err != nil {
+				log.W(l, "Could not find player token config.")
+			}
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		return SucceedWith(config.Serialize(), c)
+	}
+}
+
+// DeletePlayerTokenConfigHandler is the handler responsible for disabling the game player tokens
+func DeletePlayerTokenConfigHandler(app *App) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		c.Set("route", "DeletePlayerTokenConfig")
+		start := time.Now()
+		gameID := c.Param("gameID")
+
+		l := app.Logger.With(
+			zap.String("source", "DeletePlayerTokenConfigHandler"),
+			zap.String("operation", "deletePlayerTokenConfig"),
+			zap.String("gameID", gameID),
+		)
+
+		err := WithSegment("player-token-config-delete", c, func() error {
+			log.D(l, "Deleting player token config...")
+			err := models.DeletePlayerTokenConfig(app.Db(c.StdContext()), gameID)
+			if err != nil {
+				log.E(l, "Failed to delete the player token config.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+			}
+			return err
+		})
+		if err != nil {
+			return FailWithError(err, c)
+		}
+
+		log.I(l, "Deleted player token config successfully.", func(cm log.CM) {
+			cm.Write(zap.Duration("duration", time.Now().Sub(start)))
+		})
+		return SucceedWith(map[string]interface{}{}, c)
+	}
+}
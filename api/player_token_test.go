@@ -0,0 +1,195 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+const playerTokenSecret = "0123456789abcdef0123456789abcdef"
+
+func getPlayerToken(claims jwt.MapClaims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	Expect(err).NotTo(HaveOccurred())
+	return token
+}
+
+var _ = Describe("Player Token API Handler", func() {
+	var db models.DB
+	var a *api.App
+	var game *models.Game
+
+	BeforeEach(func() {
+		a = GetDefaultTestApp()
+		db = a.Db(nil)
+
+		game = models.GameFactory.MustCreate().(*models.Game)
+		err := db.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Player Token Config Handlers", func() {
+		It("Should set, retrieve and delete the player token config", func() {
+			route := fmt.Sprintf("/games/%s/player-tokens", game.PublicID)
+			status, body := DoRequestWithHeaders(a, "PUT", route, fmt.Sprintf(`{
+				"algorithm": "HS256",
+				"key": "%s",
+				"issuer": "game-backend",
+				"allowedRoutes": ["GET /games/:gameID/clans"]
+			}`, playerTokenSecret), nil)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).NotTo(ContainSubstring(playerTokenSecret))
+
+			status, body = Get(a, route)
+			Expect(status).To(Equal(http.StatusOK))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["algorithm"]).To(Equal(models.PlayerTokenAlgorithmHS256))
+			Expect(result["issuer"]).To(Equal("game-backend"))
+			Expect(result["allowedRoutes"]).To(Equal([]interface{}{"GET /games/:gameID/clans"}))
+			Expect(result).NotTo(HaveKey("key"))
+
+			status, _ = Delete(a, route)
+			Expect(status).To(Equal(http.StatusOK))
+			status, _ = Get(a, route)
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("Should not set a player token config with routes that can't use player tokens", func() {
+			status, body := DoRequestWithHeaders(a, "PUT", fmt.Sprintf("/games/%s/player-tokens", game.PublicID), fmt.Sprintf(`{
+				"algorithm": "HS256",
+				"key": "%s",
+				"allowedRoutes": ["PUT /games/:gameID"]
+			}`, playerTokenSecret), nil)
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(body).To(ContainSubstring("can't be used with player tokens"))
+		})
+
+		It("Should not set a player token config with a short HS256 key", func() {
+			status, _ := DoRequestWithHeaders(a, "PUT", fmt.Sprintf("/games/%s/player-tokens", game.PublicID), `{
+				"algorithm": "HS256",
+				"key": "secret"
+			}`, nil)
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Player Token Middleware", func() {
+		var tokenApp *api.App
+		var player, otherPlayer *models.Player
+
+		BeforeEach(func() {
+			tokenApp = GetTestAppWithPlayerTokens("admin", "secret")
+
+			_, err := models.SetPlayerTokenConfig(
+				db, game.PublicID, models.PlayerTokenAlgorithmHS256, playerTokenSecret, "", "",
+				[]string{"PUT /games/:gameID/players/:playerPublicID", "POST /games/:gameID/clans"},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			player = models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*models.Player)
+			otherPlayer = models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*models.Player)
+			err = db.Insert(player)
+			Expect(err).NotTo(HaveOccurred())
+			err = db.Insert(otherPlayer)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		bearer := func(claims jwt.MapClaims) map[string]string {
+			return map[string]string{"Authorization": "Bearer " + getPlayerToken(claims, playerTokenSecret)}
+		}
+
+		validClaims := func() jwt.MapClaims {
+			return jwt.MapClaims{"sub": player.PublicID, "exp": time.Now().Add(time.Hour).Unix()}
+		}
+
+		updatePlayer := func(publicID string, headers map[string]string) int {
+			status, _ := DoRequestWithHeaders(
+				tokenApp, "PUT", fmt.Sprintf("/games/%s/players/%s", game.PublicID, publicID),
+				`{"name": "new name", "metadata": {}}`, headers,
+			)
+			return status
+		}
+
+		It("Should let players update themselves", func() {
+			Expect(updatePlayer(player.PublicID, bearer(validClaims()))).To(Equal(http.StatusOK))
+
+			dbPlayer, err := models.GetPlayerByPublicID(db, game.PublicID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Name).To(Equal("new name"))
+		})
+
+		It("Should not let players act as other players", func() {
+			Expect(updatePlayer(otherPlayer.PublicID, bearer(validClaims()))).To(Equal(http.StatusForbidden))
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"publicID":         uuid.NewV4().String(),
+				"name":             "clan",
+				"ownerPublicID":    otherPlayer.PublicID,
+				"metadata":         map[string]interface{}{},
+				"allowApplication": true,
+				"autoJoin":         true,
+			})
+			status, _ := DoRequestWithHeaders(
+				tokenApp, "POST", fmt.Sprintf("/games/%s/clans", game.PublicID), string(payload), bearer(validClaims()),
+			)
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("Should only allow the routes chosen by the game", func() {
+			status, _ := DoRequestWithHeaders(
+				tokenApp, "GET", fmt.Sprintf("/games/%s/players/%s", game.PublicID, player.PublicID), "", bearer(validClaims()),
+			)
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, _ = DoRequestWithHeaders(
+				tokenApp, "GET", fmt.Sprintf("/games/%s/hooks", game.PublicID), "", bearer(validClaims()),
+			)
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("Should reject invalid tokens", func() {
+			expired := validClaims()
+			expired["exp"] = time.Now().Add(-time.Minute).Unix()
+			Expect(updatePlayer(player.PublicID, bearer(expired))).To(Equal(http.StatusUnauthorized))
+
+			withoutExpiration := validClaims()
+			delete(withoutExpiration, "exp")
+			Expect(updatePlayer(player.PublicID, bearer(withoutExpiration))).To(Equal(http.StatusUnauthorized))
+
+			wrongSecret := map[string]string{
+				"Authorization": "Bearer " + getPlayerToken(validClaims(), "another secret of thirty two chars"),
+			}
+			Expect(updatePlayer(player.PublicID, wrongSecret)).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Should reject tokens of games without player token config", func() {
+			err := models.DeletePlayerTokenConfig(db, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updatePlayer(player.PublicID, bearer(validClaims()))).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Should still require credentials from requests without tokens", func() {
+			Expect(updatePlayer(player.PublicID, nil)).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -0,0 +1,214 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+// clanPreHookFields are the clan fields pre hooks are allowed to change
+var clanPreHookFields = []string{"name", "metadata", "allowApplication", "autoJoin"}
+
+// membershipPreHookFields are the membership fields pre hooks are allowed to change
+var membershipPreHookFields = []string{"level", "message"}
+
+// preHookDecision is the response of a pre hook
+type preHookDecision struct {
+	Allow   *bool                  `json:"allow"`
+	Reason  string                 `json:"reason"`
+	Changes map[string]interface{} `json:"changes"`
+}
+
+// RunPreHooks calls the pre hooks of the event synchronously, in the order they were
+// created, before the action happens. Each hook receives the payload with the changes
+// of the previous hooks and may allow the action, deny it or change the allowedFields
+// of payload[entity]. The entity with the changes of all the hooks is returned.
+// Denials are returned as *models.PreHookDeniedError. Hooks that fail or time out are
+// skipped if they fail open, otherwise a *models.PreHookFailedError is returned. If the
+// hooks can't be retrieved the error is returned, since fail-closed hooks can't be skipped.
+func (app *App) RunPreHooks(
+	ctx context.Context, gameID string, eventType int,
+	payload map[string]interface{}, entity string, allowedFields []string,
+) (map[string]interface{}, error) {
+	l := app.Logger.With(
+		zap.String("source", "app"),
+		zap.String("operation", "RunPreHooks"),
+		zap.String("gameID", gameID),
+		zap.Int("eventType", eventType),
+	)
+
+	hooks, err := app.getHooks(ctx, l, gameID, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, _ := payload[entity].(map[string]interface{})
+	for _, hook := range hooks {
+		if !hook.Pre() || !app.Dispatcher.matchHookFilter(l, gameID, hook, payload) {
+			continue
+		}
+
+		decision, err := app.Dispatcher.callPreHook(ctx, l, gameID, eventType, hook, payload)
+		var changed map[string]interface{}
+		if err == nil && *decision.Allow {
+			changed, err = applyPreHookChanges(fields, decision.Changes, allowedFields)
+		}
+		if err != nil {
+			if hook.FailOpen {
+				log.W(l, "Pre hook failed. Skipping it since it fails open.", func(cm log.CM) {
+					cm.Write(zap.String("hookPublicID", hook.PublicID), zap.Error(err))
+				})
+				continue
+			}
+			log.E(l, "Pre hook failed.", func(cm log.CM) {
+				cm.Write(zap.String("hookPublicID", hook.PublicID), zap.Error(err))
+			})
+			return nil, &models.PreHookFailedError{HookPublicID: hook.PublicID, Reason: err.Error()}
+		}
+
+		if !*decision.Allow {
+			log.I(l, "Action denied by pre hook.", func(cm log.CM) {
+				cm.Write(zap.String("hookPublicID", hook.PublicID), zap.String("reason", decision.Reason))
+			})
+			return nil, &models.PreHookDeniedError{Reason: decision.Reason}
+		}
+
+		fields = changed
+		payload[entity] = fields
+	}
+	return fields, nil
+}
+
+// applyPreHookChanges returns a copy of the fields with the changes of a pre hook
+// Only the allowed fields can be changed and they must keep their types.
+func applyPreHookChanges(fields, changes map[string]interface{}, allowedFields []string) (map[string]interface{}, error) {
+	changed := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		changed[field] = value
+	}
+
+	for field, value := range changes {
+		allowed := false
+		for _, allowedField := range allowedFields {
+			allowed = allowed || allowedField == field
+		}
+		if !allowed {
+			return nil, fmt.Errorf("field %s can't be changed", field)
+		}
+		if reflect.TypeOf(value) != reflect.TypeOf(fields[field]) {
+			return nil, fmt.Errorf("field %s can't change its type", field)
+		}
+		changed[field] = value
+	}
+	return changed, nil
+}
+
+// callPreHook requests the pre hook URL, interpolated with the given payload, and
+// returns its decision. The request is canceled after webhooks.preHooksTimeout.
+func (d *Dispatcher) callPreHook(
+	ctx context.Context, l zap.Logger, gameID string, eventType int,
+	hook *models.Hook, payload map[string]interface{},
+) (*preHookDecision, error) {
+	app := d.app
+	timeout := time.Duration(app.Config.GetInt("webhooks.preHooksTimeout")) * time.Millisecond
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	requestURL, err := d.interpolateURL(hook.URL, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	body := make(map[string]interface{}, len(payload)+4)
+	for key, value := range payload {
+		body[key] = value
+	}
+	body["type"] = eventType
+	body["kind"] = models.HookKindPre
+	body["id"] = uuid.NewV4()
+	body["timestamp"] = time.Now().Format(time.RFC3339)
+	payloadJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HookTimestampHeader, timestamp)
+	if hook.Secret != "" {
+		req.Header.Set(HookSignatureHeader, signHookPayload(hook.Secret, timestamp, payloadJSON))
+	}
+	req = req.WithContext(ctx)
+
+	log.D(l, "Requesting pre hook URL...", func(cm log.CM) {
+		cm.Write(zap.String("requestURL", requestURL))
+	})
+
+	start := time.Now()
+	resp, err := d.httpClient.Do(req)
+	statusCode := http.StatusInternalServerError
+	if err == nil {
+		statusCode = resp.StatusCode
+	}
+	app.DDStatsD.Timing(requestingHookMilliseconds, time.Since(start),
+		fmt.Sprintf("error:%t", statusCode > 399),
+		fmt.Sprintf("url:%s", hook.URL),
+		fmt.Sprintf("game:%s", gameID),
+		fmt.Sprintf("status:%d", statusCode),
+		fmt.Sprintf("kind:%s", models.HookKindPre),
+	)
+	if err != nil {
+		app.addError()
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode > 399 {
+		app.addError()
+		return nil, fmt.Errorf("pre hook responded with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var decision preHookDecision
+	err = json.Unmarshal(respBody, &decision)
+	if err != nil {
+		return nil, fmt.Errorf("pre hook response is not valid JSON: %s", err.Error())
+	}
+	if decision.Allow == nil {
+		return nil, errors.New("pre hook response does not have the allow field")
+	}
+
+	log.D(l, "Pre hook requested successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.String("requestURL", requestURL),
+			zap.Bool("allow", *decision.Allow),
+		)
+	})
+	return &decision, nil
+}
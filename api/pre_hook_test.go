@@ -0,0 +1,225 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/Pallinder/go-randomdata"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+// startPreHookServer starts a pre hook that answers every request with the given response
+func startPreHookServer(response string, requests *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, _ := ioutil.ReadAll(r.Body)
+		var payload map[string]interface{}
+		json.Unmarshal(bs, &payload)
+		*requests = append(*requests, payload)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+}
+
+var _ = Describe("Pre Hooks", func() {
+	var testDb models.DB
+	var a *api.App
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		a = GetDefaultTestApp()
+	})
+
+	createPreHook := func(gameID string, eventType int, url string, failOpen bool) {
+		_, err := models.CreateHook(testDb, gameID, eventType, url, "", "", 0, 0, models.HookKindPre, failOpen)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	createClan := func(player *models.Player, name string) (int, map[string]interface{}) {
+		payload := map[string]interface{}{
+			"publicID":         uuid.NewV4().String(),
+			"name":             name,
+			"ownerPublicID":    player.PublicID,
+			"metadata":         map[string]interface{}{"x": "a"},
+			"allowApplication": true,
+			"autoJoin":         true,
+		}
+		status, body := PostJSON(a, GetGameRoute(player.GameID, "/clans"), payload)
+		var result map[string]interface{}
+		json.Unmarshal([]byte(body), &result)
+		result["publicID"] = payload["publicID"]
+		return status, result
+	}
+
+	Describe("Create Clan", func() {
+		It("Should create the clan if the pre hook allows it", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			requests := []map[string]interface{}{}
+			server := startPreHookServer(`{"allow": true}`, &requests)
+			defer server.Close()
+			createPreHook(player.GameID, models.ClanCreatedHook, server.URL, false)
+
+			name := randomdata.FullName(randomdata.RandomGender)
+			status, result := createClan(player, name)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(result["success"]).To(BeTrue())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0]["kind"]).To(Equal(models.HookKindPre))
+			Expect(requests[0]["gameID"]).To(Equal(player.GameID))
+			clan := requests[0]["clan"].(map[string]interface{})
+			Expect(clan["publicID"]).To(Equal(result["publicID"]))
+			Expect(clan["name"]).To(Equal(name))
+			Expect(clan["ownerPublicID"]).To(Equal(player.PublicID))
+		})
+
+		It("Should not create the clan if the pre hook denies it", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			requests := []map[string]interface{}{}
+			server := startPreHookServer(`{"allow": false, "reason": "clan name is not allowed"}`, &requests)
+			defer server.Close()
+			createPreHook(player.GameID, models.ClanCreatedHook, server.URL, false)
+
+			status, result := createClan(player, "forbidden name")
+			Expect(status).To(Equal(http.StatusForbidden))
+			Expect(result["success"]).To(BeFalse())
+			Expect(result["reason"]).To(Equal("clan name is not allowed"))
+
+			_, err = models.GetClanByPublicID(testDb, player.GameID, result["publicID"].(string))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should create the clan with the changes of the pre hook", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			requests := []map[string]interface{}{}
+			server := startPreHookServer(
+				`{"allow": true, "changes": {"name": "clean name", "autoJoin": false}}`, &requests,
+			)
+			defer server.Close()
+			createPreHook(player.GameID, models.ClanCreatedHook, server.URL, false)
+
+			status, result := createClan(player, "dirty name")
+			Expect(status).To(Equal(http.StatusOK))
+
+			dbClan, err := models.GetClanByPublicID(testDb, player.GameID, result["publicID"].(string))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Name).To(Equal("clean name"))
+			Expect(dbClan.AutoJoin).To(BeFalse())
+			Expect(dbClan.AllowApplication).To(BeTrue())
+		})
+
+		It("Should fail if a fail-closed pre hook changes a field that is not allowed", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			requests := []map[string]interface{}{}
+			server := startPreHookServer(`{"allow": true, "changes": {"ownerPublicID": "other"}}`, &requests)
+			defer server.Close()
+			createPreHook(player.GameID, models.ClanCreatedHook, server.URL, false)
+
+			status, result := createClan(player, randomdata.FullName(randomdata.RandomGender))
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			Expect(result["reason"]).To(ContainSubstring("field ownerPublicID can't be changed"))
+		})
+
+		It("Should fail if a fail-closed pre hook is unavailable", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+			createPreHook(player.GameID, models.ClanCreatedHook, "http://localhost:52526/unavailable", false)
+
+			status, result := createClan(player, randomdata.FullName(randomdata.RandomGender))
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			Expect(result["success"]).To(BeFalse())
+
+			_, err = models.GetClanByPublicID(testDb, player.GameID, result["publicID"].(string))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should create the clan if a fail-open pre hook is unavailable", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+			createPreHook(player.GameID, models.ClanCreatedHook, "http://localhost:52526/unavailable", true)
+
+			status, result := createClan(player, randomdata.FullName(randomdata.RandomGender))
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(result["success"]).To(BeTrue())
+		})
+
+		It("Should fail if a fail-closed pre hook answers with an invalid response", func() {
+			_, player, err := models.CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			requests := []map[string]interface{}{}
+			server := startPreHookServer(`{"reason": "missing allow"}`, &requests)
+			defer server.Close()
+			createPreHook(player.GameID, models.ClanCreatedHook, server.URL, false)
+
+			status, _ := createClan(player, randomdata.FullName(randomdata.RandomGender))
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+
+	Describe("Apply For Membership", func() {
+		It("Should not create the membership if the pre hook denies it", func() {
+			_, clan, _, _, _, err := models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+			clan.AllowApplication = true
+			_, err = testDb.Update(clan)
+			Expect(err).NotTo(HaveOccurred())
+
+			player := models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": clan.GameID,
+			}).(*models.Player)
+			err = testDb.Insert(player)
+			Expect(err).NotTo(HaveOccurred())
+
+			requests := []map[string]interface{}{}
+			server := startPreHookServer(`{"allow": false, "reason": "player level is too low"}`, &requests)
+			defer server.Close()
+			createPreHook(clan.GameID, models.MembershipApplicationCreatedHook, server.URL, false)
+
+			payload := map[string]interface{}{
+				"level":          "Member",
+				"playerPublicID": player.PublicID,
+				"message":        "let me in",
+			}
+			status, body := PostJSON(a, CreateMembershipRoute(clan.GameID, clan.PublicID, "application"), payload)
+			Expect(status).To(Equal(http.StatusForbidden))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["reason"]).To(Equal("player level is too low"))
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0]["player"].(map[string]interface{})["publicID"]).To(Equal(player.PublicID))
+			Expect(requests[0]["requestor"].(map[string]interface{})["publicID"]).To(Equal(player.PublicID))
+			membership := requests[0]["membership"].(map[string]interface{})
+			Expect(membership["level"]).To(Equal("Member"))
+			Expect(membership["message"]).To(Equal("let me in"))
+
+			_, err = models.GetValidMembershipByClanAndPlayerPublicID(testDb, clan.GameID, clan.PublicID, player.PublicID)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,240 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	"github.com/labstack/echo"
+	"github.com/topfreegames/khan/log"
+	"github.com/uber-go/zap"
+)
+
+const rateLimitedRequests = "rate_limited_requests"
+
+// rate limit route classes
+const (
+	RateLimitClassRead       = "read"
+	RateLimitClassSearch     = "search"
+	RateLimitClassWrite      = "write"
+	RateLimitClassMembership = "membership"
+)
+
+// rate limit scopes, each request takes a token from the bucket of its game and, if the requestor
+// is known, from the bucket of its player
+const (
+	rateLimitScopeGame   = "game"
+	rateLimitScopePlayer = "player"
+)
+
+// takeRateLimitTokens refills the token buckets in KEYS and takes a token from each of them, only if
+// all of them have one. ARGV has the current time in milliseconds followed by the rate, in tokens per
+// second, and the burst of each bucket. It returns whether the tokens were taken and, if not, how many
+// milliseconds until they can be and the index of the bucket that is the furthest from having one.
+var takeRateLimitTokens = redis.NewScript(-1, `
+local now = tonumber(ARGV[1])
+local tokens = {}
+local retryAfter = 0
+local limited = 0
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[2 * i])
+	local burst = tonumber(ARGV[2 * i + 1])
+	local bucket = redis.call("HMGET", key, "tokens", "ts")
+	local available = tonumber(bucket[1]) or burst
+	local ts = tonumber(bucket[2]) or now
+	available = math.min(burst, available + math.max(0, now - ts) * rate / 1000)
+	if available < 1 and math.ceil((1 - available) * 1000 / rate) > retryAfter then
+		retryAfter = math.ceil((1 - available) * 1000 / rate)
+		limited = i
+	end
+	tokens[i] = available
+end
+local allowed = 0
+if retryAfter == 0 then
+	allowed = 1
+end
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[2 * i])
+	local burst = tonumber(ARGV[2 * i + 1])
+	redis.call("HMSET", key, "tokens", tostring(tokens[i] - allowed), "ts", now)
+	redis.call("PEXPIRE", key, math.ceil(burst * 1000 / rate) + 1000)
+end
+return {allowed, retryAfter, limited}
+`)
+
+// RateLimitRouteClass returns the rate limit class of the route, or an empty string if the route
+// is not rate limited. Only game routes are rate limited.
+func RateLimitRouteClass(method, path string) string {
+	route := strings.TrimPrefix(path, gameRoutesPrefix)
+	if route == path || (route != "" && !strings.HasPrefix(route, "/")) {
+		return ""
+	}
+	switch {
+	case route == "/clans/search" || route == "/clans/autocomplete" || route == "/clans/top":
+		return RateLimitClassSearch
+	case strings.Contains(route, "/memberships/"):
+		return RateLimitClassMembership
+	case method == echo.GET || method == echo.HEAD:
+		return RateLimitClassRead
+	}
+	return RateLimitClassWrite
+}
+
+// rateLimit is a token bucket limit, allowing bursts of Burst requests refilled at Rate requests per second
+type rateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+//NewRateLimitMiddleware returns a new rate limit middleware
+func NewRateLimitMiddleware(app *App) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		App: app,
+	}
+}
+
+//RateLimitMiddleware limits the requests of each game and player with token buckets stored in redis
+//Limits are configured per route class in rateLimit.classes and can be overridden per game in
+//rateLimit.games. Requests are allowed if redis can't be reached.
+type RateLimitMiddleware struct {
+	App *App
+}
+
+// limit returns the limit of the class for the game, in the given scope
+func (m *RateLimitMiddleware) limit(gameID, class, scope string) (rateLimit, bool) {
+	key := fmt.Sprintf("rateLimit.games.%s.%s.%s", gameID, class, scope)
+	if !m.App.Config.IsSet(key + ".rate") {
+		key = fmt.Sprintf("rateLimit.classes.%s.%s", class, scope)
+	}
+	limit := rateLimit{
+		Rate:  m.App.Config.GetFloat64(key + ".rate"),
+		Burst: m.App.Config.GetInt(key + ".burst"),
+	}
+	if limit.Rate <= 0 {
+		return limit, false
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return limit, true
+}
+
+// requestorPublicID returns the public id of the player making the request, if it is known
+func (m *RateLimitMiddleware) requestorPublicID(c echo.Context, route string) string {
+	if playerPublicID, ok := c.Get("playerPublicID").(string); ok {
+		return playerPublicID
+	}
+	requestor, ok := playerTokenRequestors[route]
+	if !ok || requestor.Source == "" {
+		return ""
+	}
+	playerPublicID, _ := requestor.publicID(c)
+	return playerPublicID
+}
+
+// take takes a token from each bucket, returning whether the request is allowed and, if not, when
+// it can be retried and which bucket limited it
+func (m *RateLimitMiddleware) take(keys []string, limits []rateLimit) (bool, time.Duration, int, error) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	args := make([]interface{}, 0, 3*len(keys)+2)
+	args = append(args, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, time.Now().UnixNano()/int64(time.Millisecond))
+	for _, limit := range limits {
+		args = append(args, limit.Rate, limit.Burst)
+	}
+
+	replies, err := redis.Int64s(takeRateLimitTokens.Do(conn, args...))
+	if err != nil {
+		return true, 0, 0, err
+	}
+	return replies[0] == 1, time.Duration(replies[1]) * time.Millisecond, int(replies[2]) - 1, nil
+}
+
+// Serve serves the middleware
+func (m *RateLimitMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method()
+		class := RateLimitRouteClass(method, c.Path())
+		if class == "" {
+			return next(c)
+		}
+
+		gameID := c.Param("gameID")
+		route := method + " " + c.Path()
+		prefix := fmt.Sprintf("%skhan:rate-limit:%s:%s", workers.Config.Namespace, gameID, class)
+		var keys, scopes []string
+		var limits []rateLimit
+		if limit, ok := m.limit(gameID, class, rateLimitScopeGame); ok {
+			keys = append(keys, prefix)
+			scopes = append(scopes, rateLimitScopeGame)
+			limits = append(limits, limit)
+		}
+		if playerPublicID := m.requestorPublicID(c, route); playerPublicID != "" {
+			if limit, ok := m.limit(gameID, class, rateLimitScopePlayer); ok {
+				keys = append(keys, fmt.Sprintf("%s:%s", prefix, playerPublicID))
+				scopes = append(scopes, rateLimitScopePlayer)
+				limits = append(limits, limit)
+			}
+		}
+		if len(keys) == 0 {
+			return next(c)
+		}
+
+		l := m.App.Logger.With(
+			zap.String("source", "RateLimitMiddleware"),
+			zap.String("operation", "Serve"),
+			zap.String("route", route),
+			zap.String("gameID", gameID),
+			zap.String("class", class),
+		)
+
+		allowed, retryAfter, limited, err := m.take(keys, limits)
+		if err != nil {
+			log.E(l, "Could not check rate limit. Allowing request.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return next(c)
+		}
+		if allowed {
+			return next(c)
+		}
+
+		scope := rateLimitScopeGame
+		if limited >= 0 && limited < len(scopes) {
+			scope = scopes[limited]
+		}
+		log.W(l, "Request rate limited.", func(cm log.CM) {
+			cm.Write(zap.String("scope", scope), zap.Duration("retryAfter", retryAfter))
+		})
+		m.App.DDStatsD.Increment(
+			rateLimitedRequests,
+			fmt.Sprintf("route:%s", route),
+			fmt.Sprintf("game:%s", gameID),
+			fmt.Sprintf("class:%s", class),
+			fmt.Sprintf("scope:%s", scope),
+		)
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+		return FailWith(http.StatusTooManyRequests, "Rate limit exceeded.", c)
+	}
+}
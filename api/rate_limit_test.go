@@ -0,0 +1,130 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Rate Limit Middleware", func() {
+	var db models.DB
+	var game *models.Game
+	var players []*models.Player
+
+	BeforeEach(func() {
+		db = GetDefaultTestApp().Db(nil)
+
+		game = models.GameFactory.MustCreate().(*models.Game)
+		err := db.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+
+		players = make([]*models.Player, 2)
+		for i := range players {
+			players[i] = models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*models.Player)
+			err = db.Insert(players[i])
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	// retrievePlayer returns the status and the Retry-After header of a retrieve player request
+	retrievePlayer := func(a *api.App, player *models.Player) (int, string) {
+		status, _, headers := DoRequestWithResponseHeaders(
+			a, "GET", fmt.Sprintf("/games/%s/players/%s", game.PublicID, player.PublicID), "", nil,
+		)
+		return status, headers.Get("Retry-After")
+	}
+
+	It("Should limit the requests of each player", func() {
+		a := GetTestAppWithRateLimit(map[string]interface{}{
+			"rateLimit.classes.read.player.rate":  0.01,
+			"rateLimit.classes.read.player.burst": 2,
+		})
+
+		for i := 0; i < 2; i++ {
+			status, _ := retrievePlayer(a, players[0])
+			Expect(status).To(Equal(http.StatusOK))
+		}
+		status, retryAfter := retrievePlayer(a, players[0])
+		Expect(status).To(Equal(http.StatusTooManyRequests))
+		seconds, err := strconv.Atoi(retryAfter)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seconds).To(BeNumerically(">", 0))
+		Expect(seconds).To(BeNumerically("<=", 100))
+
+		status, _ = retrievePlayer(a, players[1])
+		Expect(status).To(Equal(http.StatusOK))
+	})
+
+	It("Should limit the requests of each game", func() {
+		a := GetTestAppWithRateLimit(map[string]interface{}{
+			"rateLimit.classes.read.game.rate":  0.01,
+			"rateLimit.classes.read.game.burst": 2,
+		})
+
+		status, _ := retrievePlayer(a, players[0])
+		Expect(status).To(Equal(http.StatusOK))
+		status, _ = retrievePlayer(a, players[1])
+		Expect(status).To(Equal(http.StatusOK))
+		status, retryAfter := retrievePlayer(a, players[1])
+		Expect(status).To(Equal(http.StatusTooManyRequests))
+		Expect(retryAfter).NotTo(BeEmpty())
+	})
+
+	It("Should use the limits configured for the game", func() {
+		a := GetTestAppWithRateLimit(map[string]interface{}{
+			"rateLimit.classes.read.player.rate":                               0.01,
+			"rateLimit.classes.read.player.burst":                              1,
+			fmt.Sprintf("rateLimit.games.%s.read.player.rate", game.PublicID):  1000,
+			fmt.Sprintf("rateLimit.games.%s.read.player.burst", game.PublicID): 1000,
+		})
+
+		for i := 0; i < 3; i++ {
+			status, _ := retrievePlayer(a, players[0])
+			Expect(status).To(Equal(http.StatusOK))
+		}
+	})
+
+	It("Should not take tokens from requests that were limited", func() {
+		a := GetTestAppWithRateLimit(map[string]interface{}{
+			"rateLimit.classes.read.game.rate":    0.01,
+			"rateLimit.classes.read.game.burst":   2,
+			"rateLimit.classes.read.player.rate":  0.01,
+			"rateLimit.classes.read.player.burst": 1,
+		})
+
+		status, _ := retrievePlayer(a, players[0])
+		Expect(status).To(Equal(http.StatusOK))
+		status, _ = retrievePlayer(a, players[0])
+		Expect(status).To(Equal(http.StatusTooManyRequests))
+		status, _ = retrievePlayer(a, players[1])
+		Expect(status).To(Equal(http.StatusOK))
+	})
+
+	Describe("Rate Limit Route Class", func() {
+		It("Should return the class of each route", func() {
+			Expect(api.RateLimitRouteClass("GET", "/healthcheck")).To(Equal(""))
+			Expect(api.RateLimitRouteClass("POST", "/games")).To(Equal(""))
+			Expect(api.RateLimitRouteClass("GET", "/games/:gameID")).To(Equal(api.RateLimitClassRead))
+			Expect(api.RateLimitRouteClass("PUT", "/games/:gameID")).To(Equal(api.RateLimitClassWrite))
+			Expect(api.RateLimitRouteClass("GET", "/games/:gameID/clans/search")).To(Equal(api.RateLimitClassSearch))
+			Expect(api.RateLimitRouteClass("GET", "/games/:gameID/clans/autocomplete")).To(Equal(api.RateLimitClassSearch))
+			Expect(api.RateLimitRouteClass("GET", "/games/:gameID/clans/:clanPublicID")).To(Equal(api.RateLimitClassRead))
+			Expect(api.RateLimitRouteClass("POST", "/games/:gameID/clans/:clanPublicID/memberships/application")).To(Equal(api.RateLimitClassMembership))
+			Expect(api.RateLimitRouteClass("PUT", "/games/:gameID/players/:playerPublicID")).To(Equal(api.RateLimitClassWrite))
+		})
+	})
+})
@@ -0,0 +1,45 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// IfMatchHeader carries the version a PUT request expects the resource to be at
+const IfMatchHeader = "If-Match"
+
+// ETagHeader carries the version of the resource in the response
+const ETagHeader = "ETag"
+
+// getIfMatchVersion returns the version in the If-Match header and whether the request should be
+// checked against it. Requests without the header or with If-Match: * are not checked.
+func getIfMatchVersion(c echo.Context) (int64, bool, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header().Get(IfMatchHeader))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, false, nil
+	}
+	tag := strings.TrimPrefix(ifMatch, "W/")
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false, fmt.Errorf("%s should be a quoted version, e.g. \"3\".", IfMatchHeader)
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, false, fmt.Errorf("%s should be a quoted version, e.g. \"3\".", IfMatchHeader)
+	}
+	return version, true, nil
+}
+
+// setVersionETag sets the version of the resource as the ETag of the response
+func setVersionETag(c echo.Context, version int64) {
+	c.Response().Header().Set(ETagHeader, fmt.Sprintf(`"%d"`, version))
+}
@@ -0,0 +1,171 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Versions API Handler", func() {
+	var testDb, db models.DB
+	var a *api.App
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+		a = GetDefaultTestApp()
+		db = a.Db(nil)
+	})
+
+	ifMatch := func(version interface{}) map[string]string {
+		return map[string]string{"If-Match": fmt.Sprintf(`"%v"`, version)}
+	}
+
+	Describe("Clan Versions", func() {
+		var clan *models.Clan
+		var owner *models.Player
+		var route, payload string
+
+		BeforeEach(func() {
+			var err error
+			_, clan, owner, _, _, err = models.GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			route = GetGameRoute(clan.GameID, fmt.Sprintf("/clans/%s", clan.PublicID))
+			payloadJSON, _ := json.Marshal(map[string]interface{}{
+				"name":             "new name",
+				"ownerPublicID":    owner.PublicID,
+				"metadata":         map[string]interface{}{"new": "metadata"},
+				"allowApplication": clan.AllowApplication,
+				"autoJoin":         clan.AutoJoin,
+			})
+			payload = string(payloadJSON)
+		})
+
+		It("Should return the clan version as ETag", func() {
+			status, body, headers := DoRequestWithResponseHeaders(a, "GET", route, "", nil)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get("ETag")).To(Equal(`"1"`))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["version"]).To(BeEquivalentTo(1))
+		})
+
+		It("Should update the clan if it is at the expected version", func() {
+			status, body, headers := DoRequestWithResponseHeaders(a, "PUT", route, payload, ifMatch(1))
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get("ETag")).To(Equal(`"2"`))
+			var result map[string]interface{}
+			json.Unmarshal([]byte(body), &result)
+			Expect(result["version"]).To(BeEquivalentTo(2))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Name).To(Equal("new name"))
+			Expect(dbClan.Version).To(BeEquivalentTo(2))
+		})
+
+		It("Should not update the clan with a stale version", func() {
+			status, _ := DoRequestWithHeaders(a, "PUT", route, payload, nil)
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = DoRequestWithHeaders(a, "PUT", route, payload, ifMatch(1))
+			Expect(status).To(Equal(http.StatusPreconditionFailed))
+
+			dbClan, err := models.GetClanByPublicID(db, clan.GameID, clan.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.Version).To(BeEquivalentTo(2))
+		})
+
+		It("Should accept weak and wildcard If-Match headers", func() {
+			status, _ := DoRequestWithHeaders(a, "PUT", route, payload, map[string]string{"If-Match": `W/"1"`})
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = DoRequestWithHeaders(a, "PUT", route, payload, map[string]string{"If-Match": "*"})
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("Should not update the clan with an invalid If-Match header", func() {
+			status, _ := DoRequestWithHeaders(a, "PUT", route, payload, map[string]string{"If-Match": "1"})
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Player Versions", func() {
+		var player *models.Player
+		var route string
+
+		BeforeEach(func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			player = models.PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*models.Player)
+			err = db.Insert(player)
+			Expect(err).NotTo(HaveOccurred())
+
+			route = GetGameRoute(game.PublicID, fmt.Sprintf("/players/%s", player.PublicID))
+		})
+
+		It("Should return the player version as ETag and update it", func() {
+			status, _, headers := DoRequestWithResponseHeaders(a, "GET", route, "", nil)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get("ETag")).To(Equal(`"1"`))
+
+			payload := `{"name": "new name", "metadata": {"x": 1}}`
+			status, _, headers = DoRequestWithResponseHeaders(a, "PUT", route, payload, ifMatch(1))
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get("ETag")).To(Equal(`"2"`))
+
+			status, _ = DoRequestWithHeaders(a, "PUT", route, payload, ifMatch(1))
+			Expect(status).To(Equal(http.StatusPreconditionFailed))
+
+			dbPlayer, err := models.GetPlayerByPublicID(db, player.GameID, player.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbPlayer.Name).To(Equal("new name"))
+			Expect(dbPlayer.Version).To(BeEquivalentTo(2))
+		})
+
+		It("Should not create players with If-Match", func() {
+			route = GetGameRoute(player.GameID, "/players/unknown-player")
+			status, _ := DoRequestWithHeaders(a, "PUT", route, `{"name": "name", "metadata": {}}`, ifMatch(1))
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Game Versions", func() {
+		It("Should return the game version as ETag and update it", func() {
+			game := models.GameFactory.MustCreate().(*models.Game)
+			err := db.Insert(game)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := fmt.Sprintf("/games/%s", game.PublicID)
+			status, _, headers := DoRequestWithResponseHeaders(a, "GET", route, "", nil)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get("ETag")).To(Equal(`"1"`))
+
+			payload, _ := json.Marshal(getGamePayload(game.PublicID, game.Name))
+			status, _, headers = DoRequestWithResponseHeaders(a, "PUT", route, string(payload), ifMatch(1))
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(headers.Get("ETag")).To(Equal(`"2"`))
+
+			status, _ = DoRequestWithHeaders(a, "PUT", route, string(payload), ifMatch(1))
+			Expect(status).To(Equal(http.StatusPreconditionFailed))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package caches
+
+import (
+	"sync"
+	"time"
+
+	"github.com/topfreegames/khan/models"
+)
+
+// Hooks is an in-memory registry of the hooks indexed by game and event type.
+// The hooks of a game are loaded on demand and kept until the game is invalidated.
+// The whole registry is dropped every RefreshInterval as a safety net for lost invalidations.
+type Hooks struct {
+	// RefreshInterval is the maximum time the hooks are kept without being reloaded.
+	RefreshInterval time.Duration
+
+	mutex       sync.RWMutex
+	games       map[string]map[int][]*models.Hook
+	refreshedAt time.Time
+	// generation changes on every invalidation, so loads that raced with one are not kept
+	generation uint64
+}
+
+// NewHooks returns an empty hooks registry.
+func NewHooks(refreshInterval time.Duration) *Hooks {
+	return &Hooks{
+		RefreshInterval: refreshInterval,
+		games:           make(map[string]map[int][]*models.Hook),
+		refreshedAt:     time.Now(),
+	}
+}
+
+// GetHooks returns the hooks registered for the event type in the game.
+func (c *Hooks) GetHooks(db models.DB, gameID string, eventType int) ([]*models.Hook, error) {
+	gameHooks, err := c.getGameHooks(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	return gameHooks[eventType], nil
+}
+
+// Invalidate drops the hooks of the game, so they are reloaded on the next access.
+func (c *Hooks) Invalidate(gameID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.games, gameID)
+	c.generation++
+}
+
+// InvalidateAll drops the hooks of all games.
+func (c *Hooks) InvalidateAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.invalidateAll()
+}
+
+func (c *Hooks) invalidateAll() {
+	c.games = make(map[string]map[int][]*models.Hook)
+	c.refreshedAt = time.Now()
+	c.generation++
+}
+
+func (c *Hooks) getGameHooks(db models.DB, gameID string) (map[int][]*models.Hook, error) {
+	c.mutex.RLock()
+	gameHooks, present := c.games[gameID]
+	expired := time.Since(c.refreshedAt) > c.RefreshInterval
+	generation := c.generation
+	c.mutex.RUnlock()
+
+	if present && !expired {
+		return gameHooks, nil
+	}
+
+	if expired {
+		c.mutex.Lock()
+		if time.Since(c.refreshedAt) > c.RefreshInterval {
+			c.invalidateAll()
+		}
+		generation = c.generation
+		c.mutex.Unlock()
+	}
+
+	hooks, err := models.GetHooksByGameID(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	gameHooks = make(map[int][]*models.Hook)
+	for _, hook := range hooks {
+		gameHooks[hook.EventType] = append(gameHooks[hook.EventType], hook)
+	}
+
+	c.mutex.Lock()
+	if c.generation == generation {
+		c.games[gameID] = gameHooks
+	}
+	c.mutex.Unlock()
+	return gameHooks, nil
+}
@@ -0,0 +1,84 @@
+package caches_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/caches"
+	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/testing"
+)
+
+var _ = Describe("Hooks Cache", func() {
+	var testDb models.DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = testing.GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	createHooks := func() string {
+		gameID := uuid.NewV4().String()
+		_, err := models.GetTestHooks(testDb, gameID, 2)
+		Expect(err).NotTo(HaveOccurred())
+		return gameID
+	}
+
+	It("Should return the hooks of the game indexed by event type", func() {
+		gameID := createHooks()
+		cache := caches.NewHooks(time.Minute)
+
+		hooks, err := cache.GetHooks(testDb, gameID, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks).To(HaveLen(2))
+		for _, hook := range hooks {
+			Expect(hook.GameID).To(Equal(gameID))
+			Expect(hook.EventType).To(Equal(1))
+		}
+
+		hooks, err = cache.GetHooks(testDb, gameID, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks).To(BeEmpty())
+	})
+
+	It("Should keep returning the cached hooks until the game is invalidated", func() {
+		gameID := createHooks()
+		cache := caches.NewHooks(time.Minute)
+
+		hooks, err := cache.GetHooks(testDb, gameID, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks).To(HaveLen(2))
+
+		err = models.RemoveHook(testDb, gameID, hooks[0].PublicID)
+		Expect(err).NotTo(HaveOccurred())
+		hooks, err = cache.GetHooks(testDb, gameID, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks).To(HaveLen(2))
+
+		cache.Invalidate(gameID)
+		hooks, err = cache.GetHooks(testDb, gameID, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks).To(HaveLen(1))
+	})
+
+	It("Should reload all hooks after the refresh interval", func() {
+		gameID := createHooks()
+		cache := caches.NewHooks(50 * time.Millisecond)
+
+		hooks, err := cache.GetHooks(testDb, gameID, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks).To(HaveLen(2))
+
+		err = models.RemoveHook(testDb, gameID, hooks[0].PublicID)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() []*models.Hook {
+			hooks, err := cache.GetHooks(testDb, gameID, 0)
+			Expect(err).NotTo(HaveOccurred())
+			return hooks
+		}).Should(HaveLen(1))
+	})
+})
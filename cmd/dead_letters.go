@@ -0,0 +1,122 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var deadLettersDebug bool
+var deadLettersQuiet bool
+var deadLettersGameID string
+var deadLettersLimit int
+var deadLettersReplay []string
+var deadLettersReplayAll bool
+
+// deadLettersCmd represents the dead-letters command
+var deadLettersCmd = &cobra.Command{
+	Use:   "dead-letters",
+	Short: "inspects and replays webhooks that exhausted their retries",
+	Long: `Lists the webhook deliveries of a game that failed after all retries were
+exhausted, printing one JSON document per line. Use --replay to send specific
+dead letters again or --replay-all to send all of the listed dead letters again.
+Replayed dead letters are removed and will be stored again if they keep failing.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ll := zap.InfoLevel
+		if deadLettersDebug {
+			ll = zap.DebugLevel
+		}
+		if deadLettersQuiet {
+			ll = zap.ErrorLevel
+		}
+		l := zap.New(
+			zap.NewJSONEncoder(), // drop timestamps in tests
+			ll,
+		)
+
+		cmdL := l.With(
+			zap.String("source", "deadLettersCmd"),
+			zap.String("operation", "Run"),
+			zap.String("gameID", deadLettersGameID),
+		)
+
+		if deadLettersGameID == "" {
+			log.E(cmdL, "The --game flag is required.")
+			os.Exit(1)
+		}
+
+		log.D(cmdL, "Creating application...")
+		app := api.GetApp(
+			"0.0.0.0",
+			8888,
+			ConfigFile,
+			deadLettersDebug,
+			l,
+			false,
+			false,
+		)
+		log.D(cmdL, "Application created successfully.")
+
+		publicIDs := deadLettersReplay
+		if len(publicIDs) == 0 {
+			deadLetters, err := models.GetHookDeadLettersByGameID(app.Db(nil), deadLettersGameID, deadLettersLimit)
+			if err != nil {
+				log.E(cmdL, "Failed to retrieve hook dead letters.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+
+			for _, deadLetter := range deadLetters {
+				deadLetterJSON, _ := json.Marshal(deadLetter.Serialize())
+				fmt.Println(string(deadLetterJSON))
+				if deadLettersReplayAll {
+					publicIDs = append(publicIDs, deadLetter.PublicID)
+				}
+			}
+		}
+
+		failed := false
+		for _, publicID := range publicIDs {
+			err := app.Dispatcher.ReplayHookDeadLetter(context.Background(), deadLettersGameID, publicID)
+			if err != nil {
+				failed = true
+				log.E(cmdL, "Failed to replay hook dead letter.", func(cm log.CM) {
+					cm.Write(zap.String("deadLetterPublicID", publicID), zap.Error(err))
+				})
+				continue
+			}
+			log.I(cmdL, "Hook dead letter replayed successfully.", func(cm log.CM) {
+				cm.Write(zap.String("deadLetterPublicID", publicID))
+			})
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(deadLettersCmd)
+
+	deadLettersCmd.Flags().BoolVarP(&deadLettersDebug, "debug", "d", false, "Debug mode")
+	deadLettersCmd.Flags().BoolVarP(&deadLettersQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+	deadLettersCmd.Flags().StringVarP(&deadLettersGameID, "game", "g", "", "Public ID of the game")
+	deadLettersCmd.Flags().IntVarP(&deadLettersLimit, "limit", "l", 50, "Maximum number of dead letters to list")
+	deadLettersCmd.Flags().StringSliceVarP(&deadLettersReplay, "replay", "r", []string{}, "Public IDs of the dead letters to replay")
+	deadLettersCmd.Flags().BoolVar(&deadLettersReplayAll, "replay-all", false, "Replay all the listed dead letters")
+}
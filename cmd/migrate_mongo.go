@@ -48,7 +48,7 @@ If the game do not exists in the main Postgres database, no actions take place.`
 				cm.Write(zap.String("error", err.Error()))
 			})
 		}
-		_, err = models.GetGameByPublicID(db, gameID)
+		game, err := models.GetGameByPublicID(db, gameID)
 		if err != nil {
 			log.F(l, "Error fetching game from postgres.", func(cm log.CM) {
 				cm.Write(zap.String("error", err.Error()))
@@ -62,7 +62,7 @@ If the game do not exists in the main Postgres database, no actions take place.`
 				cm.Write(zap.String("error", err.Error()))
 			})
 		}
-		err = runMigrations(mongoDB, logger)
+		err = runMigrations(mongoDB, game, logger)
 		if err != nil {
 			log.F(l, "Error running mongo migrations.", func(cm log.CM) {
 				cm.Write(zap.String("error", err.Error()))
@@ -113,7 +113,7 @@ func newMongo(config *viper.Viper) (imongo.MongoDB, error) {
 	return mongoDB.MongoDB, nil
 }
 
-func runMigrations(mongoDB imongo.MongoDB, logger zap.Logger) error {
+func runMigrations(mongoDB imongo.MongoDB, game *models.Game, logger zap.Logger) error {
 	l := logger.With(
 		zap.String("source", "cmd/migrate_mongo.go"),
 		zap.String("operation", "runMigrations"),
@@ -127,6 +127,10 @@ func runMigrations(mongoDB imongo.MongoDB, logger zap.Logger) error {
 	migrations := []Migration{
 		createClanNameTextIndex,
 		createClanNameRegularIndex,
+		createClanNamePrefixesIndex,
+		func(mongoDB imongo.MongoDB, logger zap.Logger) error {
+			return createClanSearchIndexes(mongoDB, game, logger)
+		},
 	}
 	for _, migration := range migrations {
 		if err := migration(mongoDB, logger); err != nil {
@@ -191,6 +195,58 @@ func createClanNameRegularIndex(mongoDB imongo.MongoDB, logger zap.Logger) error
 	return nil
 }
 
+func createClanNamePrefixesIndex(mongoDB imongo.MongoDB, logger zap.Logger) error {
+	l := logger.With(
+		zap.String("source", "cmd/migrate_mongo.go"),
+		zap.String("operation", "createClanNamePrefixesIndex"),
+		zap.String("game", gameID),
+	)
+
+	cmd := mongo.GetClanNamePrefixesIndexCommand(gameID, false)
+	var res struct {
+		OK               int `bson:"ok"`
+		NumIndexesBefore int `bson:"numIndexesBefore"`
+		NumIndexesAfter  int `bson:"numIndexesAfter"`
+	}
+	err := mongoDB.Run(cmd, &res)
+	if err != nil {
+		return err
+	}
+	if res.OK != 1 {
+		return &MongoCommandError{cmd: cmd}
+	}
+	if res.NumIndexesAfter == res.NumIndexesBefore {
+		log.W(l, "Clan name prefixes index already exists for this game.")
+	}
+	return nil
+}
+
+func createClanSearchIndexes(mongoDB imongo.MongoDB, game *models.Game, logger zap.Logger) error {
+	l := logger.With(
+		zap.String("source", "cmd/migrate_mongo.go"),
+		zap.String("operation", "createClanSearchIndexes"),
+		zap.String("game", gameID),
+	)
+
+	cmd := mongo.GetClanSearchIndexesCommand(gameID, game.GetClanSearchMetadataKeys(), false)
+	var res struct {
+		OK               int `bson:"ok"`
+		NumIndexesBefore int `bson:"numIndexesBefore"`
+		NumIndexesAfter  int `bson:"numIndexesAfter"`
+	}
+	err := mongoDB.Run(cmd, &res)
+	if err != nil {
+		return err
+	}
+	if res.OK != 1 {
+		return &MongoCommandError{cmd: cmd}
+	}
+	if res.NumIndexesAfter == res.NumIndexesBefore {
+		log.W(l, "Clan search indexes already exist for this game.")
+	}
+	return nil
+}
+
 // MongoCommandError represents a MongoDB run command error.
 type MongoCommandError struct {
 	cmd bson.D
@@ -0,0 +1,114 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var rebuildTopClansDebug bool
+var rebuildTopClansQuiet bool
+var rebuildTopClansGameID string
+var rebuildTopClansBatchSize int
+
+// rebuildTopClansCmd represents the rebuild-top-clans command
+var rebuildTopClansCmd = &cobra.Command{
+	Use:   "rebuild-top-clans",
+	Short: "ranks the clans in Postgres in the top clans rankings",
+	Long: `Reads the clans of one game, or of all games, from Postgres and ranks them in
+every top clans dimension of their game. Use it to rank the clans created before
+the rankings existed or after adding dimensions to topClansMetadataDimensions.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ll := zap.InfoLevel
+		if rebuildTopClansDebug {
+			ll = zap.DebugLevel
+		}
+		if rebuildTopClansQuiet {
+			ll = zap.ErrorLevel
+		}
+		l := zap.New(
+			zap.NewJSONEncoder(), // drop timestamps in tests
+			ll,
+		)
+
+		cmdL := l.With(
+			zap.String("source", "rebuildTopClansCmd"),
+			zap.String("operation", "Run"),
+			zap.String("gameID", rebuildTopClansGameID),
+		)
+
+		if rebuildTopClansBatchSize <= 0 {
+			log.E(cmdL, "The --batch-size flag must be positive.")
+			os.Exit(1)
+		}
+
+		log.D(cmdL, "Creating application...")
+		app := api.GetApp(
+			"0.0.0.0",
+			8888,
+			ConfigFile,
+			rebuildTopClansDebug,
+			l,
+			false,
+			false,
+		)
+		log.D(cmdL, "Application created successfully.")
+
+		db := app.Db(nil)
+		var games []*models.Game
+		if rebuildTopClansGameID != "" {
+			game, err := models.GetGameByPublicID(db, rebuildTopClansGameID)
+			if err != nil {
+				log.E(cmdL, "Failed to load game.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+			games = []*models.Game{game}
+		} else {
+			var err error
+			games, err = models.GetAllGames(db)
+			if err != nil {
+				log.E(cmdL, "Failed to load games.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+		}
+
+		clans := 0
+		for _, game := range games {
+			ranked, err := models.RebuildTopClans(db, game, rebuildTopClansBatchSize)
+			clans += ranked
+			if err != nil {
+				log.E(cmdL, "Failed to rebuild top clans.", func(cm log.CM) {
+					cm.Write(zap.String("game", game.PublicID), zap.Error(err))
+				})
+				os.Exit(1)
+			}
+		}
+		log.I(cmdL, "Top clans rebuilt successfully.", func(cm log.CM) {
+			cm.Write(zap.Int("games", len(games)), zap.Int("clans", clans))
+		})
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(rebuildTopClansCmd)
+
+	rebuildTopClansCmd.Flags().BoolVarP(&rebuildTopClansDebug, "debug", "d", false, "Debug mode")
+	rebuildTopClansCmd.Flags().BoolVarP(&rebuildTopClansQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+	rebuildTopClansCmd.Flags().StringVarP(&rebuildTopClansGameID, "game", "g", "", "game public ID, all games if empty")
+	rebuildTopClansCmd.Flags().IntVarP(&rebuildTopClansBatchSize, "batch-size", "b", 500, "clans read at once")
+}
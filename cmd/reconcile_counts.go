@@ -0,0 +1,109 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var reconcileCountsDebug bool
+var reconcileCountsQuiet bool
+var reconcileCountsGameID string
+var reconcileCountsBatchSize int
+
+// reconcileCountsCmd represents the reconcile-counts command
+var reconcileCountsCmd = &cobra.Command{
+	Use:   "reconcile-counts",
+	Short: "fixes the clans and players membership and ownership counts",
+	Long: `Recomputes the membership count of the clans and the membership and ownership
+counts of the players of one game, or of all games, from their memberships and
+clans, fixing the wrong ones in batches. Prints one JSON document per game with
+the previous and current values of the fixed counters. Search index and top
+clans updates are enqueued for the fixed clans.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ll := zap.InfoLevel
+		if reconcileCountsDebug {
+			ll = zap.DebugLevel
+		}
+		if reconcileCountsQuiet {
+			ll = zap.ErrorLevel
+		}
+		l := zap.New(
+			zap.NewJSONEncoder(), // drop timestamps in tests
+			ll,
+		)
+
+		cmdL := l.With(
+			zap.String("source", "reconcileCountsCmd"),
+			zap.String("operation", "Run"),
+			zap.String("gameID", reconcileCountsGameID),
+		)
+
+		if reconcileCountsBatchSize <= 0 {
+			log.E(cmdL, "The --batch-size flag must be positive.")
+			os.Exit(1)
+		}
+
+		log.D(cmdL, "Creating application...")
+		app := api.GetApp(
+			"0.0.0.0",
+			8888,
+			ConfigFile,
+			reconcileCountsDebug,
+			l,
+			false,
+			false,
+		)
+		log.D(cmdL, "Application created successfully.")
+
+		gameIDs := []string{reconcileCountsGameID}
+		if reconcileCountsGameID == "" {
+			games, err := models.GetAllGames(app.Db(nil))
+			if err != nil {
+				log.E(cmdL, "Failed to load games.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+			gameIDs = []string{}
+			for _, game := range games {
+				gameIDs = append(gameIDs, game.PublicID)
+			}
+		}
+
+		for _, gameID := range gameIDs {
+			reconciliation, err := app.ReconcileCounts(context.Background(), gameID, reconcileCountsBatchSize)
+			reconciliationJSON, _ := json.Marshal(reconciliation.Serialize())
+			fmt.Println(string(reconciliationJSON))
+			if err != nil {
+				log.E(cmdL, "Failed to reconcile counts.", func(cm log.CM) {
+					cm.Write(zap.String("reconciledGameID", gameID), zap.Error(err))
+				})
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(reconcileCountsCmd)
+
+	reconcileCountsCmd.Flags().BoolVarP(&reconcileCountsDebug, "debug", "d", false, "Debug mode")
+	reconcileCountsCmd.Flags().BoolVarP(&reconcileCountsQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+	reconcileCountsCmd.Flags().StringVarP(&reconcileCountsGameID, "game", "g", "", "game public ID, all games if empty")
+	reconcileCountsCmd.Flags().IntVarP(&reconcileCountsBatchSize, "batch-size", "b", 1000, "clans or players fixed in each transaction")
+}
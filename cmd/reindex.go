@@ -0,0 +1,308 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	imongo "github.com/topfreegames/extensions/mongo/interfaces"
+	"github.com/topfreegames/khan/es"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var reindexDebug bool
+var reindexQuiet bool
+var reindexGameID string
+var reindexTarget string
+var reindexBatchSize int
+var reindexRate int
+var reindexSwap bool
+
+// reindex targets
+const (
+	ReindexTargetAll           = "all"
+	ReindexTargetMongo         = "mongo"
+	ReindexTargetElasticsearch = "elasticsearch"
+)
+
+// ReindexOptions configures a clans reindex
+type ReindexOptions struct {
+	DB                      models.DB
+	MongoDB                 imongo.MongoDB // nil skips mongo
+	MongoCollectionTemplate string
+	ES                      *es.Client // nil skips elasticsearch
+	GameIDs                 []string
+	BatchSize               int
+	// Rate is the maximum number of clans reindexed per second, zero means unlimited
+	Rate int
+	// Swap builds fresh indexes and swaps them in after all clans are written
+	Swap bool
+}
+
+// ReindexStats has the number of clans written to each store
+type ReindexStats struct {
+	Clans              int
+	MongoClans         int
+	ElasticsearchClans int
+}
+
+// ReindexClans writes the clans of the games in Postgres to MongoDB and/or elasticsearch
+// Clans are read and written in batches of BatchSize, throttled to Rate clans per second.
+// With Swap, the clans are written to a new collection and a new index that replace the current
+// ones only after all clans are written, so clans no longer in Postgres are removed as well.
+func ReindexClans(ctx context.Context, options *ReindexOptions, logger zap.Logger) (*ReindexStats, error) {
+	totals := &ReindexStats{}
+	for _, gameID := range options.GameIDs {
+		stats, err := reindexGameClans(ctx, options, gameID, logger)
+		if err != nil {
+			return totals, err
+		}
+		totals.Clans += stats.Clans
+		totals.MongoClans += stats.MongoClans
+		totals.ElasticsearchClans += stats.ElasticsearchClans
+	}
+	return totals, nil
+}
+
+func reindexGameClans(ctx context.Context, options *ReindexOptions, gameID string, logger zap.Logger) (*ReindexStats, error) {
+	l := logger.With(
+		zap.String("source", "reindexCmd"),
+		zap.String("operation", "reindexGameClans"),
+		zap.String("gameID", gameID),
+		zap.Bool("swap", options.Swap),
+	)
+
+	total, err := models.CountClans(options.DB, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	var mongoCollection, mongoTargetCollection string
+	if options.MongoDB != nil {
+		mongoTargetCollection = fmt.Sprintf(options.MongoCollectionTemplate, gameID)
+		mongoCollection = mongoTargetCollection
+		if options.Swap {
+			mongoCollection = fmt.Sprintf("%s_reindex_%d", mongoTargetCollection, time.Now().Unix())
+			if err := models.CreateMongoDBCollection(options.MongoDB, mongoCollection); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var esIndex, esAlias string
+	if options.ES != nil {
+		esAlias = options.ES.GetIndexName(gameID)
+		esIndex = esAlias
+		if options.Swap {
+			esIndex = fmt.Sprintf("%s-%d", esAlias, time.Now().Unix())
+			if err := models.CreateElasticSearchIndexLike(ctx, options.ES, esIndex, esAlias); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	log.I(l, "Reindexing clans...", func(cm log.CM) {
+		cm.Write(zap.Int64("total", total))
+	})
+
+	stats := &ReindexStats{}
+	start := time.Now()
+	var cursor *models.ClansCursor
+	for {
+		var clans []models.Clan
+		clans, cursor, err = models.GetClansPage(options.DB, gameID, cursor, options.BatchSize)
+		if err != nil {
+			return stats, err
+		}
+
+		if options.MongoDB != nil {
+			if err = models.BulkUpsertClansIntoMongoDB(options.MongoDB, mongoCollection, clans); err != nil {
+				return stats, err
+			}
+			stats.MongoClans += len(clans)
+		}
+		if options.ES != nil {
+			if err = models.BulkIndexClansIntoElasticSearch(ctx, options.ES, esIndex, clans); err != nil {
+				return stats, err
+			}
+			stats.ElasticsearchClans += len(clans)
+		}
+		stats.Clans += len(clans)
+
+		log.I(l, "Clans reindexed.", func(cm log.CM) {
+			cm.Write(
+				zap.Int("reindexed", stats.Clans),
+				zap.Int64("total", total),
+				zap.Duration("elapsed", time.Now().Sub(start)),
+			)
+		})
+
+		if cursor == nil {
+			break
+		}
+		if options.Rate > 0 {
+			expected := time.Duration(stats.Clans) * time.Second / time.Duration(options.Rate)
+			time.Sleep(expected - time.Now().Sub(start))
+		}
+	}
+
+	if options.Swap && options.MongoDB != nil {
+		log.I(l, "Swapping mongo collection...", func(cm log.CM) {
+			cm.Write(zap.String("collection", mongoTargetCollection))
+		})
+		if err = models.ReplaceMongoDBCollection(options.MongoDB, mongoCollection, mongoTargetCollection); err != nil {
+			return stats, err
+		}
+	}
+	if options.Swap && options.ES != nil {
+		log.I(l, "Swapping elasticsearch alias...", func(cm log.CM) {
+			cm.Write(zap.String("alias", esAlias), zap.String("index", esIndex))
+		})
+		if err = models.SwapElasticSearchAlias(ctx, options.ES, esAlias, esIndex); err != nil {
+			return stats, err
+		}
+	}
+
+	log.I(l, "Game clans reindexed successfully.", func(cm log.CM) {
+		cm.Write(
+			zap.Int("clans", stats.Clans),
+			zap.Duration("duration", time.Now().Sub(start)),
+		)
+	})
+	return stats, nil
+}
+
+// reindexCmd represents the reindex command
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "writes the clans in Postgres to MongoDB and elasticsearch",
+	Long: `Reads the clans of one game, or of all games, from Postgres and writes them in
+bulk to MongoDB and/or elasticsearch. Use it to index clans created before search
+was enabled or whose index jobs were lost, and to rewrite the name prefixes of the
+MongoDB documents written before prefixes were folded.
+
+With --swap, a fresh collection and a fresh index are built and swapped in
+atomically once all clans are written: the MongoDB collection is replaced through
+an aggregation $out, and the elasticsearch index name becomes an alias of the new
+index. Clan changes made during a swapped reindex may be lost, so run it when the
+workers are idle or run it again without --swap afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		InitConfig()
+		ll := zap.InfoLevel
+		if reindexDebug {
+			ll = zap.DebugLevel
+		}
+		if reindexQuiet {
+			ll = zap.ErrorLevel
+		}
+		l := zap.New(
+			zap.NewJSONEncoder(), // drop timestamps in tests
+			ll,
+		)
+
+		cmdL := l.With(
+			zap.String("source", "reindexCmd"),
+			zap.String("operation", "Run"),
+			zap.String("gameID", reindexGameID),
+			zap.String("target", reindexTarget),
+		)
+
+		if reindexTarget != ReindexTargetAll && reindexTarget != ReindexTargetMongo && reindexTarget != ReindexTargetElasticsearch {
+			log.E(cmdL, "The --target flag must be all, mongo or elasticsearch.")
+			os.Exit(1)
+		}
+		if reindexBatchSize <= 0 {
+			log.E(cmdL, "The --batch-size flag must be positive.")
+			os.Exit(1)
+		}
+
+		db, err := newDatabase(viper.GetViper())
+		if err != nil {
+			log.E(cmdL, "Failed to connect to DB.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			os.Exit(1)
+		}
+
+		options := &ReindexOptions{
+			DB:                      db,
+			MongoCollectionTemplate: viper.GetString("mongodb.collectionTemplate"),
+			BatchSize:               reindexBatchSize,
+			Rate:                    reindexRate,
+			Swap:                    reindexSwap,
+		}
+		if reindexTarget != ReindexTargetElasticsearch {
+			options.MongoDB, err = newMongo(viper.GetViper())
+			if err != nil {
+				log.E(cmdL, "Failed to connect to mongo.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+		}
+		if reindexTarget != ReindexTargetMongo {
+			options.ES = es.GetClient(
+				viper.GetString("elasticsearch.host"),
+				viper.GetInt("elasticsearch.port"),
+				viper.GetString("elasticsearch.index"),
+				viper.GetBool("elasticsearch.sniff"),
+				l,
+				reindexDebug,
+				nil,
+			)
+		}
+
+		if reindexGameID != "" {
+			options.GameIDs = []string{reindexGameID}
+		} else {
+			games, err := models.GetAllGames(db)
+			if err != nil {
+				log.E(cmdL, "Failed to load games.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+			for _, game := range games {
+				options.GameIDs = append(options.GameIDs, game.PublicID)
+			}
+		}
+
+		stats, err := ReindexClans(context.Background(), options, l)
+		if err != nil {
+			log.E(cmdL, "Failed to reindex clans.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			os.Exit(1)
+		}
+		log.I(cmdL, "Clans reindexed successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.Int("clans", stats.Clans),
+				zap.Int("mongoClans", stats.MongoClans),
+				zap.Int("elasticsearchClans", stats.ElasticsearchClans),
+			)
+		})
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(reindexCmd)
+
+	reindexCmd.Flags().BoolVarP(&reindexDebug, "debug", "d", false, "Debug mode")
+	reindexCmd.Flags().BoolVarP(&reindexQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+	reindexCmd.Flags().StringVarP(&reindexGameID, "game", "g", "", "game public ID, all games if empty")
+	reindexCmd.Flags().StringVarP(&reindexTarget, "target", "t", ReindexTargetAll, "all, mongo or elasticsearch")
+	reindexCmd.Flags().IntVarP(&reindexBatchSize, "batch-size", "b", 500, "clans read and written at once")
+	reindexCmd.Flags().IntVarP(&reindexRate, "rate", "r", 0, "maximum clans reindexed per second, 0 for unlimited")
+	reindexCmd.Flags().BoolVarP(&reindexSwap, "swap", "s", false, "build fresh indexes and swap them in at the end")
+}
@@ -0,0 +1,93 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/globalsign/mgo/bson"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+	"github.com/topfreegames/extensions/mongo/interfaces"
+	. "github.com/topfreegames/khan/cmd"
+	"github.com/topfreegames/khan/models"
+	"github.com/topfreegames/khan/mongo"
+	kt "github.com/topfreegames/khan/testing"
+)
+
+var _ = Describe("Reindex Command", func() {
+	var db models.DB
+	var mongoDB interfaces.MongoDB
+	var gameID string
+	var clans []*models.Clan
+
+	BeforeEach(func() {
+		var err error
+		ConfigFile = "../config/test.yaml"
+		InitConfig()
+
+		db, err = kt.GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+		viper.Set("mongodb.database", viper.GetString("mongodb.databaseName"))
+		mongoDB, err = mongo.GetMongo(kt.NewMockLogger(), viper.GetViper())
+		Expect(err).NotTo(HaveOccurred())
+
+		gameID = uuid.NewV4().String()
+		_, clans, err = models.GetTestClans(db, gameID, "", 3)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	countMongoClans := func() int {
+		var res struct {
+			N int `bson:"n"`
+		}
+		err := mongoDB.Run(bson.D{{Name: "count", Value: fmt.Sprintf("clans_%s", gameID)}}, &res)
+		Expect(err).NotTo(HaveOccurred())
+		return res.N
+	}
+
+	reindex := func(swap bool) *ReindexStats {
+		stats, err := ReindexClans(context.Background(), &ReindexOptions{
+			DB:                      db,
+			MongoDB:                 mongoDB,
+			MongoCollectionTemplate: "clans_%s",
+			GameIDs:                 []string{gameID},
+			BatchSize:               2,
+			Swap:                    swap,
+		}, kt.NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		return stats
+	}
+
+	It("Should write the game clans into mongo in batches", func() {
+		stats := reindex(false)
+		Expect(stats.Clans).To(Equal(3))
+		Expect(stats.MongoClans).To(Equal(3))
+		Expect(stats.ElasticsearchClans).To(Equal(0))
+		Expect(countMongoClans()).To(Equal(3))
+
+		suggestions, err := models.AutocompleteClanNames(mongoDB, gameID, models.FoldClanName(clans[0].Name), 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(suggestions).To(HaveLen(1))
+		Expect(suggestions[0].PublicID).To(Equal(clans[0].PublicID))
+	})
+
+	It("Should replace the mongo collection when swapping", func() {
+		stale := models.Clan{GameID: gameID, PublicID: uuid.NewV4().String(), Name: "stale"}
+		err := models.BulkUpsertClansIntoMongoDB(mongoDB, fmt.Sprintf("clans_%s", gameID), []models.Clan{stale})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(countMongoClans()).To(Equal(1))
+
+		stats := reindex(true)
+		Expect(stats.MongoClans).To(Equal(3))
+		Expect(countMongoClans()).To(Equal(3))
+	})
+})
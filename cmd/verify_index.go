@@ -0,0 +1,124 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/khan/api"
+	"github.com/topfreegames/khan/log"
+	"github.com/topfreegames/khan/models"
+	"github.com/uber-go/zap"
+)
+
+var verifyIndexDebug bool
+var verifyIndexQuiet bool
+var verifyIndexGameID string
+var verifyIndexTarget string
+var verifyIndexRepair bool
+
+// verifyIndexCmd represents the verify-index command
+var verifyIndexCmd = &cobra.Command{
+	Use:   "verify-index",
+	Short: "compares the clans in Postgres with MongoDB and elasticsearch",
+	Long: `Compares the clans of one game, or of all games, in Postgres with the documents
+in the MongoDB collection and in the elasticsearch index of the game by id and
+updatedAt, printing one JSON document per game and store with the public IDs of
+the missing, extra and stale clans. Drift counts are also sent to statsd.
+
+With --repair, jobs that write the missing and stale clans and delete the extra
+ones are enqueued to the workers. Stores that are not enabled in the
+configuration are skipped.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ll := zap.InfoLevel
+		if verifyIndexDebug {
+			ll = zap.DebugLevel
+		}
+		if verifyIndexQuiet {
+			ll = zap.ErrorLevel
+		}
+		l := zap.New(
+			zap.NewJSONEncoder(), // drop timestamps in tests
+			ll,
+		)
+
+		cmdL := l.With(
+			zap.String("source", "verifyIndexCmd"),
+			zap.String("operation", "Run"),
+			zap.String("gameID", verifyIndexGameID),
+			zap.String("target", verifyIndexTarget),
+			zap.Bool("repair", verifyIndexRepair),
+		)
+
+		var stores []string
+		switch verifyIndexTarget {
+		case ReindexTargetAll:
+			stores = []string{models.IndexStoreMongo, models.IndexStoreElasticsearch}
+		case ReindexTargetMongo:
+			stores = []string{models.IndexStoreMongo}
+		case ReindexTargetElasticsearch:
+			stores = []string{models.IndexStoreElasticsearch}
+		default:
+			log.E(cmdL, "The --target flag must be all, mongo or elasticsearch.")
+			os.Exit(1)
+		}
+
+		log.D(cmdL, "Creating application...")
+		app := api.GetApp(
+			"0.0.0.0",
+			8888,
+			ConfigFile,
+			verifyIndexDebug,
+			l,
+			false,
+			false,
+		)
+		log.D(cmdL, "Application created successfully.")
+
+		gameIDs := []string{verifyIndexGameID}
+		if verifyIndexGameID == "" {
+			games, err := models.GetAllGames(app.Db(nil))
+			if err != nil {
+				log.E(cmdL, "Failed to load games.", func(cm log.CM) {
+					cm.Write(zap.Error(err))
+				})
+				os.Exit(1)
+			}
+			gameIDs = []string{}
+			for _, game := range games {
+				gameIDs = append(gameIDs, game.PublicID)
+			}
+		}
+
+		drifts, err := app.VerifyIndexes(context.Background(), gameIDs, stores, verifyIndexRepair)
+		for _, drift := range drifts {
+			driftJSON, _ := json.Marshal(drift.Serialize())
+			fmt.Println(string(driftJSON))
+		}
+		if err != nil {
+			log.E(cmdL, "Failed to verify indexes.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyIndexCmd)
+
+	verifyIndexCmd.Flags().BoolVarP(&verifyIndexDebug, "debug", "d", false, "Debug mode")
+	verifyIndexCmd.Flags().BoolVarP(&verifyIndexQuiet, "quiet", "q", false, "Quiet mode (log level error)")
+	verifyIndexCmd.Flags().StringVarP(&verifyIndexGameID, "game", "g", "", "game public ID, all games if empty")
+	verifyIndexCmd.Flags().StringVarP(&verifyIndexTarget, "target", "t", ReindexTargetAll, "all, mongo or elasticsearch")
+	verifyIndexCmd.Flags().BoolVarP(&verifyIndexRepair, "repair", "r", false, "enqueue jobs that fix the drifted clans")
+}
@@ -18,6 +18,20 @@
 // migrations/20160729184159_CreateCooldownAfterInviteField.sql
 // migrations/20160819145352_CreateHookTriggerFieldsMetadata.sql
 // migrations/20180517112014_ChangeIDSequenceType.sql
+// migrations/20261018100000_CreateHookDeadLettersTable.sql
+// migrations/20261018110000_CreateHookSecretField.sql
+// migrations/20261018120000_CreateHookFilterField.sql
+// migrations/20261018130000_CreateHookBatchFields.sql
+// migrations/20261018140000_CreateAuditEventsTable.sql
+// migrations/20261018150000_CreateGameTopClansDimensionsField.sql
+// migrations/20261018160000_CreateMembershipBanFields.sql
+// migrations/20261018170000_CreateClanCreatedAtIndex.sql
+// migrations/20261018180000_CreateHookPreFields.sql
+// migrations/20261018190000_CreateOutboxTable.sql
+// migrations/20261018200000_CreateGameClanSearchMetadataKeysField.sql
+// migrations/20261018210000_CreateAPIKeysTable.sql
+// migrations/20261018220000_CreatePlayerTokenConfigsTable.sql
+// migrations/20261018230000_CreateVersionFields.sql
 // DO NOT EDIT!
 
 package db
@@ -445,6 +459,286 @@ func migrations20180517112014_changeidsequencetypeSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations20261018100000_createhookdeadletterstableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x93\x4d\x6e\xdb\x30\x10\x85\xf7\x3a\xc5\xec\x6c\xa3\xb1\x95\xb6\x40\x16\x4e\x51\x54\xb5\xe9\xc2\xa8\x22\x27\xb2\x04\x24\x2b\x81\x92\x26\x12\x6b\x59\x24\x48\x2a\x8e\x11\xf4\x40\xbd\x46\x4f\x56\x52\xf2\x4f\xe2\xa4\x40\xbc\xe3\xcc\x37\xa3\x87\x37\xcf\xc3\x21\xac\x4a\x5a\x3b\xc3\x21\x94\x5a\x0b\x35\x76\xdd\x82\xe9\xb2\x49\x47\x19\x5f\xbb\x9a\x8b\x7b\x89\x58\xd0\x35\x2a\x77\xc7\x59\xd4\x67\x19\xd6\x0a\x73\x68\xea\x1c\x25\xe8\x12\xe1\x6a\x1e\x41\xd5\x95\xc7\xfb\x6d\x66\xd9\x66\xb3\x19\x71\x61\xaa\xbc\x91\x19\x8e\xb8\x2c\xdc\x1d\xa5\xdc\x35\xd3\xc3\xdd\xc3\x4e\x4c\xb8\xd8\x4a\x56\x94\x1a\xfe\xfe\x81\x4f\xe7\x1f\x2f\x20\xe2\x02\x66\xe6\xfb\xf0\xc3\x0a\x80\x2f\x29\xcd\x56\x58\xe7\xdf\xf4\x7d\x91\x71\x2b\xf0\xab\x63\x07\x3f\x14\x9c\x2b\x84\x58\xd8\xc7\xf2\xc6\x07\x56\x83\xc2\x4c\x33\x5e\x43\x2f\x16\x3d\x60\x0a\xf0\x11\xb3\x46\x1b\xc5\x9b\x12\x6b\x23\xd8\x94\xd6\xac\x90\xb4\x85\xcc\x83\x0a\x51\x31\xcc\x9d\x49\x48\xbc\x88\x40\xe4\x7d\xf7\x09\x94\x9c\xaf\x92\x1c\x69\x9e\x54\xa8\x35\x4a\x05\x7d\x07\xcc\x8f\xe5\x90\xb2\x42\xa1\x64\xb4\x82\xeb\x70\x7e\xe5\x85\x77\xf0\x93\xdc\x9d\xb5\x5d\xeb\x56\x62\x90\x07\x2a\xb3\x92\xca\xfe\xe7\x8b\x01\x04\x8b\x08\x82\xd8\xf7\x21\x24\x33\x12\x92\x60\x42\x96\x2d\x67\x36\x8a\x26\x35\x26\x98\x81\x41\x37\x7e\x78\xbf\xb9\xa0\x63\x5a\x61\xef\x01\xf1\x01\x6b\x9d\xe8\xad\x40\x63\x8a\xc6\xc2\x1c\xeb\x25\xd0\xc8\x0a\x34\x3e\xea\x93\xb2\xa0\xdb\x8a\xd3\x1c\x7e\x29\x5e\xa7\x47\xf5\x53\x32\xf3\x62\x3f\x82\xde\xd3\xef\xde\x78\xdc\x36\x3b\xbe\xa2\x4a\x27\x28\x25\x97\x2f\xb7\x1d\x27\x7a\x1d\x48\x8d\x8f\x6b\xa1\xd5\x2b\x39\x07\xf2\xbc\x03\x33\x89\xd4\xdc\x2b\xa1\xda\x7a\x6d\xe8\x53\xe1\x22\x3f\xed\xb7\xbd\xb6\x39\x59\x04\xcb\x28\xf4\xe6\x41\xd4\x5a\x65\x4f\xd8\x5d\x90\xe5\x3b\xdb\x8c\x6b\x71\x30\xbf\x89\x49\x7f\x77\xae\xb3\xa3\xf1\x03\x67\x70\xe9\xec\x93\x30\x0f\xa6\xe4\xf6\x75\x12\x92\x76\xec\x99\xc8\x45\xf0\x56\x5c\x0e\xcb\x8f\xa4\xdd\x7d\x0c\xed\x94\x6f\xea\x7d\x6c\x0f\x99\xb5\xc5\x77\xa5\x56\xf2\xaa\x32\x5d\xfb\xbf\x70\xa6\xe1\xe2\xfa\x7f\xb9\xbd\x74\xfe\x01\x27\x5d\x2b\x69\xe8\x03\x00\x00")
+
+func migrations20261018100000_createhookdeadletterstableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018100000_createhookdeadletterstableSql,
+		"migrations/20261018100000_CreateHookDeadLettersTable.sql",
+	)
+}
+
+func migrations20261018100000_createhookdeadletterstableSql() (*asset, error) {
+	bytes, err := migrations20261018100000_createhookdeadletterstableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018100000_CreateHookDeadLettersTable.sql", size: 1000, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018110000_createhooksecretfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\xce\xb1\x0e\x82\x30\x14\x05\xd0\xbd\x5f\x71\x37\x34\x86\xc5\x84\x89\x09\x2d\x4e\x15\x14\xdb\x0f\xa8\xf0\x02\x0d\x48\x49\x41\xf1\xf3\x05\xa3\x26\x26\x0e\x8e\xef\xbe\x9b\x9b\xe3\xfb\x58\x95\xd6\xf6\x04\xd5\x31\xdf\xc7\xe9\x28\x60\x5a\xf4\x94\x0f\xc6\xb6\xf0\x54\xe7\xc1\xf4\xa0\x3b\xe5\xd7\x81\x0a\x8c\x15\xb5\x18\xaa\x29\xba\x98\xd2\xe9\x67\x69\x3a\x74\xd7\x35\x86\x0a\x16\x09\x19\x67\x90\xd1\x46\xc4\xa8\xac\xad\x7b\x44\x9c\x63\x9b\x0a\xb5\x4f\xe6\x51\x47\x03\x6e\xda\xe5\x95\x76\x8b\x75\x10\x2c\x91\xa4\x12\x89\x12\x02\x3c\xde\x45\x4a\x48\x78\x5e\xc8\x66\xc8\x4b\xc5\xed\xd8\xbe\x5d\x1f\xd4\x1c\xfe\xc5\x72\xb6\x69\xa6\xef\x59\xe7\xf5\x0f\x1a\xcf\xd2\xc3\xb7\x2d\x64\x0f\x3a\x4b\x4d\xc3\x10\x01\x00\x00")
+
+func migrations20261018110000_createhooksecretfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018110000_createhooksecretfieldSql,
+		"migrations/20261018110000_CreateHookSecretField.sql",
+	)
+}
+
+func migrations20261018110000_createhooksecretfieldSql() (*asset, error) {
+	bytes, err := migrations20261018110000_createhooksecretfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018110000_CreateHookSecretField.sql", size: 272, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018120000_createhookfilterfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\xce\x3d\x0e\x82\x30\x18\x06\xe0\x9d\x53\xbc\x1b\x83\xe9\x09\x98\xd0\xe2\x54\x41\x91\x1e\x00\xe1\x13\x1a\x6a\xdb\xd0\x1a\x38\xbe\x60\xd4\xc4\xc4\xc1\xf1\xfd\x19\x1e\xc6\xb0\xe9\xac\xf5\x04\xe9\x22\xc6\x70\x3e\x09\x28\x03\x4f\x4d\x50\xd6\x20\x96\x2e\x86\xf2\xa0\x99\x9a\x7b\xa0\x16\x53\x4f\x06\xa1\x5f\xaa\x9b\xea\xc6\xfa\x79\x5a\x42\xed\x9c\x56\xd4\x46\xa9\xa8\xb2\x12\x55\xba\x15\x19\x7a\x6b\x07\x8f\x94\x73\xec\x0a\x21\x0f\x39\xae\x4a\x07\x1a\x11\x68\x0e\xc8\x8b\x0a\xb9\x14\x02\x3c\xdb\xa7\x52\x54\x88\xe3\x24\x5a\x01\x2f\x0d\xb7\x93\x79\x7b\x3e\x98\xb5\xfc\x8b\x33\x5a\xad\x97\xf5\x52\x37\xc3\x0f\x12\x2f\x8b\xe3\xb7\x29\x89\x1e\x50\xab\x7e\x7f\x08\x01\x00\x00")
+
+func migrations20261018120000_createhookfilterfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018120000_createhookfilterfieldSql,
+		"migrations/20261018120000_CreateHookFilterField.sql",
+	)
+}
+
+func migrations20261018120000_createhookfilterfieldSql() (*asset, error) {
+	bytes, err := migrations20261018120000_createhookfilterfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018120000_CreateHookFilterField.sql", size: 264, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018130000_createhookbatchfieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x95\x90\x31\x6f\x83\x30\x14\x84\x77\x7e\xc5\x6d\x19\x22\xa4\xee\x4c\xa4\xa6\x93\x0b\x6d\x82\x67\xe4\xe0\x57\xb0\xe2\xd8\x08\x3b\x4d\xd5\x5f\x5f\x53\x35\x5d\x1c\x29\xc9\xf8\x4e\xef\xee\x74\x5f\x9e\x63\x3d\x38\xe7\x09\x62\xca\xf2\x1c\xbb\x77\x0e\x6d\xe1\xa9\x0f\xda\x59\xac\xc4\xb4\x82\xf6\xa0\x2f\xea\x4f\x81\x14\xce\x23\x59\x84\x31\x4a\x47\x3d\xcc\xf2\xf7\x29\x1e\x72\x9a\x8c\x26\x95\x95\xbc\xad\xb6\x68\xcb\x0d\xaf\x30\x3a\x77\xf0\x28\x19\xc3\x73\xc3\xc5\x6b\x8d\xbd\x0c\xfd\xd8\x79\xfd\x4d\xb1\x22\xd0\x40\x33\xea\xa6\x45\x2d\x38\x07\xab\x5e\x4a\xc1\x5b\x3c\x15\xf7\x64\x2c\xf6\xf9\x53\x9a\x87\x72\x3a\x45\x52\x75\x86\x42\xf4\xa6\x99\x71\xdb\xde\x39\x43\xd2\xa6\x61\x1f\xd2\x78\x2a\xb2\x85\xcf\x1f\x2c\xe6\xce\xf6\x82\xeb\x9f\xd5\x22\xde\x45\x6b\x76\xc6\x2c\x7d\xb2\x3f\x5c\x59\xcb\xb6\xcd\x5b\x8a\xec\x1a\x97\xf4\xf3\x02\xe6\xd6\xfa\xc4\x49\xaa\xc8\x7e\x00\x77\x0b\x50\x40\x0c\x02\x00\x00")
+
+func migrations20261018130000_createhookbatchfieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018130000_createhookbatchfieldsSql,
+		"migrations/20261018130000_CreateHookBatchFields.sql",
+	)
+}
+
+func migrations20261018130000_createhookbatchfieldsSql() (*asset, error) {
+	bytes, err := migrations20261018130000_createhookbatchfieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018130000_CreateHookBatchFields.sql", size: 524, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018140000_createauditeventstableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x95\x54\xcd\x6e\xda\x40\x10\xbe\xfb\x29\xe6\x06\xa8\x80\xd3\x54\xe4\x40\xaa\xaa\x14\x4c\x85\xea\x98\xc4\xd8\x52\x73\x42\x6b\x7b\xb0\xb7\x31\xbb\xdb\xdd\x75\x48\x54\xf5\x81\xf2\x1a\x79\xb2\xae\x7f\xa0\x84\x52\x09\x7c\x9b\x99\x6f\xe6\x9b\xd9\xf9\xc6\xbd\x1e\x3c\x64\x84\x59\xbd\x1e\x64\x5a\x0b\x35\xb4\xed\x94\xea\xac\x88\xfa\x31\x5f\xdb\x9a\x8b\x95\x44\x4c\xc9\x1a\x95\xdd\xe0\x4a\xa8\x4b\x63\x64\x0a\x13\x28\x58\x82\x12\x74\x86\x70\x33\x0b\x20\xaf\xdd\xc3\x6d\x35\x53\x6c\xb3\xd9\xf4\xb9\x30\x5e\x5e\xc8\x18\xfb\x5c\xa6\x76\x83\x52\xf6\x9a\xea\x5e\x63\x94\x19\x63\x2e\x9e\x25\x4d\x33\x0d\xaf\x2f\x70\x79\xf1\xfe\x0a\x02\x2e\x60\x6a\xf8\xe1\x6b\xd9\x00\x7c\x8c\x48\xfc\x80\x2c\xf9\xac\x57\x69\xcc\xcb\x06\x3f\x59\x65\xe2\xbb\x94\x73\x85\x10\x8a\xd2\x58\xdc\xb9\x40\x19\x28\x8c\x35\xe5\x0c\x5a\xa1\x68\x01\x55\x80\x4f\x18\x17\xda\x74\xbc\xc9\x90\x99\x86\x8d\x6b\x4d\x53\x49\x2a\x90\x31\x88\x10\x39\xc5\xc4\x1a\xfb\xce\x28\x70\x20\x18\x7d\x71\x1d\x20\x45\x42\xf5\x12\x1f\x91\x69\x05\x6d\x0b\xcc\x47\x13\x88\x68\xaa\x50\x52\x92\xc3\xad\x3f\xbb\x19\xf9\xf7\xf0\xcd\xb9\xef\x56\xd1\xf2\xa1\x96\x06\xf2\x48\x64\x9c\x11\xd9\xfe\x70\xd5\x01\x6f\x1e\x80\x17\xba\x2e\xf8\xce\xd4\xf1\x1d\x6f\xec\x2c\x2a\x9c\xa9\x28\x8a\xc8\xcc\x6f\x12\x3a\x75\xfa\xce\x3e\x5a\xa0\xc6\x90\x7a\xae\x2d\x60\x70\x71\x04\xc0\xe5\xf2\xdf\x52\x97\x83\xc1\x5e\x33\x13\x67\x3a\x0a\xdd\x00\x5a\xad\x3a\x2b\xce\x09\x3b\x3b\x49\xe4\xe4\x19\xcf\xe7\x8a\x70\xc5\x25\xc2\x0f\xc5\x59\x74\x04\xf5\xeb\x77\x6b\x38\xac\x82\xcd\x40\x2b\x6d\x34\x76\x2a\x5a\xe2\xcf\x02\x95\x3e\x63\x72\x89\xc4\x08\x63\x49\x74\xb9\x59\xca\xf4\xde\x7b\x56\x80\xf1\xdc\x5b\x04\xfe\x68\xe6\x05\xb5\x20\x2a\x3d\xd0\xa4\x19\xdb\xf0\x84\xde\xec\x2e\x74\xda\xcd\xf2\xbb\x7f\xd7\xd8\xb1\x3a\xd7\xd6\x56\x52\x33\x6f\xe2\x7c\x7f\x23\xa9\x65\xf5\xe8\x7b\xfc\x73\xef\x40\x72\xbb\x92\x6f\xd7\xd3\xdd\x6b\xda\x30\xfc\x9f\xa0\x59\xd0\x49\x14\x87\xcb\x3c\x99\xa4\x16\xdc\x49\x1c\x07\xda\x3c\xa0\xd8\xbb\xe5\x09\xdf\xb0\xed\x35\xef\x4e\xb9\x74\x9e\x74\xcc\x92\xe7\xb9\x89\x96\xbf\x0b\x6b\xe2\xcf\x6f\x8f\x9c\xf3\xb5\xf5\x07\xde\x25\xbd\x90\xfa\x04\x00\x00")
+
+func migrations20261018140000_createauditeventstableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018140000_createauditeventstableSql,
+		"migrations/20261018140000_CreateAuditEventsTable.sql",
+	)
+}
+
+func migrations20261018140000_createauditeventstableSql() (*asset, error) {
+	bytes, err := migrations20261018140000_createauditeventstableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018140000_CreateAuditEventsTable.sql", size: 1274, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018150000_creategametopclansdimensionsfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x90\x41\x4e\xc3\x30\x10\x45\xf7\x39\xc5\xec\x02\x42\x69\x42\x17\x2c\x5a\x84\x08\x4d\x8b\x90\xdc\x16\x4a\xb2\x8e\x5c\x67\x9a\x58\x4d\x6c\xcb\x76\x08\x1c\x89\x6b\x70\x32\xec\xd2\x22\x16\x2c\xba\xfc\xdf\x7f\xbe\xdf\x4c\x14\xc1\xbe\xa1\x22\x88\x22\x68\xac\x55\x66\x12\xc7\x35\xb7\x4d\xbf\x1d\x31\xd9\xc5\x56\xaa\x9d\x46\xac\x69\x87\x26\x3e\xe6\x7c\x94\x70\x86\xc2\x60\x05\xbd\xa8\x50\x83\x6d\x10\x96\x4f\x39\xb4\x3f\xf6\xe4\xd4\xe6\xca\x86\x61\x18\x49\xe5\x5c\xd9\x6b\x86\x23\xa9\xeb\xf8\x98\x32\x71\xc7\x6d\x74\x14\x7e\x62\x26\xd5\x87\xe6\x75\x63\xe1\xeb\x13\xc6\xc9\xf5\x0d\xe4\x52\xc1\xc2\xfd\x0f\x8f\x1e\x00\x6e\xb7\x94\xed\x51\x54\xf7\x76\x57\x33\xe9\x01\xef\x02\x3f\x78\x55\x4b\x69\x10\x0a\xe5\xc5\xeb\x0b\x01\x2e\xc0\x20\xb3\x5c\x0a\x08\x0b\x15\x02\x37\x80\xef\xc8\x7a\xeb\x88\x87\x06\x85\x03\x76\x56\xc7\x6b\x4d\x0f\x21\x27\xa8\x52\x2d\xc7\x2a\x48\x49\x3e\xdf\x40\x9e\x3e\x90\x39\x1c\xd6\x86\x34\xcb\x60\xb6\x26\xc5\x72\x05\xee\x1c\x25\x6b\xa9\x30\x65\x87\x96\x56\xd4\xd2\xb2\xe2\x9d\xe3\x77\x25\x06\xde\xa8\x66\x0d\xd5\x17\xe3\x24\x49\x2e\x61\xb5\xce\x61\x55\x10\x02\xd9\x7c\x91\x16\x24\x87\x30\x9c\xfe\xa5\xcd\xe4\x20\x4e\xbc\xbf\xb0\xde\x3c\x0b\x57\xcb\xb6\x75\xaf\xfe\x20\xff\x20\x67\x9b\xf5\xf3\x59\xcc\xd3\xe0\x1b\xb9\xad\x43\x14\x00\x02\x00\x00")
+
+func migrations20261018150000_creategametopclansdimensionsfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018150000_creategametopclansdimensionsfieldSql,
+		"migrations/20261018150000_CreateGameTopClansDimensionsField.sql",
+	)
+}
+
+func migrations20261018150000_creategametopclansdimensionsfieldSql() (*asset, error) {
+	bytes, err := migrations20261018150000_creategametopclansdimensionsfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018150000_CreateGameTopClansDimensionsField.sql", size: 512, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018160000_createmembershipbanfieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x95\x90\x41\x4e\xc3\x30\x10\x45\xf7\x39\xc5\xec\xba\x40\x69\x0a\x0b\x16\x2d\x42\x84\xa6\x45\x48\x6e\x0b\x6d\xb2\x46\x71\x32\xb5\xad\x26\xb6\x65\x3b\x0a\x3d\x12\xd7\xe0\x64\xd8\xd0\x22\x24\x5a\x09\x96\xf3\xfd\xe7\xfb\xfd\x89\x63\xd8\xf1\x52\x46\x71\x0c\xdc\x39\x6d\xc7\x49\xc2\x84\xe3\x1d\x1d\x56\xaa\x4d\x9c\xd2\x5b\x83\xc8\xca\x16\x6d\x72\xf0\x05\x2b\x11\x15\x4a\x8b\x35\x74\xb2\x46\x03\x8e\x23\x2c\x1e\x73\x68\xbe\xe4\xf1\x31\xcd\x87\xf5\x7d\x3f\x54\xda\xab\xaa\x33\x15\x0e\x95\x61\xc9\xc1\x65\x93\x56\xb8\xf8\x30\x84\x8d\xa9\xd2\x7b\x23\x18\x77\xf0\xfe\x06\x57\xa3\xcb\x6b\xc8\x95\x86\xb9\xff\x1f\x1e\x02\x00\xdc\xd0\xb2\xda\xa1\xac\xef\xdc\x96\x55\x2a\x00\xde\x46\x61\xf1\x82\x29\x65\x11\x0a\x1d\x86\xcd\x33\x01\x21\xc1\x62\xe5\x84\x92\x30\x28\xf4\x00\x84\x05\x7c\xc5\xaa\x73\x9e\xb8\xe7\x28\x3d\xb0\x97\x5a\xc1\x4c\xf9\x69\xf2\x43\xa9\x75\x23\xb0\x8e\x52\x92\xcf\xd6\x90\xa7\xf7\x64\x06\x2d\xb6\x14\x8d\xe5\x42\x5b\x48\xb3\x0c\xa6\x2b\x52\x2c\x96\x40\x4b\x29\xb1\x7e\x29\x1d\x50\xc1\x84\x74\xb0\x5c\xe5\xb0\x2c\x08\x81\x6c\x36\x4f\x0b\x92\xc3\x68\xf2\x8f\x1c\xba\xf7\xbc\x0e\x99\xbf\xe3\xa9\xa0\x1f\x05\x33\xd5\xcb\x63\xc5\xef\x7e\x41\xfc\x53\x43\xa3\x9a\xc6\xbf\x86\x1b\x9e\xa5\xcb\xd6\xab\xa7\x5f\x35\xcf\x97\x39\x61\xa7\xfb\x49\xf4\x01\x67\xbd\x71\x13\x54\x02\x00\x00")
+
+func migrations20261018160000_createmembershipbanfieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018160000_createmembershipbanfieldsSql,
+		"migrations/20261018160000_CreateMembershipBanFields.sql",
+	)
+}
+
+func migrations20261018160000_createmembershipbanfieldsSql() (*asset, error) {
+	bytes, err := migrations20261018160000_createmembershipbanfieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018160000_CreateMembershipBanFields.sql", size: 596, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018170000_createclancreatedatindexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x8f\x4b\x4e\xc3\x30\x10\x86\xf7\x39\xc5\xec\x0a\xa2\x49\x80\x05\x8b\x16\x21\x50\x93\xa2\x48\xa5\x85\x26\x95\xba\x8b\x5c\x67\xea\x58\x4d\x6c\xcb\x76\x14\x38\x12\xd7\xe0\x64\xd8\x34\x05\x56\x88\xe5\xff\x98\x99\x6f\xc2\x10\x0e\x35\x11\x41\x18\x42\x6d\xad\x32\x93\x38\x66\xdc\xd6\xdd\x2e\xa2\xb2\x8d\xad\x54\x7b\x8d\xc8\x48\x8b\x26\x1e\x7a\xbe\xba\xe0\x14\x85\xc1\x0a\x3a\x51\xa1\x06\x5b\x23\x3c\x65\x05\x34\x47\x7b\x72\xda\xe6\x96\xf5\x7d\x1f\x49\xe5\x5c\xd9\x69\x8a\x91\xd4\x2c\x1e\x5a\x26\x6e\xb9\x0d\x07\xe1\x27\x66\x52\xbd\x69\xce\x6a\x0b\x1f\xef\x70\x7d\x79\x75\x03\x85\x54\x30\x77\xf7\xe1\xd1\x03\xc0\xed\x8e\xd0\x03\x8a\xea\xde\xee\x19\x95\x1e\xf0\x2e\xf0\x83\x17\x4c\x4a\x83\xb0\x51\x5e\xe4\x2f\x0b\xe0\x02\x0c\x52\xcb\xa5\x80\xd1\x46\x8d\x80\x1b\xc0\x57\xa4\x9d\x75\xc4\x7d\x8d\xc2\x01\x3b\xab\xe5\x4c\x93\xaf\x92\x13\x44\xa9\x86\x63\x15\xcc\xd6\xe9\x43\x91\x42\xb6\x4c\xd2\x2d\xd0\x86\x08\x53\xfa\xef\x4b\x5e\x95\x54\x23\x71\x1b\x4a\x62\x9d\x82\xd5\xf2\x18\x9f\x0d\xf1\x18\x7e\xf2\x31\xf0\xea\x7c\xfa\x9b\x2d\x91\xbd\x38\xd1\x7d\xa3\x79\xf3\x5f\x70\x5a\x36\x8d\x4b\xfd\xfb\x41\xb2\x5e\x3d\x0f\x78\xd9\x1c\xd2\x6d\x96\x17\xf9\x5f\xa0\xd3\xe0\x13\x6a\xc3\x61\xc6\xe3\x01\x00\x00")
+
+func migrations20261018170000_createclancreatedatindexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018170000_createclancreatedatindexSql,
+		"migrations/20261018170000_CreateClanCreatedAtIndex.sql",
+	)
+}
+
+func migrations20261018170000_createclancreatedatindexSql() (*asset, error) {
+	bytes, err := migrations20261018170000_createclancreatedatindexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018170000_CreateClanCreatedAtIndex.sql", size: 483, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018180000_createhookprefieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x90\x41\x4e\xc3\x30\x10\x45\xf7\x39\xc5\xec\x02\x42\x69\x5a\x16\x2c\x5a\x84\x08\x4d\x8b\x90\xd2\x16\x4a\xb2\x46\x6e\x32\x49\xac\x24\x1e\xcb\x76\x09\x1c\x89\x6b\x70\x32\x6c\x68\xab\x4a\x54\x88\xe5\x7c\xff\xf9\x7e\x7f\x82\x00\x9a\x9a\x09\x2f\x08\xa0\x36\x46\xea\x71\x18\x56\xdc\xd4\xdb\xcd\x20\xa7\x2e\x34\x24\x4b\x85\x58\xb1\x0e\x75\xb8\xf3\x39\x6b\xc2\x73\x14\x1a\x0b\xd8\x8a\x02\x15\x98\x1a\x61\xf1\x90\x42\xfb\x23\x8f\xf7\x69\x36\xac\xef\xfb\x01\x49\xab\xd2\x56\xe5\x38\x20\x55\x85\x3b\x97\x0e\x3b\x6e\x82\xdd\xe0\x36\xa6\x24\xdf\x15\xaf\x6a\x03\x9f\x1f\x70\x39\x1c\x5d\x41\x4a\x12\xe6\xf6\x7f\xb8\x77\x00\x70\xbd\x61\x79\x83\xa2\xb8\x35\x65\x95\x93\x03\xbc\xf1\xdc\xe2\x45\x45\xa4\x11\x32\xe9\x86\xe7\xa7\x04\xb8\x00\x8d\xb9\xe1\x24\xc0\xcf\xa4\x0f\x5c\x03\xbe\x61\xbe\x35\x96\xb8\xaf\x51\x58\x60\x2b\x75\xbc\x52\xec\xdb\x64\x07\x26\x65\xcb\xb1\xf0\xa2\x24\x9d\xad\x21\x8d\xee\x92\x19\xd4\x44\x8d\x86\x28\x8e\x61\xba\x4a\xb2\xc5\x12\x1a\x2e\x0a\x78\x65\x2a\xaf\x99\x3a\x1b\x0d\xcf\x61\xb9\x4a\x61\x99\x25\x09\xc4\xb3\x79\x94\x25\x29\xf8\x92\xb4\xf1\x27\x7f\xc7\x94\x8c\xb7\x2f\xee\x28\xb0\x21\x6a\x91\x89\xdf\x39\x25\x6b\x35\x4e\x8e\xdb\xc5\xd4\x8b\x7d\xbf\x43\x39\x27\xfe\xab\x9e\xa2\xb6\xb5\xaf\xee\x80\x27\xd8\xe2\xf5\xea\xf1\xb8\xe3\x29\xfe\x63\xcf\xa1\xc0\xc4\xfb\x02\x38\xe2\xf3\x6a\x40\x02\x00\x00")
+
+func migrations20261018180000_createhookprefieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018180000_createhookprefieldsSql,
+		"migrations/20261018180000_CreateHookPreFields.sql",
+	)
+}
+
+func migrations20261018180000_createhookprefieldsSql() (*asset, error) {
+	bytes, err := migrations20261018180000_createhookprefieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018180000_CreateHookPreFields.sql", size: 576, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018190000_createoutboxtableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x52\xcb\x6e\xdb\x30\x10\xbc\xeb\x2b\xf6\xe6\x18\xad\x2c\x37\x40\x7a\x90\x93\xa0\x6e\xac\x34\x46\x65\x3b\x55\x64\x24\x39\x05\x14\xb5\xa1\xd8\xc8\x24\x4b\x52\x95\x8b\xa2\x1f\x94\xdf\xc8\x97\x95\xf4\x0b\x4e\xe0\x43\x78\xdb\xd9\xd9\xd9\x59\x0c\xc3\x10\x9e\x2a\x22\x82\x30\x84\xca\x5a\x65\xe2\x28\x62\xdc\x56\x4d\xd1\xa3\x72\x11\x59\xa9\x1e\x35\x22\x23\x0b\x34\xd1\x86\xe7\xa9\x29\xa7\x28\x0c\x96\xd0\x88\x12\x35\xd8\x0a\x61\x32\xce\xa1\x5e\xc3\xf1\x56\xcd\x89\xb5\x6d\xdb\x93\xca\xa1\xb2\xd1\x14\x7b\x52\xb3\x68\xc3\x32\xd1\x82\xdb\x70\x53\xf8\x89\x0b\xa9\xfe\x68\xce\x2a\x0b\x2f\xcf\x70\xdc\xff\xf4\x19\x72\xa9\xe0\xd2\xed\x87\x6f\xde\x00\x9c\x16\x84\x3e\xa1\x28\xbf\xd8\x47\x46\xa5\x37\x78\x1e\xf8\xc1\x0f\x4c\x4a\x83\x30\x57\xbe\xb8\xf9\x91\x02\x17\x60\x90\x5a\x2e\x05\x74\xe6\xaa\x03\xdc\x00\x2e\x91\x36\xd6\x39\x6e\x2b\x14\xce\xb0\x83\x16\x9c\x69\xb2\x22\xb9\x82\x28\x55\x73\x2c\x83\x8b\x2c\x19\xe6\x09\xe4\xc3\xaf\x69\x02\xb2\xb1\x85\x5c\xc2\x51\x00\xee\xf1\x12\x0a\xce\x0c\x6a\x4e\x6a\xb8\xce\xc6\x93\x61\x76\x0f\xdf\x93\xfb\x8f\xab\xee\xaf\x06\x1b\x84\xdf\x44\xd3\x8a\xe8\xa3\xe3\x93\x93\x2e\x4c\x67\x39\x4c\xe7\x69\xba\x26\x10\xcd\x0c\xfc\x34\x52\x14\xbb\x06\x8c\x92\xcb\xe1\x3c\xcd\xa1\xf3\xf7\x5f\x27\x8e\x57\xcd\x35\x99\x6a\x24\xce\xec\x03\xb1\x7e\x27\x17\xf6\x8d\x98\x6a\x8a\x9a\x9b\xea\x20\x63\xa7\xda\x0f\xba\x83\x60\x7b\xd0\x78\x3a\x4a\xee\x36\x07\x3d\xb8\x3c\x4a\x2e\x18\xcc\xa6\xbb\x13\x79\xd9\x85\xdb\xab\x24\x4b\x5e\x6b\x9f\x41\x7f\x70\x58\x62\x9f\xb5\xa7\xb3\x8f\x1f\x54\x3c\xf7\x8a\x7b\xa9\x8d\x64\x2b\xb6\xb9\xed\x42\xf3\xe0\xbb\x62\xd3\xb2\xae\x5d\xd7\x7f\x8c\x60\x94\xcd\xae\x5f\x05\x37\x08\xfe\x03\x3d\x27\xd8\x03\xde\x02\x00\x00")
+
+func migrations20261018190000_createoutboxtableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018190000_createoutboxtableSql,
+		"migrations/20261018190000_CreateOutboxTable.sql",
+	)
+}
+
+func migrations20261018190000_createoutboxtableSql() (*asset, error) {
+	bytes, err := migrations20261018190000_createoutboxtableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018190000_CreateOutboxTable.sql", size: 734, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018200000_creategameclansearchmetadatakeysfieldSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x90\xd1\x4e\xc2\x30\x14\x86\xef\xf7\x14\xe7\x6e\x1a\x33\x36\xb9\xf0\x02\x8c\x71\x32\x30\x26\x05\x04\xb7\x6b\x52\xba\xc3\xd6\xb0\xb5\x4d\xdb\x39\x79\x24\x5f\xc3\x27\xb3\x45\x30\x5e\x98\xe8\xe5\xff\xf5\x3f\xa7\x5f\x1b\x45\xb0\xaf\xa9\x08\xa2\x08\x6a\x6b\x95\x19\xc5\x71\xc5\x6d\xdd\x6d\x07\x4c\xb6\xb1\x95\x6a\xa7\x11\x2b\xda\xa2\x89\x4f\x3d\x5f\x25\x9c\xa1\x30\x58\x42\x27\x4a\xd4\x60\x6b\x84\xf9\x53\x0e\xcd\x17\x1e\x9d\xb7\xb9\x65\x7d\xdf\x0f\xa4\x72\x54\x76\x9a\xe1\x40\xea\x2a\x3e\xb5\x4c\xdc\x72\x1b\x9d\x82\x9f\x98\x48\x75\xd0\xbc\xaa\x2d\x7c\xbc\xc3\x30\xb9\xbe\x81\x5c\x2a\x98\xb9\xfb\xe1\xd1\x0b\xc0\xed\x96\xb2\x3d\x8a\xf2\xde\xee\x2a\x26\xbd\xe0\x5d\xe0\x07\xaf\x2a\x29\x0d\x42\xa1\x7c\x78\x59\x11\xe0\x02\x0c\x32\xcb\xa5\x80\xb0\x50\x21\x70\x03\xf8\x86\xac\xb3\xce\xb8\xaf\x51\x38\x61\x87\x5a\x5e\x69\x7a\x2c\xb9\x40\x95\x6a\x38\x96\x41\x4a\xf2\xe9\x1a\xf2\xf4\x81\x4c\xe1\xf8\x6c\x48\xb3\x0c\x26\x4b\x52\xcc\x17\xc0\x1a\x2a\x36\x06\xa9\x66\xf5\xa6\x45\x4b\x4b\x6a\xe9\x66\x8f\x07\x03\xaf\x9e\x51\x7d\x31\x4c\x92\xe4\x12\x16\xcb\x1c\x16\x05\x21\x90\x4d\x67\x69\x41\x72\x08\xc3\xf1\x4f\xd3\x4c\xf6\xe2\xec\xfa\x2d\xea\xe1\xbf\x54\xb5\x6c\x1a\x77\xea\x3f\xe3\x17\xdd\x6c\xbd\x7c\xfe\xd3\x77\x1c\x7c\x02\x71\x5e\xf9\x2d\xf8\x01\x00\x00")
+
+func migrations20261018200000_creategameclansearchmetadatakeysfieldSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018200000_creategameclansearchmetadatakeysfieldSql,
+		"migrations/20261018200000_CreateGameClanSearchMetadataKeysField.sql",
+	)
+}
+
+func migrations20261018200000_creategameclansearchmetadatakeysfieldSql() (*asset, error) {
+	bytes, err := migrations20261018200000_creategameclansearchmetadatakeysfieldSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018200000_CreateGameClanSearchMetadataKeysField.sql", size: 504, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018210000_createapikeystableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x53\xd1\x6e\x9b\x30\x14\x7d\xe7\x2b\xee\x5b\x12\xad\x84\x36\x5b\xf3\xd0\x56\xd3\xb2\xc4\x99\xd0\x28\x69\x09\x48\xeb\x13\x72\xc0\x01\x2b\x09\xb6\x6c\x53\xda\x4f\xda\x6f\xec\xcb\x66\x03\x21\x11\xed\xd4\xf1\x76\xef\x3d\xe7\xf8\xfa\xf8\x60\xdb\xb0\xcb\x71\x61\xd9\x36\xe4\x4a\x71\x79\xe3\x38\x19\x55\x79\xb9\x19\x27\xec\xe0\x28\xc6\xb7\x82\x90\x0c\x1f\x88\x74\x5a\x9c\x81\x7a\x34\x21\x85\x24\x29\x94\x45\x4a\x04\xa8\x9c\xc0\xbd\x1b\xc2\xbe\x69\xdf\x1c\xd5\xb4\x58\x55\x55\x63\xc6\x75\x97\x95\x22\x21\x63\x26\x32\xa7\x45\x49\xe7\x40\x95\xdd\x16\x86\x31\x67\xfc\x55\xd0\x2c\x57\xf0\xe7\x37\x4c\x2e\xaf\xa6\x10\x32\x0e\x4b\x7d\x3e\xfc\x30\x0b\xc0\xdd\x06\x27\x3b\x52\xa4\xdf\xd4\x36\x4b\x98\x59\xf0\xab\x65\x88\x9f\x32\xc6\x24\x81\x88\x9b\x62\xfd\xe8\x01\x2d\x40\x92\x44\x51\x56\xc0\x20\xe2\x03\xa0\x12\xc8\x0b\x49\x4a\xa5\x37\xae\x72\x52\xe8\x85\x75\xeb\x40\x33\x81\x6b\x90\x2e\x30\xe7\x7b\x4a\x52\x6b\x1e\xa0\x59\x88\x20\x9c\x7d\xf7\x90\x6e\xd2\x78\x47\x5e\x25\x0c\x2d\xd0\x1f\x4d\x61\x43\x33\x49\x04\xc5\x7b\x78\x08\xdc\xfb\x59\xf0\x04\x3f\xd1\xd3\x45\x3d\x35\x26\xc5\x1a\xf2\x8c\x45\x92\x63\x31\xfc\x3c\x1d\x81\xbf\x0a\xc1\x8f\x3c\x0f\x02\xb4\x44\x01\xf2\xe7\x68\x5d\xe3\xb4\x22\x2f\x37\xfa\xee\x9a\x30\x6a\xe8\x5d\xfd\xae\x40\x83\x29\x34\xb5\x1b\x4f\xae\xaf\xcf\x0e\x58\xa0\xe5\x2c\xf2\x42\x18\x0c\x1a\xa8\x4c\xb4\xeb\x27\xa9\x49\x5f\x4a\x5f\x2b\xce\xb1\xcc\x3b\xc8\xf4\xcb\x7b\x10\x2e\xc8\x96\xbe\x74\xa0\xab\x37\x2b\x25\x82\x60\x6d\x6b\x8c\x95\xf1\x86\x16\xaa\x37\x2f\x79\xda\x9f\x77\x33\xc1\xd4\x3f\xb8\xdd\x75\x2e\x5b\x24\x79\x66\xbb\x0f\x91\x35\x74\xbe\xf2\xd7\x61\x30\x73\xfd\xd0\x3c\x9f\xbe\x03\x4d\xe3\xc6\x5b\x6d\x6d\xe4\xbb\x8f\x11\x1a\xb6\x4f\x75\x01\xfd\x47\x78\x43\x8e\x3b\x9f\x5a\xea\xb1\x1e\x59\xa3\x5b\xeb\x98\x16\xd7\x5f\xa0\x5f\x5d\x5a\xe2\x5a\xfe\xcc\x98\x95\x7f\x96\xa4\xee\xec\x13\xc0\x28\x9d\x62\xbc\x60\x55\x71\x0c\x72\x97\x62\xd3\xfc\xaf\x1c\x0b\xb6\xdf\xeb\xa9\xf9\x53\xac\x45\xb0\x7a\xe8\x25\xf9\xd6\xfa\x0b\xbd\xe6\x2c\x9a\xf1\x03\x00\x00")
+
+func migrations20261018210000_createapikeystableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018210000_createapikeystableSql,
+		"migrations/20261018210000_CreateAPIKeysTable.sql",
+	)
+}
+
+func migrations20261018210000_createapikeystableSql() (*asset, error) {
+	bytes, err := migrations20261018210000_createapikeystableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018210000_CreateAPIKeysTable.sql", size: 1009, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018220000_createplayertokenconfigstableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x52\xc1\x6e\xdb\x30\x0c\xbd\xfb\x2b\x78\x4b\x82\xcd\xf1\xda\xa1\x3d\xb4\xc3\x30\x2f\x51\x86\x60\xae\xd3\x3a\xf6\xa1\x27\x43\x91\x19\x5b\x88\x23\x09\x92\x3c\x37\x9f\xd4\xdf\xd8\x97\x4d\x76\x9c\x22\xcb\x80\xa1\xbc\x91\x7c\xe4\x23\x1e\x9f\xef\xc3\xae\xa2\xc2\xf3\x7d\xa8\xac\x55\xe6\x2e\x08\x4a\x6e\xab\x66\x33\x65\x72\x1f\x58\xa9\xb6\x1a\xb1\xa4\x7b\x34\xc1\x80\xeb\xa0\x11\x67\x28\x0c\x16\xd0\x88\x02\x35\xd8\x0a\xe1\x61\x99\x42\x7d\x2c\xdf\x9d\xb6\xb9\x65\x6d\xdb\x4e\xa5\x72\x55\xd9\x68\x86\x53\xa9\xcb\x60\x40\x99\x60\xcf\xad\x3f\x24\xdd\xc4\x4c\xaa\x83\xe6\x65\x65\xe1\xf7\x2b\x5c\x7f\xba\xba\x85\x54\x2a\x58\x38\x7e\xf8\xd1\x1d\x00\x5f\x36\x94\xed\x50\x14\xdf\xec\xb6\x64\xb2\x3b\xf0\xab\xd7\x0d\x7e\x28\xa5\x34\x08\x99\xea\x92\xf5\x53\x04\x5c\x80\x41\x66\xb9\x14\x30\xca\xd4\x08\xb8\x01\x7c\x41\xd6\x58\x77\x71\x5b\xa1\x70\x07\xbb\xd2\x9e\x97\x9a\xf6\x20\x97\x50\xa5\x6a\x8e\x85\x37\x4b\x48\x98\x12\x48\xc3\xef\x11\x01\x55\xd3\x03\xea\xdc\x4a\xc7\x9a\x33\x29\xb6\xbc\x34\x30\xf6\xc0\x05\x2f\x60\xe3\x32\xd4\x9c\xd6\xf0\x98\x2c\x1f\xc2\xe4\x19\x7e\x92\xe7\x8f\x7d\xb7\x13\x2c\x77\x90\x5f\x54\xb3\x8a\xea\xf1\xe7\xdb\x09\xc4\xab\x14\xe2\x2c\x8a\x20\x21\x0b\x92\x90\x78\x46\xd6\x3d\xce\x6d\x54\xcd\xc6\xe9\xe0\x06\x26\xc7\x71\x5a\x97\x52\xbb\x2f\xec\xdf\x16\x5c\x9d\x2d\x38\x62\x76\x78\x00\x8b\x2f\xf6\xa2\xcc\x8d\x69\xdc\x47\x4e\x73\xd7\x37\x37\x67\xcc\x73\xb2\x08\xb3\x28\x85\xd1\x68\xe0\x69\x0a\x8e\x82\xe1\x7b\xe1\x75\x2d\x5b\x2c\x72\x2d\x9d\x92\xe6\x6f\xf6\x7f\xc0\x4c\x23\x75\x7a\xe7\xd4\x76\x42\x71\x71\x79\x68\xa3\x8a\xcb\x7e\xdf\xeb\x9b\xb3\x55\xbc\x4e\x93\x70\x19\xa7\xc3\x0f\xfa\x17\x1c\x3f\x90\x9f\xc4\xcd\xe2\xe5\x53\x46\xc6\x43\x3a\xf1\x26\xf7\xe7\x6e\x98\xcb\x56\x9c\xfc\xf0\x66\x86\xae\xf8\x2e\x3b\x68\x59\xd7\xae\xdb\x19\xce\x9b\x27\xab\xc7\xff\x18\xe2\xde\xfb\x03\xb4\x06\x42\x5e\x44\x03\x00\x00")
+
+func migrations20261018220000_createplayertokenconfigstableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018220000_createplayertokenconfigstableSql,
+		"migrations/20261018220000_CreatePlayerTokenConfigsTable.sql",
+	)
+}
+
+func migrations20261018220000_createplayertokenconfigstableSql() (*asset, error) {
+	bytes, err := migrations20261018220000_createplayertokenconfigstableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018220000_CreatePlayerTokenConfigsTable.sql", size: 836, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20261018230000_createversionfieldsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xa5\x90\x41\x4e\xc3\x30\x10\x45\xf7\x3d\xc5\xec\xba\x40\x69\x28\x0b\x16\x05\x21\x42\xd3\x22\x24\xb7\x85\x36\x39\x80\xeb\x4c\x1d\xab\x89\x6d\xd9\x0e\xa1\x47\xe2\x1a\x9c\x0c\xbb\x4d\x11\xa0\x4a\x80\x58\xce\xf7\xfb\xe3\x3f\x3f\x8a\x60\x5b\x52\xd9\x8b\x22\x28\x9d\xd3\x76\x14\xc7\x5c\xb8\xb2\x59\x0f\x98\xaa\x63\xa7\xf4\xc6\x20\x72\x5a\xa3\x8d\x3b\x2e\xa0\x44\x30\x94\x16\x0b\x68\x64\x81\x06\x5c\x89\x30\x7b\xc8\xa0\x3a\xc8\xa3\xe3\x36\xbf\xac\x6d\xdb\x81\xd2\x5e\x55\x8d\x61\x38\x50\x86\xc7\x1d\x65\xe3\x5a\xb8\xa8\x1b\x82\x63\xac\xf4\xce\x08\x5e\x3a\x78\x7b\x85\x8b\xf3\xe1\x25\x64\x4a\xc3\xd4\xff\x0f\xf7\x21\x00\x5c\xaf\x29\xdb\xa2\x2c\x6e\xdd\x86\x33\x15\x02\xde\xf4\x82\xf1\x8c\x2b\x65\x11\x72\x1d\x86\xd5\x13\x01\x21\xc1\x22\x73\x42\x49\xe8\xe7\xba\x0f\xc2\x02\xbe\x20\x6b\x9c\x4f\xdc\x96\x28\x7d\x60\x2f\xd5\x82\x1b\xba\x87\xfc\x40\xb5\xae\x04\x16\xbd\x84\x64\x93\x25\x64\xc9\x1d\x99\xc0\xfe\x6c\x48\xd2\x14\xc6\x0b\x92\xcf\xe6\xf0\x8c\xc6\x06\x7e\x2d\xb8\x90\x0e\xe6\x8b\x0c\xe6\x39\x21\x90\x4e\xa6\x49\x4e\x32\x18\x5e\x7d\xf1\xeb\x8a\xee\xbc\xe3\x1f\x1b\x58\x45\xe5\x5f\xfd\x9f\x1a\x49\x55\x2b\x8f\x9d\x7c\x14\x12\xc4\x5f\x55\x62\x54\x55\xf9\xd7\x50\xfa\x89\x50\xe9\x72\xf1\xf8\x2d\xd5\xe9\xeb\x7f\x04\x0f\x35\x9f\xc4\xde\x01\x49\x8c\xcd\xba\x9e\x02\x00\x00")
+
+func migrations20261018230000_createversionfieldsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20261018230000_createversionfieldsSql,
+		"migrations/20261018230000_CreateVersionFields.sql",
+	)
+}
+
+func migrations20261018230000_createversionfieldsSql() (*asset, error) {
+	bytes, err := migrations20261018230000_createversionfieldsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20261018230000_CreateVersionFields.sql", size: 670, mode: os.FileMode(420), modTime: time.Unix(1792310400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -515,6 +809,20 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/20160729184159_CreateCooldownAfterInviteField.sql": migrations20160729184159_createcooldownafterinvitefieldSql,
 	"migrations/20160819145352_CreateHookTriggerFieldsMetadata.sql": migrations20160819145352_createhooktriggerfieldsmetadataSql,
 	"migrations/20180517112014_ChangeIDSequenceType.sql": migrations20180517112014_changeidsequencetypeSql,
+	"migrations/20261018100000_CreateHookDeadLettersTable.sql": migrations20261018100000_createhookdeadletterstableSql,
+	"migrations/20261018110000_CreateHookSecretField.sql": migrations20261018110000_createhooksecretfieldSql,
+	"migrations/20261018120000_CreateHookFilterField.sql": migrations20261018120000_createhookfilterfieldSql,
+	"migrations/20261018130000_CreateHookBatchFields.sql": migrations20261018130000_createhookbatchfieldsSql,
+	"migrations/20261018140000_CreateAuditEventsTable.sql": migrations20261018140000_createauditeventstableSql,
+	"migrations/20261018150000_CreateGameTopClansDimensionsField.sql": migrations20261018150000_creategametopclansdimensionsfieldSql,
+	"migrations/20261018160000_CreateMembershipBanFields.sql": migrations20261018160000_createmembershipbanfieldsSql,
+	"migrations/20261018170000_CreateClanCreatedAtIndex.sql": migrations20261018170000_createclancreatedatindexSql,
+	"migrations/20261018180000_CreateHookPreFields.sql": migrations20261018180000_createhookprefieldsSql,
+	"migrations/20261018190000_CreateOutboxTable.sql": migrations20261018190000_createoutboxtableSql,
+	"migrations/20261018200000_CreateGameClanSearchMetadataKeysField.sql": migrations20261018200000_creategameclansearchmetadatakeysfieldSql,
+	"migrations/20261018210000_CreateAPIKeysTable.sql": migrations20261018210000_createapikeystableSql,
+	"migrations/20261018220000_CreatePlayerTokenConfigsTable.sql": migrations20261018220000_createplayertokenconfigstableSql,
+	"migrations/20261018230000_CreateVersionFields.sql": migrations20261018230000_createversionfieldsSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -576,6 +884,20 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"20160729184159_CreateCooldownAfterInviteField.sql": &bintree{migrations20160729184159_createcooldownafterinvitefieldSql, map[string]*bintree{}},
 		"20160819145352_CreateHookTriggerFieldsMetadata.sql": &bintree{migrations20160819145352_createhooktriggerfieldsmetadataSql, map[string]*bintree{}},
 		"20180517112014_ChangeIDSequenceType.sql": &bintree{migrations20180517112014_changeidsequencetypeSql, map[string]*bintree{}},
+		"20261018100000_CreateHookDeadLettersTable.sql": &bintree{migrations20261018100000_createhookdeadletterstableSql, map[string]*bintree{}},
+		"20261018110000_CreateHookSecretField.sql": &bintree{migrations20261018110000_createhooksecretfieldSql, map[string]*bintree{}},
+		"20261018120000_CreateHookFilterField.sql": &bintree{migrations20261018120000_createhookfilterfieldSql, map[string]*bintree{}},
+		"20261018130000_CreateHookBatchFields.sql": &bintree{migrations20261018130000_createhookbatchfieldsSql, map[string]*bintree{}},
+		"20261018140000_CreateAuditEventsTable.sql": &bintree{migrations20261018140000_createauditeventstableSql, map[string]*bintree{}},
+		"20261018150000_CreateGameTopClansDimensionsField.sql": &bintree{migrations20261018150000_creategametopclansdimensionsfieldSql, map[string]*bintree{}},
+		"20261018160000_CreateMembershipBanFields.sql": &bintree{migrations20261018160000_createmembershipbanfieldsSql, map[string]*bintree{}},
+		"20261018170000_CreateClanCreatedAtIndex.sql": &bintree{migrations20261018170000_createclancreatedatindexSql, map[string]*bintree{}},
+		"20261018180000_CreateHookPreFields.sql": &bintree{migrations20261018180000_createhookprefieldsSql, map[string]*bintree{}},
+		"20261018190000_CreateOutboxTable.sql": &bintree{migrations20261018190000_createoutboxtableSql, map[string]*bintree{}},
+		"20261018200000_CreateGameClanSearchMetadataKeysField.sql": &bintree{migrations20261018200000_creategameclansearchmetadatakeysfieldSql, map[string]*bintree{}},
+		"20261018210000_CreateAPIKeysTable.sql": &bintree{migrations20261018210000_createapikeystableSql, map[string]*bintree{}},
+		"20261018220000_CreatePlayerTokenConfigsTable.sql": &bintree{migrations20261018220000_createplayertokenconfigstableSql, map[string]*bintree{}},
+		"20261018230000_CreateVersionFields.sql": &bintree{migrations20261018230000_createversionfieldsSql, map[string]*bintree{}},
 	}},
 }}
 
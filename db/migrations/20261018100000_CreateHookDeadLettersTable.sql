@@ -0,0 +1,30 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE hook_dead_letters (
+    id bigserial PRIMARY KEY,
+    game_id varchar(36) NOT NULL REFERENCES games (public_id),
+    public_id varchar(36) NOT NULL,
+    hook_public_id varchar(36) NOT NULL,
+    event_type integer NOT NULL,
+    url text NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}'::jsonb,
+    last_error text NOT NULL DEFAULT '',
+    attempts integer NOT NULL DEFAULT 0,
+    created_at bigint NOT NULL,
+    updated_at bigint NULL,
+
+    CONSTRAINT hookdeadletterid_publicid UNIQUE(game_id, public_id)
+);
+
+CREATE INDEX hook_dead_letters_game_created_at ON hook_dead_letters (game_id, created_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE hook_dead_letters;
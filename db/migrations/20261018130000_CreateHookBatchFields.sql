@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE hooks ADD COLUMN batch_size integer NOT NULL DEFAULT 0;
+ALTER TABLE hooks ADD COLUMN batch_interval integer NOT NULL DEFAULT 0;
+ALTER TABLE hook_dead_letters ADD COLUMN batched boolean NOT NULL DEFAULT false;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE hooks DROP COLUMN batch_size;
+ALTER TABLE hooks DROP COLUMN batch_interval;
+ALTER TABLE hook_dead_letters DROP COLUMN batched;
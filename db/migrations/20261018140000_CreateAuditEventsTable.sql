@@ -0,0 +1,32 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE audit_events (
+    id bigserial PRIMARY KEY,
+    game_id varchar(36) NOT NULL REFERENCES games (public_id),
+    public_id varchar(36) NOT NULL,
+    action varchar(50) NOT NULL,
+    actor_public_id varchar(255) NOT NULL DEFAULT '',
+    clan_public_id varchar(255) NOT NULL DEFAULT '',
+    player_public_id varchar(255) NOT NULL DEFAULT '',
+    before jsonb NOT NULL DEFAULT '{}'::jsonb,
+    after jsonb NOT NULL DEFAULT '{}'::jsonb,
+    request_id varchar(255) NOT NULL DEFAULT '',
+    created_at bigint NOT NULL,
+
+    CONSTRAINT auditeventid_publicid UNIQUE(game_id, public_id)
+);
+
+CREATE INDEX audit_events_clan_created_at ON audit_events (game_id, clan_public_id, created_at);
+CREATE INDEX audit_events_player_created_at ON audit_events (game_id, player_public_id, created_at);
+CREATE INDEX audit_events_actor_created_at ON audit_events (game_id, actor_public_id, created_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE audit_events;
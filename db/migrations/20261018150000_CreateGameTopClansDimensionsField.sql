@@ -0,0 +1,14 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE games ADD COLUMN top_clans_metadata_dimensions varchar(2000) NOT NULL DEFAULT '';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE games DROP COLUMN top_clans_metadata_dimensions;
@@ -0,0 +1,16 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE memberships ADD COLUMN banned_at bigint NOT NULL DEFAULT 0;
+ALTER TABLE memberships ADD COLUMN banned_by integer NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE memberships DROP COLUMN banned_at;
+ALTER TABLE memberships DROP COLUMN banned_by;
@@ -0,0 +1,16 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE hooks ADD COLUMN kind varchar(10) NOT NULL DEFAULT 'post';
+ALTER TABLE hooks ADD COLUMN fail_open boolean NOT NULL DEFAULT false;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE hooks DROP COLUMN kind;
+ALTER TABLE hooks DROP COLUMN fail_open;
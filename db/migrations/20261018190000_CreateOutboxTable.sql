@@ -0,0 +1,23 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE outbox (
+    id bigserial PRIMARY KEY,
+    queue varchar(255) NOT NULL,
+    args jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_at bigint NOT NULL,
+    published_at bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX outbox_pending ON outbox (id) WHERE published_at = 0;
+CREATE INDEX outbox_published_at ON outbox (published_at) WHERE published_at > 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE outbox;
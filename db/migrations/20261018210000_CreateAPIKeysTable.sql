@@ -0,0 +1,31 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE api_keys (
+    id bigserial PRIMARY KEY,
+    game_id varchar(36) NOT NULL REFERENCES games (public_id),
+    public_id varchar(36) NOT NULL,
+    name varchar(255) NOT NULL DEFAULT '',
+    scope varchar(32) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    key_prefix varchar(16) NOT NULL,
+    created_at bigint NOT NULL,
+    updated_at bigint NULL,
+    rotated_at bigint NOT NULL DEFAULT 0,
+    revoked_at bigint NOT NULL DEFAULT 0,
+
+    CONSTRAINT apikeyid_publicid UNIQUE(game_id, public_id),
+    CONSTRAINT apikey_key_hash UNIQUE(key_hash)
+);
+
+CREATE INDEX api_keys_game_created_at ON api_keys (game_id, created_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE api_keys;
@@ -0,0 +1,26 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE player_token_configs (
+    id bigserial PRIMARY KEY,
+    game_id varchar(36) NOT NULL REFERENCES games (public_id),
+    algorithm varchar(16) NOT NULL,
+    key text NOT NULL,
+    issuer varchar(255) NOT NULL DEFAULT '',
+    audience varchar(255) NOT NULL DEFAULT '',
+    allowed_routes text NOT NULL DEFAULT '',
+    created_at bigint NOT NULL,
+    updated_at bigint NULL,
+
+    CONSTRAINT playertokenconfig_game_id UNIQUE(game_id)
+);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE player_token_configs;
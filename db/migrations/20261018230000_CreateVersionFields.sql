@@ -0,0 +1,18 @@
+-- khan
+-- https://github.com/topfreegames/khan
+--
+-- Licensed under the MIT license:
+-- http://www.opensource.org/licenses/mit-license
+-- Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE games ADD COLUMN version bigint NOT NULL DEFAULT 1;
+ALTER TABLE players ADD COLUMN version bigint NOT NULL DEFAULT 1;
+ALTER TABLE clans ADD COLUMN version bigint NOT NULL DEFAULT 1;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE clans DROP COLUMN version;
+ALTER TABLE players DROP COLUMN version;
+ALTER TABLE games DROP COLUMN version;
@@ -4,12 +4,16 @@ import "context"
 
 // KhanInterface defines the interface for the khan client
 type KhanInterface interface {
+	AdminDisbandClan(context.Context, string) (*Result, error)
 	ApplyForMembership(context.Context, *ApplicationPayload) (*ClanApplyResult, error)
+	AutocompleteClans(context.Context, string, int) (*AutocompleteClansResult, error)
 	ApproveDenyMembershipApplication(context.Context, *ApplicationApprovalPayload) (*Result, error)
 	ApproveDenyMembershipInvitation(context.Context, *InvitationApprovalPayload) (*Result, error)
+	BanMember(context.Context, *BanPayload) (*Result, error)
 	CreateClan(context.Context, *ClanPayload) (string, error)
 	CreatePlayer(context.Context, string, string, interface{}) (string, error)
 	DeleteMembership(context.Context, *DeleteMembershipPayload) (*Result, error)
+	DisbandClan(context.Context, string, string) (*Result, error)
 	InviteForMembership(context.Context, *InvitationPayload) (*Result, error)
 	LeaveClan(context.Context, string) (*LeaveClanResult, error)
 	PromoteDemote(context.Context, *PromoteDemotePayload) (*Result, error)
@@ -17,10 +21,17 @@ type KhanInterface interface {
 	RetrieveClansSummary(context.Context, []string) ([]*ClanSummary, error)
 	RetrieveClanMembers(context.Context, string) (*ClanMembers, error)
 	RetrieveClanSummary(context.Context, string) (*ClanSummary, error)
+	RetrieveGame(context.Context) (*Game, error)
+	RetrieveGames(context.Context) ([]*Game, error)
+	RetrieveHooks(context.Context) ([]*Hook, error)
 	RetrievePlayer(context.Context, string) (*Player, error)
+	TopClans(context.Context, string, int) (*TopClansResult, error)
 	TransferOwnership(context.Context, string, string) (*TransferOwnershipResult, error)
+	UnbanMember(context.Context, *BanPayload) (*Result, error)
 	UpdateClan(context.Context, *ClanPayload) (*Result, error)
+	UpdateClanIfVersion(context.Context, *ClanPayload, int64) (*Result, error)
 	UpdatePlayer(context.Context, string, string, interface{}) (*Result, error)
+	UpdatePlayerIfVersion(context.Context, string, string, interface{}, int64) (*Result, error)
 	SearchClans(context.Context, string) (*SearchClansResult, error)
 	SearchClansWithOptions(context.Context, string, *SearchOptions) (*SearchClansResult, error)
 }
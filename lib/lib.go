@@ -8,6 +8,8 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -128,6 +130,15 @@ func NewKhanWithParams(params *KhanParams) KhanInterface {
 }
 
 func (k *Khan) sendTo(ctx context.Context, method, url string, payload interface{}) ([]byte, error) {
+	return k.sendToWithHeaders(ctx, method, url, payload, nil)
+}
+
+func (k *Khan) sendToWithHeaders(
+	ctx context.Context,
+	method, url string,
+	payload interface{},
+	headers map[string]string,
+) ([]byte, error) {
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
@@ -147,6 +158,9 @@ func (k *Khan) sendTo(ctx context.Context, method, url string, payload interface
 		}
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 	req.SetBasicAuth(k.user, k.pass)
 	if ctx == nil {
 		ctx = context.Background()
@@ -171,10 +185,27 @@ func (k *Khan) sendTo(ctx context.Context, method, url string, payload interface
 	return body, nil
 }
 
+func ifMatchHeaders(version int64) map[string]string {
+	return map[string]string{"If-Match": fmt.Sprintf(`"%d"`, version)}
+}
+
 func (k *Khan) buildURL(pathname string) string {
 	return fmt.Sprintf("%s/games/%s/%s", k.url, k.gameID, pathname)
 }
 
+func (k *Khan) buildRetrieveGameURL() string {
+	return fmt.Sprintf("%s/games/%s", k.url, k.gameID)
+}
+
+func (k *Khan) buildRetrieveGamesURL() string {
+	return fmt.Sprintf("%s/games", k.url)
+}
+
+func (k *Khan) buildRetrieveHooksURL() string {
+	pathname := "hooks"
+	return k.buildURL(pathname)
+}
+
 func (k *Khan) buildCreatePlayerURL() string {
 	pathname := "players"
 	return k.buildURL(pathname)
@@ -250,11 +281,26 @@ func (k *Khan) buildDeleteMembershipURL(clanID string) string {
 	return k.buildURL(pathname)
 }
 
+func (k *Khan) buildBanUnbanURL(clanID, action string) string {
+	pathname := fmt.Sprintf("clans/%s/memberships/%s", clanID, action)
+	return k.buildURL(pathname)
+}
+
 func (k *Khan) buildLeaveClanURL(clanID string) string {
 	pathname := fmt.Sprintf("clans/%s/leave", clanID)
 	return k.buildURL(pathname)
 }
 
+func (k *Khan) buildDisbandClanURL(clanID, requestorPublicID string) string {
+	pathname := fmt.Sprintf("clans/%s?requestorPublicID=%s", clanID, url.QueryEscape(requestorPublicID))
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildAdminDisbandClanURL(clanID string) string {
+	pathname := fmt.Sprintf("clans/%s/admin-disband", clanID)
+	return k.buildURL(pathname)
+}
+
 func (k *Khan) buildTransferOwnershipURL(clanID string) string {
 	pathname := fmt.Sprintf("clans/%s/transfer-ownership", clanID)
 	return k.buildURL(pathname)
@@ -265,18 +311,128 @@ func (k *Khan) buildSearchClansURL(clanName string) string {
 	return k.buildURL(pathname)
 }
 
-func (k *Khan) buildSearchClansWithOptionsURL(clanName string, searchMethod SearchMethod, from int, limit *OptionalInt) string {
-	var pathname string
-	useRegexSearch := searchMethod == SearchMethodRegex
-	if limit == nil {
-		pathname = fmt.Sprintf("clans/search?term=%s&useRegexSearch=%t&from=%d", clanName, useRegexSearch, from)
-	} else {
-		pathname = fmt.Sprintf("clans/search?term=%s&useRegexSearch=%t&from=%d&limit=%d", clanName, useRegexSearch, from, limit.Value)
+func (k *Khan) buildAutocompleteClansURL(prefix string, limit int) string {
+	pathname := fmt.Sprintf("clans/autocomplete?prefix=%s&limit=%d", url.QueryEscape(prefix), limit)
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildTopClansURL(dimension string, limit int) string {
+	pathname := fmt.Sprintf("clans/top?dimension=%s&limit=%d", url.QueryEscape(dimension), limit)
+	return k.buildURL(pathname)
+}
+
+func (k *Khan) buildSearchClansWithOptionsURL(clanName string, options *SearchOptions) string {
+	useRegexSearch := options.Method == SearchMethodRegex
+	pathname := fmt.Sprintf("clans/search?term=%s&useRegexSearch=%t&from=%d", clanName, useRegexSearch, options.From)
+	if options.Limit != nil {
+		pathname = fmt.Sprintf("%s&limit=%d", pathname, options.Limit.Value)
+	}
+	if options.Cursor != "" {
+		pathname = fmt.Sprintf("%s&cursor=%s", pathname, url.QueryEscape(options.Cursor))
+	}
+	if options.AllowApplication != nil {
+		pathname = fmt.Sprintf("%s&allowApplication=%t", pathname, *options.AllowApplication)
+	}
+	if options.AutoJoin != nil {
+		pathname = fmt.Sprintf("%s&autoJoin=%t", pathname, *options.AutoJoin)
+	}
+	if options.MinMembershipCount != nil {
+		pathname = fmt.Sprintf("%s&minMembershipCount=%d", pathname, options.MinMembershipCount.Value)
+	}
+	if options.MaxMembershipCount != nil {
+		pathname = fmt.Sprintf("%s&maxMembershipCount=%d", pathname, options.MaxMembershipCount.Value)
+	}
+	if options.HasFreeSlots != nil {
+		pathname = fmt.Sprintf("%s&hasFreeSlots=%t", pathname, *options.HasFreeSlots)
+	}
+	for _, key := range sortedKeys(options.Metadata) {
+		pathname = fmt.Sprintf(
+			"%s&metadata.%s=%s", pathname, url.QueryEscape(key), url.QueryEscape(options.Metadata[key]),
+		)
+	}
+	for _, key := range sortedFloatKeys(options.MinMetadata) {
+		pathname = fmt.Sprintf("%s&minMetadata.%s=%g", pathname, url.QueryEscape(key), options.MinMetadata[key])
+	}
+	for _, key := range sortedFloatKeys(options.MaxMetadata) {
+		pathname = fmt.Sprintf("%s&maxMetadata.%s=%g", pathname, url.QueryEscape(key), options.MaxMetadata[key])
+	}
+	if options.Sort != "" {
+		pathname = fmt.Sprintf("%s&sort=%s", pathname, url.QueryEscape(options.Sort))
+	}
+	if options.Order != "" {
+		pathname = fmt.Sprintf("%s&order=%s", pathname, options.Order)
 	}
 
 	return k.buildURL(pathname)
 }
 
+// sortedKeys and sortedFloatKeys keep the search URLs stable
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFloatKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RetrieveGame calls the retrieve game route from khan
+func (k *Khan) RetrieveGame(ctx context.Context) (*Game, error) {
+	route := k.buildRetrieveGameURL()
+	body, err := k.sendTo(ctx, "GET", route, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var game Game
+	err = json.Unmarshal(body, &game)
+	return &game, err
+}
+
+// RetrieveGames calls the list games route from khan
+func (k *Khan) RetrieveGames(ctx context.Context) ([]*Game, error) {
+	route := k.buildRetrieveGamesURL()
+	body, err := k.sendTo(ctx, "GET", route, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result GamesResult
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Games, nil
+}
+
+// RetrieveHooks calls the list hooks route from khan
+func (k *Khan) RetrieveHooks(ctx context.Context) ([]*Hook, error) {
+	route := k.buildRetrieveHooksURL()
+	body, err := k.sendTo(ctx, "GET", route, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result HooksResult
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Hooks, nil
+}
+
 // CreatePlayer calls Khan to create a new player
 func (k *Khan) CreatePlayer(ctx context.Context, publicID, name string, metadata interface{}) (string, error) {
 	route := k.buildCreatePlayerURL()
@@ -315,6 +471,26 @@ func (k *Khan) UpdatePlayer(
 	return &result, err
 }
 
+// UpdatePlayerIfVersion calls khan to update the player only if it is still at the given version.
+// If the player was updated since, the returned error satisfies IsVersionConflict.
+func (k *Khan) UpdatePlayerIfVersion(
+	ctx context.Context,
+	publicID, name string,
+	metadata interface{},
+	version int64,
+) (*Result, error) {
+	route := k.buildUpdatePlayerURL(publicID)
+	playerPayload := &Player{Name: name, Metadata: metadata}
+	body, err := k.sendToWithHeaders(ctx, "PUT", route, playerPayload, ifMatchHeaders(version))
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
 // RetrievePlayer calls the retrieve player route from khan
 func (k *Khan) RetrievePlayer(ctx context.Context, publicID string) (*Player, error) {
 	route := k.buildRetrievePlayerURL(publicID)
@@ -356,6 +532,20 @@ func (k *Khan) UpdateClan(ctx context.Context, clan *ClanPayload) (*Result, erro
 	return &result, err
 }
 
+// UpdateClanIfVersion calls the update clan route from khan only if the clan is still at the given
+// version. If the clan was updated since, the returned error satisfies IsVersionConflict.
+func (k *Khan) UpdateClanIfVersion(ctx context.Context, clan *ClanPayload, version int64) (*Result, error) {
+	route := k.buildUpdateClanURL(clan.PublicID)
+	body, err := k.sendToWithHeaders(ctx, "PUT", route, clan, ifMatchHeaders(version))
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
 // RetrieveClanMembers calls the route to retrieve clan members from khan
 func (k *Khan) RetrieveClanMembers(ctx context.Context, clanID string) (*ClanMembers, error) {
 	route := k.buildRetrieveClanMembersURL(clanID)
@@ -479,6 +669,24 @@ func (k *Khan) DeleteMembership(
 	return k.defaultPostRequest(ctx, route, payload)
 }
 
+// BanMember bans player from clan, preventing new applications and invitations
+func (k *Khan) BanMember(
+	ctx context.Context,
+	payload *BanPayload,
+) (*Result, error) {
+	route := k.buildBanUnbanURL(payload.ClanID, "ban")
+	return k.defaultPostRequest(ctx, route, payload)
+}
+
+// UnbanMember allows a banned player to apply or be invited to clan again
+func (k *Khan) UnbanMember(
+	ctx context.Context,
+	payload *BanPayload,
+) (*Result, error) {
+	route := k.buildBanUnbanURL(payload.ClanID, "unban")
+	return k.defaultPostRequest(ctx, route, payload)
+}
+
 // LeaveClan allows member to leave clan
 func (k *Khan) LeaveClan(
 	ctx context.Context,
@@ -496,6 +704,36 @@ func (k *Khan) LeaveClan(
 	return &result, err
 }
 
+// DisbandClan disbands the clan, ending all of its memberships
+// The requestorPublicID must be the clan owner's public id.
+func (k *Khan) DisbandClan(
+	ctx context.Context,
+	clanID, requestorPublicID string,
+) (*Result, error) {
+	route := k.buildDisbandClanURL(clanID, requestorPublicID)
+	body, err := k.sendTo(ctx, "DELETE", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// AdminDisbandClan disbands the clan on behalf of the game, regardless of its owner
+func (k *Khan) AdminDisbandClan(ctx context.Context, clanID string) (*Result, error) {
+	route := k.buildAdminDisbandClanURL(clanID)
+	body, err := k.sendTo(ctx, "POST", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Result
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
 // TransferOwnership transfers clan ownership to another member
 func (k *Khan) TransferOwnership(
 	ctx context.Context,
@@ -544,8 +782,9 @@ func (k *Khan) SearchClans(ctx context.Context, clanName string) (*SearchClansRe
 }
 
 // SearchClansWithOptions returns clan summaries for all clans that contain the string "clanName" using the given search method.
+// To iterate through the pages, set options.Cursor to the NextCursor of the previous result until it is empty.
 func (k *Khan) SearchClansWithOptions(ctx context.Context, clanName string, options *SearchOptions) (*SearchClansResult, error) {
-	route := k.buildSearchClansWithOptionsURL(clanName, options.Method, options.From, options.Limit)
+	route := k.buildSearchClansWithOptionsURL(clanName, options)
 	body, err := k.sendTo(ctx, "GET", route, nil)
 	if err != nil {
 		return nil, err
@@ -555,3 +794,31 @@ func (k *Khan) SearchClansWithOptions(ctx context.Context, clanName string, opti
 	err = json.Unmarshal(body, &result)
 	return &result, err
 }
+
+// AutocompleteClans returns up to limit clans whose names start with the prefix, ignoring
+// case and accents, biggest clans first
+func (k *Khan) AutocompleteClans(ctx context.Context, prefix string, limit int) (*AutocompleteClansResult, error) {
+	route := k.buildAutocompleteClansURL(prefix, limit)
+	body, err := k.sendTo(ctx, "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result AutocompleteClansResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
+
+// TopClans returns the best ranked clans in the given dimension, such as
+// "membershipCount", "createdAt" or "metadata.<key>"
+func (k *Khan) TopClans(ctx context.Context, dimension string, limit int) (*TopClansResult, error) {
+	route := k.buildTopClansURL(dimension, limit)
+	body, err := k.sendTo(ctx, "GET", route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result TopClansResult
+	err = json.Unmarshal(body, &result)
+	return &result, err
+}
@@ -1,6 +1,8 @@
 package lib_test
 
 import (
+	"net/http"
+
 	"github.com/jarcoal/httpmock"
 	"github.com/spf13/viper"
 	"github.com/topfreegames/khan/lib"
@@ -65,6 +67,34 @@ var _ = Describe("Lib", func() {
 		})
 	})
 
+	Describe("UpdatePlayerIfVersion", func() {
+		It("Should call khan API to update player with the If-Match header", func() {
+			publicID := "testid"
+			url := "http://khan/games/" + gameID + "/players/" + publicID
+			httpmock.RegisterResponder("PUT", url, func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("If-Match")).To(Equal(`"3"`))
+				return httpmock.NewStringResponse(200, `{ "success": true, "version": 4 }`), nil
+			})
+
+			result, err := k.UpdatePlayerIfVersion(nil, publicID, "testname", nil, 3)
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&lib.Result{Success: true, Version: 4}))
+		})
+
+		It("Should return a version conflict if the player changed", func() {
+			publicID := "testid"
+			url := "http://khan/games/" + gameID + "/players/" + publicID
+			httpmock.RegisterResponder("PUT", url,
+				httpmock.NewStringResponder(412, `{ "success": false, "reason": "Player testid is not at version 3" }`))
+
+			_, err := k.UpdatePlayerIfVersion(nil, publicID, "testname", nil, 3)
+
+			Expect(err).To(HaveOccurred())
+			Expect(lib.IsVersionConflict(err)).To(BeTrue())
+		})
+	})
+
 	Describe("RetrievePlayer", func() {
 		It("Should call khan API to retrieve player", func() {
 			publicID := "testid"
@@ -172,6 +202,27 @@ var _ = Describe("Lib", func() {
 		})
 	})
 
+	Describe("UpdateClanIfVersion", func() {
+		It("Should call khan API to update clan with the If-Match header", func() {
+			publicID := "testid"
+			url := "http://khan/games/" + gameID + "/clans/" + publicID
+			httpmock.RegisterResponder("PUT", url, func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("If-Match")).To(Equal(`"2"`))
+				return httpmock.NewStringResponse(200, `{ "success": true, "version": 3 }`), nil
+			})
+
+			clanPayload := &lib.ClanPayload{
+				PublicID:      publicID,
+				Name:          "testname",
+				OwnerPublicID: "ownerID",
+			}
+			result, err := k.UpdateClanIfVersion(nil, clanPayload, 2)
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&lib.Result{Success: true, Version: 3}))
+		})
+	})
+
 	Describe("RetrieveClan", func() {
 		It("Should call khan API to retrieve clan", func() {
 			publicID := "testid"
@@ -313,6 +364,252 @@ var _ = Describe("Lib", func() {
 		})
 	})
 
+	Describe("AutocompleteClans", func() {
+		It("Should call khan API to autocomplete clan names", func() {
+			url := "http://khan/games/" + gameID + "/clans/autocomplete?prefix=cl%C3%A3+dos&limit=5"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"clans": [
+						{"publicID": "testid", "name": "Clã dos Bravos", "membershipCount": 3}
+					]
+				}`))
+
+			result, err := k.AutocompleteClans(nil, "clã dos", 5)
+
+			Expect(err).To(BeNil())
+			Expect(result.Success).To(BeTrue())
+			Expect(result.Clans).To(HaveLen(1))
+			Expect(result.Clans[0].PublicID).To(Equal("testid"))
+			Expect(result.Clans[0].Name).To(Equal("Clã dos Bravos"))
+			Expect(result.Clans[0].MembershipCount).To(Equal(3))
+		})
+	})
+
+	Describe("TopClans", func() {
+		It("Should call khan API to retrieve top clans", func() {
+			url := "http://khan/games/" + gameID + "/clans/top?dimension=metadata.trophies&limit=2"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"dimension": "metadata.trophies",
+					"clans": [
+						{
+							"publicID": "testid",
+							"name": "testname",
+							"metadata": {"trophies": 30},
+							"allowApplication": true,
+							"autoJoin": false,
+							"membershipCount": 3,
+							"score": 30
+						},
+						{
+							"publicID": "testid2",
+							"name": "testname2",
+							"metadata": {"trophies": 10},
+							"allowApplication": false,
+							"autoJoin": false,
+							"membershipCount": 1,
+							"score": 10
+						}
+					]
+				}`))
+
+			result, err := k.TopClans(nil, "metadata.trophies", 2)
+
+			Expect(err).To(BeNil())
+			Expect(result.Success).To(BeTrue())
+			Expect(result.Dimension).To(Equal("metadata.trophies"))
+			Expect(result.Clans).To(HaveLen(2))
+			Expect(result.Clans[0].PublicID).To(Equal("testid"))
+			Expect(result.Clans[0].MembershipCount).To(Equal(3))
+			Expect(result.Clans[0].Score).To(Equal(float64(30)))
+			Expect(result.Clans[1].PublicID).To(Equal("testid2"))
+			Expect(result.Clans[1].Score).To(Equal(float64(10)))
+		})
+	})
+
+	Describe("RetrieveGame", func() {
+		It("Should call khan API to retrieve game", func() {
+			url := "http://khan/games/" + gameID
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"publicID": "testgame",
+					"name": "Test Game",
+					"membershipLevels": {"member": 1, "coleader": 2},
+					"metadata": {},
+					"maxMembers": 50,
+					"maxClansPerPlayer": 1,
+					"clanHookFieldsWhitelist": "league"
+				}`))
+
+			game, err := k.RetrieveGame(nil)
+
+			Expect(err).To(BeNil())
+			Expect(game.PublicID).To(Equal(gameID))
+			Expect(game.Name).To(Equal("Test Game"))
+			Expect(game.MembershipLevels).To(HaveLen(2))
+			Expect(game.MaxMembers).To(Equal(50))
+			Expect(game.MaxClansPerPlayer).To(Equal(1))
+			Expect(game.ClanHookFieldsWhitelist).To(Equal("league"))
+		})
+	})
+
+	Describe("RetrieveGames", func() {
+		It("Should call khan API to list games", func() {
+			url := "http://khan/games"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"games": [
+						{ "publicID": "testgame", "name": "Test Game" },
+						{ "publicID": "othergame", "name": "Other Game" }
+					]
+				}`))
+
+			games, err := k.RetrieveGames(nil)
+
+			Expect(err).To(BeNil())
+			Expect(games).To(HaveLen(2))
+			Expect(games[0].PublicID).To(Equal("testgame"))
+			Expect(games[1].PublicID).To(Equal("othergame"))
+		})
+	})
+
+	Describe("RetrieveHooks", func() {
+		It("Should call khan API to list hooks", func() {
+			url := "http://khan/games/" + gameID + "/hooks"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"hooks": [
+						{ "gameID": "testgame", "publicID": "hook1", "type": 3, "hookURL": "http://test/clan-created" }
+					]
+				}`))
+
+			hooks, err := k.RetrieveHooks(nil)
+
+			Expect(err).To(BeNil())
+			Expect(hooks).To(HaveLen(1))
+			Expect(hooks[0].PublicID).To(Equal("hook1"))
+			Expect(hooks[0].Type).To(Equal(3))
+			Expect(hooks[0].HookURL).To(Equal("http://test/clan-created"))
+		})
+	})
+
+	Describe("BanMember", func() {
+		It("Should call khan API to ban member", func() {
+			url := "http://khan/games/" + gameID + "/clans/clanid/memberships/ban"
+			httpmock.RegisterResponder("POST", url,
+				httpmock.NewStringResponder(200, `{ "success": true }`))
+
+			result, err := k.BanMember(nil, &lib.BanPayload{
+				ClanID:            "clanid",
+				PlayerPublicID:    "playerid",
+				RequestorPublicID: "ownerid",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&lib.Result{Success: true}))
+		})
+	})
+
+	Describe("UnbanMember", func() {
+		It("Should call khan API to unban member", func() {
+			url := "http://khan/games/" + gameID + "/clans/clanid/memberships/unban"
+			httpmock.RegisterResponder("POST", url,
+				httpmock.NewStringResponder(200, `{ "success": true }`))
+
+			result, err := k.UnbanMember(nil, &lib.BanPayload{
+				ClanID:            "clanid",
+				PlayerPublicID:    "playerid",
+				RequestorPublicID: "ownerid",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&lib.Result{Success: true}))
+		})
+	})
+
+	Describe("DisbandClan", func() {
+		It("Should call khan API to disband clan", func() {
+			url := "http://khan/games/" + gameID + "/clans/clanid?requestorPublicID=ownerid"
+			httpmock.RegisterResponder("DELETE", url,
+				httpmock.NewStringResponder(200, `{ "success": true }`))
+
+			result, err := k.DisbandClan(nil, "clanid", "ownerid")
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&lib.Result{Success: true}))
+		})
+	})
+
+	Describe("AdminDisbandClan", func() {
+		It("Should call khan API to disband clan on behalf of the game", func() {
+			url := "http://khan/games/" + gameID + "/clans/clanid/admin-disband"
+			httpmock.RegisterResponder("POST", url,
+				httpmock.NewStringResponder(200, `{ "success": true }`))
+
+			result, err := k.AdminDisbandClan(nil, "clanid")
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&lib.Result{Success: true}))
+		})
+	})
+
+	Describe("SearchClansWithOptions", func() {
+		It("Should call khan API to get the next page of a search", func() {
+			url := "http://khan/games/" + gameID + "/clans/search?term=clan&useRegexSearch=false&from=0&limit=1&cursor=next-page"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"clans": [{"publicID": "clan1", "name": "clan 1"}],
+					"nextCursor": "last-page"
+				}`))
+
+			result, err := k.SearchClansWithOptions(nil, "clan", &lib.SearchOptions{
+				Method: lib.SearchMethodText,
+				Limit:  &lib.OptionalInt{Value: 1},
+				Cursor: "next-page",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Clans).To(HaveLen(1))
+			Expect(result.Clans[0].PublicID).To(Equal("clan1"))
+			Expect(result.NextCursor).To(Equal("last-page"))
+		})
+
+		It("Should call khan API with the search filters and sort", func() {
+			url := "http://khan/games/" + gameID + "/clans/search?term=clan&useRegexSearch=false&from=0" +
+				"&allowApplication=true&minMembershipCount=2&hasFreeSlots=true" +
+				"&metadata.language=en&metadata.region=eu&minMetadata.trophies=100&sort=metadata.trophies&order=asc"
+			httpmock.RegisterResponder("GET", url,
+				httpmock.NewStringResponder(200, `{
+					"success": true,
+					"clans": [{"publicID": "clan1", "name": "clan 1"}],
+					"nextCursor": ""
+				}`))
+
+			allowApplication := true
+			hasFreeSlots := true
+			result, err := k.SearchClansWithOptions(nil, "clan", &lib.SearchOptions{
+				Method:             lib.SearchMethodText,
+				AllowApplication:   &allowApplication,
+				MinMembershipCount: &lib.OptionalInt{Value: 2},
+				HasFreeSlots:       &hasFreeSlots,
+				Metadata:           map[string]string{"region": "eu", "language": "en"},
+				MinMetadata:        map[string]float64{"trophies": 100},
+				Sort:               "metadata.trophies",
+				Order:              "asc",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Clans).To(HaveLen(1))
+			Expect(result.Clans[0].PublicID).To(Equal("clan1"))
+		})
+	})
+
 	AfterSuite(func() {
 		defer httpmock.DeactivateAndReset()
 	})
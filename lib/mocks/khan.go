@@ -34,6 +34,21 @@ func (m *MockKhanInterface) EXPECT() *MockKhanInterfaceMockRecorder {
 	return m.recorder
 }
 
+// AdminDisbandClan mocks base method
+func (m *MockKhanInterface) AdminDisbandClan(arg0 context.Context, arg1 string) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDisbandClan", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDisbandClan indicates an expected call of AdminDisbandClan
+func (mr *MockKhanInterfaceMockRecorder) AdminDisbandClan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDisbandClan", reflect.TypeOf((*MockKhanInterface)(nil).AdminDisbandClan), arg0, arg1)
+}
+
 // ApplyForMembership mocks base method
 func (m *MockKhanInterface) ApplyForMembership(arg0 context.Context, arg1 *lib.ApplicationPayload) (*lib.ClanApplyResult, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +94,36 @@ func (mr *MockKhanInterfaceMockRecorder) ApproveDenyMembershipInvitation(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDenyMembershipInvitation", reflect.TypeOf((*MockKhanInterface)(nil).ApproveDenyMembershipInvitation), arg0, arg1)
 }
 
+// AutocompleteClans mocks base method
+func (m *MockKhanInterface) AutocompleteClans(arg0 context.Context, arg1 string, arg2 int) (*lib.AutocompleteClansResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutocompleteClans", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*lib.AutocompleteClansResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AutocompleteClans indicates an expected call of AutocompleteClans
+func (mr *MockKhanInterfaceMockRecorder) AutocompleteClans(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutocompleteClans", reflect.TypeOf((*MockKhanInterface)(nil).AutocompleteClans), arg0, arg1, arg2)
+}
+
+// BanMember mocks base method
+func (m *MockKhanInterface) BanMember(arg0 context.Context, arg1 *lib.BanPayload) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanMember", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BanMember indicates an expected call of BanMember
+func (mr *MockKhanInterfaceMockRecorder) BanMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanMember", reflect.TypeOf((*MockKhanInterface)(nil).BanMember), arg0, arg1)
+}
+
 // CreateClan mocks base method
 func (m *MockKhanInterface) CreateClan(arg0 context.Context, arg1 *lib.ClanPayload) (string, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +169,21 @@ func (mr *MockKhanInterfaceMockRecorder) DeleteMembership(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMembership", reflect.TypeOf((*MockKhanInterface)(nil).DeleteMembership), arg0, arg1)
 }
 
+// DisbandClan mocks base method
+func (m *MockKhanInterface) DisbandClan(arg0 context.Context, arg1, arg2 string) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisbandClan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisbandClan indicates an expected call of DisbandClan
+func (mr *MockKhanInterfaceMockRecorder) DisbandClan(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisbandClan", reflect.TypeOf((*MockKhanInterface)(nil).DisbandClan), arg0, arg1, arg2)
+}
+
 // InviteForMembership mocks base method
 func (m *MockKhanInterface) InviteForMembership(arg0 context.Context, arg1 *lib.InvitationPayload) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -229,6 +289,51 @@ func (mr *MockKhanInterfaceMockRecorder) RetrieveClansSummary(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClansSummary", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveClansSummary), arg0, arg1)
 }
 
+// RetrieveGame mocks base method
+func (m *MockKhanInterface) RetrieveGame(arg0 context.Context) (*lib.Game, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveGame", arg0)
+	ret0, _ := ret[0].(*lib.Game)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveGame indicates an expected call of RetrieveGame
+func (mr *MockKhanInterfaceMockRecorder) RetrieveGame(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveGame", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveGame), arg0)
+}
+
+// RetrieveGames mocks base method
+func (m *MockKhanInterface) RetrieveGames(arg0 context.Context) ([]*lib.Game, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveGames", arg0)
+	ret0, _ := ret[0].([]*lib.Game)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveGames indicates an expected call of RetrieveGames
+func (mr *MockKhanInterfaceMockRecorder) RetrieveGames(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveGames", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveGames), arg0)
+}
+
+// RetrieveHooks mocks base method
+func (m *MockKhanInterface) RetrieveHooks(arg0 context.Context) ([]*lib.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveHooks", arg0)
+	ret0, _ := ret[0].([]*lib.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveHooks indicates an expected call of RetrieveHooks
+func (mr *MockKhanInterfaceMockRecorder) RetrieveHooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveHooks", reflect.TypeOf((*MockKhanInterface)(nil).RetrieveHooks), arg0)
+}
+
 // RetrievePlayer mocks base method
 func (m *MockKhanInterface) RetrievePlayer(arg0 context.Context, arg1 string) (*lib.Player, error) {
 	m.ctrl.T.Helper()
@@ -274,6 +379,21 @@ func (mr *MockKhanInterfaceMockRecorder) SearchClansWithOptions(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchClansWithOptions", reflect.TypeOf((*MockKhanInterface)(nil).SearchClansWithOptions), arg0, arg1, arg2)
 }
 
+// TopClans mocks base method
+func (m *MockKhanInterface) TopClans(arg0 context.Context, arg1 string, arg2 int) (*lib.TopClansResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopClans", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*lib.TopClansResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopClans indicates an expected call of TopClans
+func (mr *MockKhanInterfaceMockRecorder) TopClans(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopClans", reflect.TypeOf((*MockKhanInterface)(nil).TopClans), arg0, arg1, arg2)
+}
+
 // TransferOwnership mocks base method
 func (m *MockKhanInterface) TransferOwnership(arg0 context.Context, arg1, arg2 string) (*lib.TransferOwnershipResult, error) {
 	m.ctrl.T.Helper()
@@ -289,6 +409,21 @@ func (mr *MockKhanInterfaceMockRecorder) TransferOwnership(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOwnership", reflect.TypeOf((*MockKhanInterface)(nil).TransferOwnership), arg0, arg1, arg2)
 }
 
+// UnbanMember mocks base method
+func (m *MockKhanInterface) UnbanMember(arg0 context.Context, arg1 *lib.BanPayload) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbanMember", arg0, arg1)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnbanMember indicates an expected call of UnbanMember
+func (mr *MockKhanInterfaceMockRecorder) UnbanMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbanMember", reflect.TypeOf((*MockKhanInterface)(nil).UnbanMember), arg0, arg1)
+}
+
 // UpdateClan mocks base method
 func (m *MockKhanInterface) UpdateClan(arg0 context.Context, arg1 *lib.ClanPayload) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -304,6 +439,21 @@ func (mr *MockKhanInterfaceMockRecorder) UpdateClan(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClan", reflect.TypeOf((*MockKhanInterface)(nil).UpdateClan), arg0, arg1)
 }
 
+// UpdateClanIfVersion mocks base method
+func (m *MockKhanInterface) UpdateClanIfVersion(arg0 context.Context, arg1 *lib.ClanPayload, arg2 int64) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateClanIfVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateClanIfVersion indicates an expected call of UpdateClanIfVersion
+func (mr *MockKhanInterfaceMockRecorder) UpdateClanIfVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClanIfVersion", reflect.TypeOf((*MockKhanInterface)(nil).UpdateClanIfVersion), arg0, arg1, arg2)
+}
+
 // UpdatePlayer mocks base method
 func (m *MockKhanInterface) UpdatePlayer(arg0 context.Context, arg1, arg2 string, arg3 interface{}) (*lib.Result, error) {
 	m.ctrl.T.Helper()
@@ -318,3 +468,18 @@ func (mr *MockKhanInterfaceMockRecorder) UpdatePlayer(arg0, arg1, arg2, arg3 int
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlayer", reflect.TypeOf((*MockKhanInterface)(nil).UpdatePlayer), arg0, arg1, arg2, arg3)
 }
+
+// UpdatePlayerIfVersion mocks base method
+func (m *MockKhanInterface) UpdatePlayerIfVersion(arg0 context.Context, arg1, arg2 string, arg3 interface{}, arg4 int64) (*lib.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePlayerIfVersion", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*lib.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePlayerIfVersion indicates an expected call of UpdatePlayerIfVersion
+func (mr *MockKhanInterfaceMockRecorder) UpdatePlayerIfVersion(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlayerIfVersion", reflect.TypeOf((*MockKhanInterface)(nil).UpdatePlayerIfVersion), arg0, arg1, arg2, arg3, arg4)
+}
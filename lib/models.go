@@ -1,6 +1,9 @@
 package lib
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+)
 
 // RequestError contains code and body of a request that failed
 type RequestError struct {
@@ -24,6 +27,13 @@ func (r *RequestError) Status() int {
 	return r.statusCode
 }
 
+// IsVersionConflict returns whether the error is the result of an update made with a stale version,
+// in which case the resource should be retrieved again before retrying the update
+func IsVersionConflict(err error) bool {
+	reqErr, ok := err.(*RequestError)
+	return ok && reqErr.Status() == http.StatusPreconditionFailed
+}
+
 //ClanPayload maps the payload for the Create Clan route and Update Clan route
 type ClanPayload struct {
 	PublicID         string      `json:"publicID,omitempty"`
@@ -41,6 +51,7 @@ type Player struct {
 	Metadata    interface{}         `json:"metadata"`
 	Clans       *ClansRelationships `json:"clans,omitempty"`
 	Memberships []*PlayerMembership `json:"memberships,omitempty"`
+	Version     int64               `json:"version,omitempty"`
 }
 
 // ClansRelationships defines the struct returned inside player
@@ -107,6 +118,12 @@ type ClanSummary struct {
 	MembershipCount  int         `json:"membershipCount"`
 }
 
+// TopClan defines a clan summary and its score in a top clans ranking
+type TopClan struct {
+	ClanSummary
+	Score float64 `json:"score"`
+}
+
 // ClansSummary defines the clans summary
 type ClansSummary struct {
 	Clans []*ClanSummary `json:"clans"`
@@ -146,6 +163,63 @@ type Clan struct {
 	Owner            *ShortPlayerInfo  `json:"owner"`
 	Roster           []*ClanMembership `json:"roster"`
 	Memberships      *ClanMemberships  `json:"memberships"`
+	Version          int64             `json:"version"`
+}
+
+// Game is the structure returned by the retrieve game route
+type Game struct {
+	PublicID                      string                 `json:"publicID"`
+	Name                          string                 `json:"name"`
+	MembershipLevels              map[string]interface{} `json:"membershipLevels"`
+	Metadata                      interface{}            `json:"metadata"`
+	MinMembershipLevel            int                    `json:"minMembershipLevel"`
+	MaxMembershipLevel            int                    `json:"maxMembershipLevel"`
+	MinLevelToAcceptApplication   int                    `json:"minLevelToAcceptApplication"`
+	MinLevelToCreateInvitation    int                    `json:"minLevelToCreateInvitation"`
+	MinLevelToRemoveMember        int                    `json:"minLevelToRemoveMember"`
+	MinLevelOffsetToRemoveMember  int                    `json:"minLevelOffsetToRemoveMember"`
+	MinLevelOffsetToPromoteMember int                    `json:"minLevelOffsetToPromoteMember"`
+	MinLevelOffsetToDemoteMember  int                    `json:"minLevelOffsetToDemoteMember"`
+	MaxMembers                    int                    `json:"maxMembers"`
+	MaxClansPerPlayer             int                    `json:"maxClansPerPlayer"`
+	CooldownAfterDeny             int                    `json:"cooldownAfterDeny"`
+	CooldownAfterDelete           int                    `json:"cooldownAfterDelete"`
+	CooldownBeforeApply           int                    `json:"cooldownBeforeApply"`
+	CooldownBeforeInvite          int                    `json:"cooldownBeforeInvite"`
+	MaxPendingInvites             int                    `json:"maxPendingInvites"`
+	ClanHookFieldsWhitelist       string                 `json:"clanHookFieldsWhitelist"`
+	PlayerHookFieldsWhitelist     string                 `json:"playerHookFieldsWhitelist"`
+	TopClansMetadataDimensions    string                 `json:"topClansMetadataDimensions"`
+	ClanSearchMetadataKeys        string                 `json:"clanSearchMetadataKeys"`
+	CreatedAt                     int64                  `json:"createdAt"`
+	UpdatedAt                     int64                  `json:"updatedAt"`
+	Version                       int64                  `json:"version"`
+}
+
+// GamesResult is used to unmarshal the response payload for list games route
+type GamesResult struct {
+	Games []*Game `json:"games"`
+}
+
+// Hook is the structure of each hook returned by the list hooks route
+type Hook struct {
+	GameID        string `json:"gameID"`
+	PublicID      string `json:"publicID"`
+	Type          int    `json:"type"`
+	HookURL       string `json:"hookURL"`
+	Signed        bool   `json:"signed"`
+	Filter        string `json:"filter"`
+	BatchSize     int    `json:"batchSize"`
+	BatchInterval int    `json:"batchInterval"`
+	Kind          string `json:"kind"`
+	FailOpen      bool   `json:"failOpen"`
+	CreatedAt     int64  `json:"createdAt"`
+	UpdatedAt     int64  `json:"updatedAt"`
+}
+
+// HooksResult is used to unmarshal the response payload for list hooks route
+type HooksResult struct {
+	Hooks []*Hook `json:"hooks"`
 }
 
 // ApplicationPayload is the argument on apply for membership
@@ -202,6 +276,13 @@ type DeleteMembershipPayload struct {
 	RequestorPublicID string `json:"requestorPublicID"`
 }
 
+// BanPayload is the argument on ban and unban member methods
+type BanPayload struct {
+	ClanID            string `json:"-"`
+	PlayerPublicID    string `json:"playerPublicID"`
+	RequestorPublicID string `json:"requestorPublicID"`
+}
+
 // LeaveClanResult is the result of leave clan method
 type LeaveClanResult struct {
 	Success       bool
@@ -220,11 +301,33 @@ type TransferOwnershipResult struct {
 // Result is the default result
 type Result struct {
 	Success bool
+	Version int64
 }
 
-type SearchClansResult struct {
+// ClanSuggestion is a clan whose name starts with an autocomplete prefix
+type ClanSuggestion struct {
+	PublicID        string `json:"publicID"`
+	Name            string `json:"name"`
+	MembershipCount int    `json:"membershipCount"`
+}
+
+// AutocompleteClansResult is the result of autocomplete clans method
+type AutocompleteClansResult struct {
 	Success bool
-	Clans   []*ClanSummary
+	Clans   []*ClanSuggestion
+}
+
+// TopClansResult is the result of top clans method
+type TopClansResult struct {
+	Success   bool
+	Dimension string
+	Clans     []*TopClan
+}
+
+type SearchClansResult struct {
+	Success    bool
+	Clans      []*ClanSummary
+	NextCursor string // empty when there are no more pages
 }
 
 type SearchMethod int
@@ -242,4 +345,18 @@ type SearchOptions struct {
 	Method SearchMethod
 	Limit  *OptionalInt
 	From   int
+	Cursor string // NextCursor of the previous page
+
+	// Filters, nil or empty values do not filter the clans
+	AllowApplication   *bool
+	AutoJoin           *bool
+	MinMembershipCount *OptionalInt
+	MaxMembershipCount *OptionalInt
+	HasFreeSlots       *bool
+	Metadata           map[string]string // keys must be in the game clanSearchMetadataKeys
+	MinMetadata        map[string]float64
+	MaxMetadata        map[string]float64
+
+	Sort  string // relevance (default), membershipCount, createdAt, name or metadata.<key>
+	Order string // asc or desc
 }
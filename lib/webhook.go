@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader is the header with the signature of webhooks registered with a secret
+	WebhookSignatureHeader = "X-Khan-Signature"
+	// WebhookTimestampHeader is the header with the unix timestamp (in seconds) of the delivery
+	WebhookTimestampHeader = "X-Khan-Timestamp"
+)
+
+var (
+	// ErrInvalidWebhookSignature is returned when the signature does not match the payload
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrInvalidWebhookTimestamp is returned when the timestamp is missing, malformed or outside the tolerance
+	ErrInvalidWebhookTimestamp = errors.New("invalid webhook timestamp")
+)
+
+// SignWebhookPayload returns the signature Khan sends for a delivery with the given timestamp and body
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifyWebhookSignature checks that a delivery was signed with the hook secret.
+// Deliveries with a timestamp older or newer than tolerance are rejected to
+// prevent replays. A zero tolerance disables the timestamp check.
+func VerifyWebhookSignature(secret, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookTimestamp
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(sentAt, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrInvalidWebhookTimestamp
+		}
+	}
+
+	expected := SignWebhookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// VerifyWebhookRequest checks the signature headers of a webhook request and
+// returns its body. The request body is restored so it can be read again.
+func VerifyWebhookRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+	err = VerifyWebhookSignature(
+		secret,
+		r.Header.Get(WebhookTimestampHeader),
+		r.Header.Get(WebhookSignatureHeader),
+		body,
+		tolerance,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
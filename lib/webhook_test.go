@@ -0,0 +1,90 @@
+package lib_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/topfreegames/khan/lib"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook", func() {
+	var body []byte
+	var timestamp string
+
+	BeforeEach(func() {
+		body = []byte(`{"gameID":"testgame","publicID":"clan-id"}`)
+		timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+	})
+
+	Describe("VerifyWebhookSignature", func() {
+		It("Should accept a valid signature", func() {
+			signature := lib.SignWebhookPayload("secret", timestamp, body)
+			err := lib.VerifyWebhookSignature("secret", timestamp, signature, body, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a signature made with another secret", func() {
+			signature := lib.SignWebhookPayload("other-secret", timestamp, body)
+			err := lib.VerifyWebhookSignature("secret", timestamp, signature, body, time.Minute)
+			Expect(err).To(Equal(lib.ErrInvalidWebhookSignature))
+		})
+
+		It("Should reject a tampered body", func() {
+			signature := lib.SignWebhookPayload("secret", timestamp, body)
+			err := lib.VerifyWebhookSignature("secret", timestamp, signature, []byte(`{}`), time.Minute)
+			Expect(err).To(Equal(lib.ErrInvalidWebhookSignature))
+		})
+
+		It("Should reject an old timestamp", func() {
+			timestamp = strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+			signature := lib.SignWebhookPayload("secret", timestamp, body)
+			err := lib.VerifyWebhookSignature("secret", timestamp, signature, body, time.Minute)
+			Expect(err).To(Equal(lib.ErrInvalidWebhookTimestamp))
+		})
+
+		It("Should accept an old timestamp if tolerance is zero", func() {
+			timestamp = strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+			signature := lib.SignWebhookPayload("secret", timestamp, body)
+			err := lib.VerifyWebhookSignature("secret", timestamp, signature, body, 0)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a missing timestamp", func() {
+			signature := lib.SignWebhookPayload("secret", "", body)
+			err := lib.VerifyWebhookSignature("secret", "", signature, body, time.Minute)
+			Expect(err).To(Equal(lib.ErrInvalidWebhookTimestamp))
+		})
+	})
+
+	Describe("VerifyWebhookRequest", func() {
+		It("Should verify the request headers and restore its body", func() {
+			req, err := http.NewRequest("POST", "http://my-service/hook", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set(lib.WebhookTimestampHeader, timestamp)
+			req.Header.Set(lib.WebhookSignatureHeader, lib.SignWebhookPayload("secret", timestamp, body))
+
+			verifiedBody, err := lib.VerifyWebhookRequest(req, "secret", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verifiedBody).To(Equal(body))
+
+			restoredBody, err := ioutil.ReadAll(req.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restoredBody).To(Equal(body))
+		})
+
+		It("Should fail if the request is not signed", func() {
+			req, err := http.NewRequest("POST", "http://my-service/hook", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set(lib.WebhookTimestampHeader, timestamp)
+
+			_, err = lib.VerifyWebhookRequest(req, "secret", time.Minute)
+			Expect(err).To(Equal(lib.ErrInvalidWebhookSignature))
+		})
+	})
+})
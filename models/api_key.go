@@ -0,0 +1,211 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/go-gorp/gorp"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/util"
+)
+
+// api key scopes, each one allows everything the previous ones allow
+const (
+	APIKeyScopeReadOnly    = "read-only"
+	APIKeyScopePlayerWrite = "player-write"
+	APIKeyScopeAdmin       = "admin"
+)
+
+var apiKeyScopeLevels = map[string]int{
+	APIKeyScopeReadOnly:    1,
+	APIKeyScopePlayerWrite: 2,
+	APIKeyScopeAdmin:       3,
+}
+
+// apiKeyPrefixLength is the number of characters of the key stored in clear to identify it
+const apiKeyPrefixLength = 12
+
+// APIKey allows a caller to use the routes of a single game, within its scope
+// Only the SHA-256 hash of the key is stored, the key itself is returned once when it is created or rotated.
+type APIKey struct {
+	ID        int64  `db:"id"`
+	GameID    string `db:"game_id"`
+	PublicID  string `db:"public_id"`
+	Name      string `db:"name"`
+	Scope     string `db:"scope"`
+	KeyHash   string `db:"key_hash"`
+	KeyPrefix string `db:"key_prefix"`
+	CreatedAt int64  `db:"created_at"`
+	UpdatedAt int64  `db:"updated_at"`
+	RotatedAt int64  `db:"rotated_at"`
+	RevokedAt int64  `db:"revoked_at"`
+}
+
+// PreInsert populates fields before inserting a new api key
+func (k *APIKey) PreInsert(s gorp.SqlExecutor) error {
+	k.CreatedAt = util.NowMilli()
+	k.UpdatedAt = k.CreatedAt
+	return nil
+}
+
+// PreUpdate populates fields before updating an api key
+func (k *APIKey) PreUpdate(s gorp.SqlExecutor) error {
+	k.UpdatedAt = util.NowMilli()
+	return nil
+}
+
+// Serialize returns a JSON with api key details, without the key
+func (k *APIKey) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":    k.GameID,
+		"publicID":  k.PublicID,
+		"name":      k.Name,
+		"scope":     k.Scope,
+		"keyPrefix": k.KeyPrefix,
+		"createdAt": k.CreatedAt,
+		"rotatedAt": k.RotatedAt,
+		"revokedAt": k.RevokedAt,
+	}
+}
+
+// Revoked returns whether the api key was revoked
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt > 0
+}
+
+// Allows returns whether the api key scope includes the required scope
+func (k *APIKey) Allows(scope string) bool {
+	required, ok := apiKeyScopeLevels[scope]
+	return ok && apiKeyScopeLevels[k.Scope] >= required
+}
+
+// IsValidAPIKeyScope returns whether the scope exists
+func IsValidAPIKeyScope(scope string) bool {
+	_, ok := apiKeyScopeLevels[scope]
+	return ok
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of the key
+// Keys are random, so they don't need a slow or salted hash.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func generateAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "khan_" + hex.EncodeToString(secret), nil
+}
+
+// setKey replaces the key of the api key, returning the new one
+func (k *APIKey) setKey() (string, error) {
+	key, err := generateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	k.KeyHash = HashAPIKey(key)
+	k.KeyPrefix = key[:apiKeyPrefixLength]
+	return key, nil
+}
+
+// CreateAPIKey creates a new api key for the game, returning it along with the key
+func CreateAPIKey(db DB, gameID, name, scope string) (*APIKey, string, error) {
+	if !IsValidAPIKeyScope(scope) {
+		return nil, "", &InvalidAPIKeyScopeError{scope}
+	}
+
+	apiKey := &APIKey{
+		GameID:   gameID,
+		PublicID: uuid.NewV4().String(),
+		Name:     name,
+		Scope:    scope,
+	}
+	key, err := apiKey.setKey()
+	if err != nil {
+		return nil, "", err
+	}
+	if err = db.Insert(apiKey); err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// GetAPIKeyByPublicID returns an api key by game id and public id
+func GetAPIKeyByPublicID(db DB, gameID, publicID string) (*APIKey, error) {
+	var apiKey APIKey
+	err := db.SelectOne(&apiKey, "SELECT * FROM api_keys WHERE game_id=$1 AND public_id=$2", gameID, publicID)
+	if err != nil {
+		return nil, &ModelNotFoundError{"APIKey", publicID}
+	}
+	return &apiKey, nil
+}
+
+// GetAPIKeysByGameID returns the api keys of the game, revoked ones included
+func GetAPIKeysByGameID(db DB, gameID string) ([]*APIKey, error) {
+	var apiKeys []*APIKey
+	_, err := db.Select(&apiKeys, "SELECT * FROM api_keys WHERE game_id=$1 ORDER BY created_at", gameID)
+	if err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+// GetAPIKeyByKey returns the api key that was not revoked with the given key
+func GetAPIKeyByKey(db DB, key string) (*APIKey, error) {
+	var apiKey APIKey
+	err := db.SelectOne(&apiKey, "SELECT * FROM api_keys WHERE key_hash=$1 AND revoked_at=0", HashAPIKey(key))
+	if err != nil {
+		return nil, &ModelNotFoundError{"APIKey", HashAPIKey(key)}
+	}
+	return &apiKey, nil
+}
+
+// RotateAPIKey replaces the key of an api key that was not revoked, returning it along with the new key
+// The previous key stops working immediately.
+func RotateAPIKey(db DB, gameID, publicID string) (*APIKey, string, error) {
+	apiKey, err := GetAPIKeyByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, "", err
+	}
+	if apiKey.Revoked() {
+		return nil, "", &APIKeyRevokedError{publicID}
+	}
+
+	key, err := apiKey.setKey()
+	if err != nil {
+		return nil, "", err
+	}
+	apiKey.RotatedAt = util.NowMilli()
+	if _, err = db.Update(apiKey); err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// RevokeAPIKey revokes an api key, which stops working immediately
+func RevokeAPIKey(db DB, gameID, publicID string) (*APIKey, error) {
+	apiKey, err := GetAPIKeyByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.Revoked() {
+		return apiKey, nil
+	}
+
+	apiKey.RevokedAt = util.NowMilli()
+	if _, err = db.Update(apiKey); err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
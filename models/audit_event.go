@@ -0,0 +1,130 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/util"
+
+	"github.com/go-gorp/gorp"
+)
+
+// Audited actions
+const (
+	AuditClanCreated                   = "clan.created"
+	AuditClanUpdated                   = "clan.updated"
+	AuditClanLeft                      = "clan.left"
+	AuditClanOwnershipTransferred      = "clan.ownership.transferred"
+	AuditClanDisbanded                 = "clan.disbanded"
+	AuditMembershipApplied             = "membership.applied"
+	AuditMembershipInvited             = "membership.invited"
+	AuditMembershipApplicationApproved = "membership.application.approved"
+	AuditMembershipApplicationDenied   = "membership.application.denied"
+	AuditMembershipInvitationApproved  = "membership.invitation.approved"
+	AuditMembershipInvitationDenied    = "membership.invitation.denied"
+	AuditMembershipPromoted            = "membership.promoted"
+	AuditMembershipDemoted             = "membership.demoted"
+	AuditMembershipDeleted             = "membership.deleted"
+	AuditMembershipBanned              = "membership.banned"
+	AuditMembershipUnbanned            = "membership.unbanned"
+)
+
+// AuditEvent records a mutating action performed on a clan or membership
+type AuditEvent struct {
+	ID             int64                  `db:"id"`
+	GameID         string                 `db:"game_id"`
+	PublicID       string                 `db:"public_id"`
+	Action         string                 `db:"action"`
+	ActorPublicID  string                 `db:"actor_public_id"`
+	ClanPublicID   string                 `db:"clan_public_id"`
+	PlayerPublicID string                 `db:"player_public_id"`
+	Before         map[string]interface{} `db:"before"`
+	After          map[string]interface{} `db:"after"`
+	RequestID      string                 `db:"request_id"`
+	CreatedAt      int64                  `db:"created_at"`
+}
+
+// PreInsert populates fields before inserting a new audit event
+func (a *AuditEvent) PreInsert(s gorp.SqlExecutor) error {
+	a.CreatedAt = util.NowMilli()
+	return nil
+}
+
+// Serialize returns a JSON with audit event details
+func (a *AuditEvent) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"publicID":       a.PublicID,
+		"action":         a.Action,
+		"actorPublicID":  a.ActorPublicID,
+		"clanPublicID":   a.ClanPublicID,
+		"playerPublicID": a.PlayerPublicID,
+		"before":         a.Before,
+		"after":          a.After,
+		"requestID":      a.RequestID,
+		"createdAt":      a.CreatedAt,
+	}
+}
+
+// CreateAuditEvent records an action performed by the actor on a clan and/or player
+// Before and after hold snapshots of the changed entity and are empty when it did not exist.
+func CreateAuditEvent(
+	db DB, gameID, action, actorPublicID, clanPublicID, playerPublicID string,
+	before, after map[string]interface{}, requestID string,
+) (*AuditEvent, error) {
+	if before == nil {
+		before = map[string]interface{}{}
+	}
+	if after == nil {
+		after = map[string]interface{}{}
+	}
+
+	event := &AuditEvent{
+		GameID:         gameID,
+		PublicID:       uuid.NewV4().String(),
+		Action:         action,
+		ActorPublicID:  actorPublicID,
+		ClanPublicID:   clanPublicID,
+		PlayerPublicID: playerPublicID,
+		Before:         before,
+		After:          after,
+		RequestID:      requestID,
+	}
+	err := db.Insert(event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// GetClanAuditEvents returns the audit events of the clan, most recent first
+func GetClanAuditEvents(db DB, gameID, clanPublicID string, limit, from int) ([]*AuditEvent, error) {
+	var events []*AuditEvent
+	_, err := db.Select(&events, `
+	SELECT * FROM audit_events
+	WHERE game_id=$1 AND clan_public_id=$2
+	ORDER BY created_at DESC, id DESC
+	LIMIT $3 OFFSET $4`, gameID, clanPublicID, limit, from)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetPlayerAuditEvents returns the audit events performed by or on the player, most recent first
+func GetPlayerAuditEvents(db DB, gameID, playerPublicID string, limit, from int) ([]*AuditEvent, error) {
+	var events []*AuditEvent
+	_, err := db.Select(&events, `
+	SELECT * FROM audit_events
+	WHERE game_id=$1 AND (player_public_id=$2 OR actor_public_id=$2)
+	ORDER BY created_at DESC, id DESC
+	LIMIT $3 OFFSET $4`, gameID, playerPublicID, limit, from)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,117 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Audit Event Model", func() {
+	var testDb DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Create Audit Event", func() {
+		It("Should create a new audit event", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			event, err := CreateAuditEvent(
+				testDb, game.PublicID, AuditClanUpdated, player.PublicID, "clan-id", player.PublicID,
+				map[string]interface{}{"name": "old"}, map[string]interface{}{"name": "new"}, "request-id",
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.ID).NotTo(BeEquivalentTo(0))
+			Expect(event.PublicID).NotTo(BeEmpty())
+			Expect(event.CreatedAt).To(BeNumerically(">", 0))
+
+			events, err := GetClanAuditEvents(testDb, game.PublicID, "clan-id", 10, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(AuditClanUpdated))
+			Expect(events[0].ActorPublicID).To(Equal(player.PublicID))
+			Expect(events[0].PlayerPublicID).To(Equal(player.PublicID))
+			Expect(events[0].Before["name"]).To(Equal("old"))
+			Expect(events[0].After["name"]).To(Equal("new"))
+			Expect(events[0].RequestID).To(Equal("request-id"))
+		})
+
+		It("Should store empty snapshots when not given", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = CreateAuditEvent(
+				testDb, game.PublicID, AuditClanCreated, player.PublicID, "clan-id", player.PublicID,
+				nil, map[string]interface{}{"name": "new"}, "",
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := GetClanAuditEvents(testDb, game.PublicID, "clan-id", 10, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Before).To(BeEmpty())
+		})
+	})
+
+	Describe("Get Clan Audit Events", func() {
+		It("Should paginate the clan events, most recent first", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+			clanPublicID := uuid.NewV4().String()
+
+			for _, action := range []string{AuditClanCreated, AuditClanUpdated, AuditMembershipInvited} {
+				_, err = CreateAuditEvent(testDb, game.PublicID, action, player.PublicID, clanPublicID, "", nil, nil, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			_, err = CreateAuditEvent(testDb, game.PublicID, AuditClanCreated, player.PublicID, "other-clan", "", nil, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := GetClanAuditEvents(testDb, game.PublicID, clanPublicID, 2, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Action).To(Equal(AuditMembershipInvited))
+			Expect(events[1].Action).To(Equal(AuditClanUpdated))
+
+			events, err = GetClanAuditEvents(testDb, game.PublicID, clanPublicID, 2, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(AuditClanCreated))
+		})
+	})
+
+	Describe("Get Player Audit Events", func() {
+		It("Should get the events performed by or on the player", func() {
+			game, player, err := CreatePlayerFactory(testDb, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, other, err := CreatePlayerFactory(testDb, game.PublicID, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = CreateAuditEvent(testDb, game.PublicID, AuditMembershipInvited, other.PublicID, "clan-id", player.PublicID, nil, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = CreateAuditEvent(testDb, game.PublicID, AuditMembershipApplied, player.PublicID, "clan-id", player.PublicID, nil, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = CreateAuditEvent(testDb, game.PublicID, AuditMembershipPromoted, player.PublicID, "clan-id", other.PublicID, nil, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := GetPlayerAuditEvents(testDb, game.PublicID, player.PublicID, 10, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(3))
+
+			events, err = GetPlayerAuditEvents(testDb, game.PublicID, other.PublicID, 10, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+		})
+	})
+})
@@ -22,7 +22,6 @@ import (
 
 	"github.com/globalsign/mgo/bson"
 	"github.com/go-gorp/gorp"
-	workers "github.com/jrallison/go-workers"
 	"github.com/mailru/easyjson/jlexer"
 	"github.com/mailru/easyjson/jwriter"
 	"github.com/topfreegames/extensions/mongo/interfaces"
@@ -55,6 +54,7 @@ type Clan struct {
 	CreatedAt        int64                  `db:"created_at" json:"createdAt" bson:"createdAt"`
 	UpdatedAt        int64                  `db:"updated_at" json:"updatedAt" bson:"updatedAt"`
 	DeletedAt        int64                  `db:"deleted_at" json:"deletedAt" bson:"deletedAt"`
+	Version          int64                  `db:"version" json:"version" bson:"version"`
 }
 
 // ClanWithNamePrefixes extends Clan with a field to help name indexation in MongoDB
@@ -137,11 +137,15 @@ func (c *Clan) PreInsert(s gorp.SqlExecutor) error {
 
 //PostInsert indexes clan in ES after creation in PG
 func (c *Clan) PostInsert(s gorp.SqlExecutor) error {
-	err := c.IndexClanIntoElasticSearch()
+	err := c.IndexClanIntoElasticSearch(s)
 	if err != nil {
 		return err
 	}
-	err = c.UpdateClanIntoMongoDB()
+	err = c.UpdateClanIntoMongoDB(s)
+	if err != nil {
+		return err
+	}
+	err = c.UpdateClanIntoTopClans(s)
 	return err
 }
 
@@ -153,21 +157,29 @@ func (c *Clan) PreUpdate(s gorp.SqlExecutor) error {
 
 //PostUpdate indexes clan in ES after update in PG
 func (c *Clan) PostUpdate(s gorp.SqlExecutor) error {
-	err := c.UpdateClanIntoElasticSearch()
+	err := c.UpdateClanIntoElasticSearch(s)
 	if err != nil {
 		return err
 	}
-	err = c.UpdateClanIntoMongoDB()
+	err = c.UpdateClanIntoMongoDB(s)
+	if err != nil {
+		return err
+	}
+	err = c.UpdateClanIntoTopClans(s)
 	return err
 }
 
 //PostDelete deletes clan from elasticsearch after deleting from PG
 func (c *Clan) PostDelete(s gorp.SqlExecutor) error {
-	err := c.DeleteClanFromElasticSearch()
+	err := c.DeleteClanFromElasticSearch(s)
+	if err != nil {
+		return err
+	}
+	err = c.DeleteClanFromMongoDB(s)
 	if err != nil {
 		return err
 	}
-	err = c.DeleteClanFromMongoDB()
+	err = c.DeleteClanFromTopClans(s)
 	return err
 }
 
@@ -184,11 +196,10 @@ func getNewLogger() zap.Logger {
 }
 
 //IndexClanIntoElasticSearch after operation in PG
-func (c *Clan) IndexClanIntoElasticSearch() error {
+func (c *Clan) IndexClanIntoElasticSearch(db DB) error {
 	es := es.GetConfiguredClient()
-	// TODO: fix it, boomforce is hardcoded for now
-	if es != nil && c.GameID == "boomforce" {
-		workers.Enqueue(queues.KhanESQueue, "Add", map[string]interface{}{
+	if es != nil {
+		return EnqueueOutbox(db, queues.KhanESQueue, map[string]interface{}{
 			"index":  es.GetIndexName(c.GameID),
 			"op":     "index",
 			"clan":   c,
@@ -199,20 +210,21 @@ func (c *Clan) IndexClanIntoElasticSearch() error {
 }
 
 // NewClanWithNamePrefixes returns a new extended Clan object with name  prefixes
+// Prefixes are folded by FoldClanName, so they can be matched ignoring case and accents.
+// Documents written before prefixes were folded are rewritten by `khan reindex --target mongo`.
 func (c *Clan) NewClanWithNamePrefixes() *ClanWithNamePrefixes {
-	minPrefixLength := 4 // TODO: how to bring the app Viper config here?
-	caseSensitiveWords := strings.Fields(c.Name)
+	words := strings.Fields(FoldClanName(c.Name))
 	foundPrefixes := make(map[string]bool)
 	var prefixes []string
-	for _, caseSensitiveWord := range caseSensitiveWords {
-		word := strings.ToLower(caseSensitiveWord)
-		wordLen := len(word)
-		firstPrefixIdx := minPrefixLength
+	for _, word := range words {
+		wordRunes := []rune(word)
+		wordLen := len(wordRunes)
+		firstPrefixIdx := minClanNamePrefixLength
 		if firstPrefixIdx > wordLen {
 			firstPrefixIdx = wordLen
 		}
 		for i := firstPrefixIdx; i <= wordLen; i++ {
-			prefix := word[:i]
+			prefix := string(wordRunes[:i])
 			if !foundPrefixes[prefix] {
 				foundPrefixes[prefix] = true
 				prefixes = append(prefixes, prefix)
@@ -227,10 +239,10 @@ func (c *Clan) NewClanWithNamePrefixes() *ClanWithNamePrefixes {
 }
 
 // UpdateClanIntoMongoDB after operation in PG
-func (c *Clan) UpdateClanIntoMongoDB() error {
+func (c *Clan) UpdateClanIntoMongoDB(db DB) error {
 	mongo := mongo.GetConfiguredMongoClient()
 	if mongo != nil {
-		workers.Enqueue(queues.KhanMongoQueue, "Add", map[string]interface{}{
+		return EnqueueOutbox(db, queues.KhanMongoQueue, map[string]interface{}{
 			"game":   c.GameID,
 			"op":     "update",
 			"clan":   c.NewClanWithNamePrefixes(),
@@ -241,10 +253,10 @@ func (c *Clan) UpdateClanIntoMongoDB() error {
 }
 
 //DeleteClanFromMongoDB after deletion in PG
-func (c *Clan) DeleteClanFromMongoDB() error {
+func (c *Clan) DeleteClanFromMongoDB(db DB) error {
 	mongo := mongo.GetConfiguredMongoClient()
 	if mongo != nil {
-		workers.Enqueue(queues.KhanMongoQueue, "Add", map[string]interface{}{
+		return EnqueueOutbox(db, queues.KhanMongoQueue, map[string]interface{}{
 			"game":   c.GameID,
 			"op":     "delete",
 			"clan":   c,
@@ -255,11 +267,10 @@ func (c *Clan) DeleteClanFromMongoDB() error {
 }
 
 //UpdateClanIntoElasticSearch after operation in PG
-func (c *Clan) UpdateClanIntoElasticSearch() error {
+func (c *Clan) UpdateClanIntoElasticSearch(db DB) error {
 	es := es.GetConfiguredClient()
-	// TODO: fix it, boomforce is hardcoded for now
-	if es != nil && c.GameID == "boomforce" {
-		workers.Enqueue(queues.KhanESQueue, "Add", map[string]interface{}{
+	if es != nil {
+		return EnqueueOutbox(db, queues.KhanESQueue, map[string]interface{}{
 			"index":  es.GetIndexName(c.GameID),
 			"op":     "update",
 			"clan":   c,
@@ -270,11 +281,10 @@ func (c *Clan) UpdateClanIntoElasticSearch() error {
 }
 
 //DeleteClanFromElasticSearch after deletion in PG
-func (c *Clan) DeleteClanFromElasticSearch() error {
+func (c *Clan) DeleteClanFromElasticSearch(db DB) error {
 	es := es.GetConfiguredClient()
-	// TODO: fix it, boomforce is hardcoded for now
-	if es != nil && c.GameID == "boomforce" {
-		workers.Enqueue(queues.KhanESQueue, "Add", map[string]interface{}{
+	if es != nil {
+		return EnqueueOutbox(db, queues.KhanESQueue, map[string]interface{}{
 			"index":  es.GetIndexName(c.GameID),
 			"op":     "delete",
 			"clan":   c,
@@ -284,7 +294,8 @@ func (c *Clan) DeleteClanFromElasticSearch() error {
 	return nil
 }
 
-func updateClanIntoES(db DB, id int64) error {
+// updateClanIntoIndexes loads the clan once and updates it in the search indexes and top clans rankings
+func updateClanIntoIndexes(db DB, id int64) error {
 	clan, err := GetClanByID(db, id)
 	if err != nil {
 		return err
@@ -292,18 +303,13 @@ func updateClanIntoES(db DB, id int64) error {
 	if clan == nil {
 		return &ModelNotFoundError{"Clan", id}
 	}
-	return clan.UpdateClanIntoElasticSearch()
-}
-
-func updateClanIntoMongo(db DB, id int64) error {
-	clan, err := GetClanByID(db, id)
-	if err != nil {
+	if err = clan.UpdateClanIntoElasticSearch(db); err != nil {
 		return err
 	}
-	if clan == nil {
-		return &ModelNotFoundError{"Clan", id}
+	if err = clan.UpdateClanIntoMongoDB(db); err != nil {
+		return err
 	}
-	return clan.UpdateClanIntoMongoDB()
+	return clan.UpdateClanIntoTopClans(db)
 }
 
 // Serialize returns a JSON with clan details
@@ -345,19 +351,7 @@ func UpdateClanMembershipCount(db DB, id int64) error {
 		return &ModelNotFoundError{"Clan", id}
 	}
 
-	err = updateClanIntoES(db, id)
-
-	if err != nil {
-		return err
-	}
-
-	err = updateClanIntoMongo(db, id)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return updateClanIntoIndexes(db, id)
 }
 
 // GetClanByID returns a clan by id
@@ -372,10 +366,24 @@ func GetClanByID(db DB, id int64) (*Clan, error) {
 	return obj.(*Clan), nil
 }
 
+// getClanByIDForUpdate returns a clan by id, locking it until the end of the transaction
+// Concurrent transactions that lock the clan wait for this one, so they read its up to date counts.
+func getClanByIDForUpdate(db DB, id int64) (*Clan, error) {
+	var clans []*Clan
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE id=$1 FOR UPDATE", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(clans) == 0 {
+		return nil, &ModelNotFoundError{"Clan", id}
+	}
+	return clans[0], nil
+}
+
 // GetClanByPublicID returns a clan by its public id
 func GetClanByPublicID(db DB, gameID, publicID string) (*Clan, error) {
 	var clans []*Clan
-	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2", gameID, publicID)
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2 AND deleted_at=0", gameID, publicID)
 	if err != nil {
 		return nil, err
 	}
@@ -390,7 +398,7 @@ func GetClanByShortPublicID(db DB, gameID, publicID string) (*Clan, error) {
 	var clans []*Clan
 	// String for between don't need to be be same length as UUID
 	startRange, endRange := publicID+"-0000-0000-0000-000000000000", publicID+"-ffff-ffff-ffff-ffffffffffff"
-	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id BETWEEN $2 AND $3", gameID, startRange, endRange)
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id BETWEEN $2 AND $3 AND deleted_at=0", gameID, startRange, endRange)
 	if err != nil {
 		return nil, err
 	}
@@ -432,7 +440,7 @@ func sliceDiff(slice1 []string, slice2 []string) []string {
 func GetClansByPublicIDs(db DB, gameID string, publicIDs []string) ([]Clan, error) {
 	var clans []Clan
 
-	queryPart := "SELECT * from clans WHERE game_id=$1 AND public_id=%s"
+	queryPart := "SELECT * from clans WHERE game_id=$1 AND public_id=%s AND deleted_at=0"
 	queryParts := []string{}
 	for i := 0; i < len(publicIDs); i++ {
 		paramIndex := fmt.Sprintf("$%d", (i + 2))
@@ -468,7 +476,7 @@ func GetClansByPublicIDs(db DB, gameID string, publicIDs []string) ([]Clan, erro
 func GetClanByPublicIDAndOwnerPublicID(db DB, gameID, publicID, ownerPublicID string) (*Clan, error) {
 	var clans []*Clan
 	var players []*Player
-	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2", gameID, publicID)
+	_, err := db.Select(&clans, "SELECT * FROM clans WHERE game_id=$1 AND public_id=$2 AND deleted_at=0", gameID, publicID)
 	if err != nil {
 		return nil, err
 	}
@@ -494,6 +502,11 @@ func CreateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata ma
 	if err != nil {
 		return nil, err
 	}
+	// locks the owner, so concurrent clans and memberships can't exceed maxClansPerPlayer
+	player, err = getPlayerByIDForUpdate(db, player.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	if player.MembershipCount+player.OwnershipCount >= maxClansPerPlayer {
 		return nil, &PlayerReachedMaxClansError{ownerPublicID}
@@ -682,6 +695,126 @@ func TransferClanOwnership(db DB, gameID, clanPublicID, playerPublicID string, l
 	return clan, oldOwner, newOwner, nil
 }
 
+// ClanMember is a player and the level of their membership in a clan
+type ClanMember struct {
+	Player *Player
+	Level  string
+}
+
+// DisbandClan soft deletes the clan and ends all of its memberships, applications and invitations
+// The requestor must be the clan owner. It returns the disbanded clan, its owner and the members it had.
+func DisbandClan(db DB, gameID, publicID, requestorPublicID string) (*Clan, *Player, []*ClanMember, error) {
+	if requestorPublicID == "" {
+		return nil, nil, nil, &ForbiddenError{gameID, requestorPublicID, publicID}
+	}
+	clan, err := GetClanByPublicIDAndOwnerPublicID(db, gameID, publicID, requestorPublicID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return disbandClan(db, clan)
+}
+
+// AdminDisbandClan disbands the clan on behalf of the game, regardless of its owner
+func AdminDisbandClan(db DB, gameID, publicID string) (*Clan, *Player, []*ClanMember, error) {
+	clan, err := GetClanByPublicID(db, gameID, publicID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return disbandClan(db, clan)
+}
+
+func disbandClan(db DB, clan *Clan) (*Clan, *Player, []*ClanMember, error) {
+	owner, err := GetPlayerByID(db, clan.OwnerID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var memberships []*Membership
+	_, err = db.Select(&memberships, `
+	SELECT * FROM memberships m
+	WHERE m.clan_id=$1 AND m.deleted_at=0 AND m.approved=true
+	ORDER BY m.created_at ASC`, clan.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	members := make([]*ClanMember, len(memberships))
+	for i, membership := range memberships {
+		player, err := GetPlayerByID(db, membership.PlayerID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		members[i] = &ClanMember{Player: player, Level: membership.Level}
+	}
+
+	now := util.NowMilli()
+	_, err = db.Exec(`
+	UPDATE memberships SET deleted_at=$2, deleted_by=$3, approved=false, updated_at=$2
+	WHERE clan_id=$1 AND deleted_at=0`, clan.ID, now, clan.OwnerID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The clan is updated with a query instead of db.Update so that the
+	// clan update hooks do not index it again after it is removed below
+	_, err = db.Exec(`
+	UPDATE clans SET deleted_at=$2, membership_count=0, updated_at=$2
+	WHERE id=$1`, clan.ID, now)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	clan.DeletedAt = now
+	clan.UpdatedAt = now
+	clan.MembershipCount = 0
+
+	for _, member := range members {
+		err = UpdatePlayerMembershipCount(db, member.Player.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		member.Player.MembershipCount--
+	}
+	err = UpdatePlayerOwnershipCount(db, owner.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	owner.OwnershipCount--
+
+	err = clan.DeleteClanFromElasticSearch(db)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = clan.DeleteClanFromMongoDB(db)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = clan.DeleteClanFromTopClans(db)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return clan, owner, members, nil
+}
+
+// CheckClanVersion locks the clan and returns a VersionMismatchError if it is not at the given version
+func CheckClanVersion(db DB, gameID, publicID string, version int64) error {
+	current, err := db.SelectInt(
+		"SELECT version FROM clans WHERE game_id=$1 AND public_id=$2 AND deleted_at=0 FOR UPDATE",
+		gameID, publicID,
+	)
+	if err != nil {
+		return err
+	}
+	// versions start at 1, so SelectInt only returns 0 when the clan does not exist
+	if current == 0 {
+		return &ModelNotFoundError{"Clan", publicID}
+	}
+	if current != version {
+		return &VersionMismatchError{"Clan", publicID, version}
+	}
+	return nil
+}
+
 // UpdateClan updates an existing clan
 func UpdateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata map[string]interface{}, allowApplication, autoJoin bool) (*Clan, error) {
 	clan, err := GetClanByPublicIDAndOwnerPublicID(db, gameID, publicID, ownerPublicID)
@@ -702,10 +835,11 @@ func UpdateClan(db DB, gameID, publicID, name, ownerPublicID string, metadata ma
 	}
 
 	query := `
-		UPDATE clans SET name=$1, metadata=$2, allow_application=$3, auto_join=$4
+		UPDATE clans SET name=$1, metadata=$2, allow_application=$3, auto_join=$4, version=version+1
 		WHERE clans.id=$5
+		RETURNING version
 	`
-	_, err = db.Exec(query, name, metadataBuffer.String(), allowApplication, autoJoin, clan.ID)
+	clan.Version, err = db.SelectInt(query, name, metadataBuffer.String(), allowApplication, autoJoin, clan.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -732,7 +866,7 @@ func GetAllClans(db DB, gameID string) ([]Clan, error) {
 	}
 
 	var clans []Clan
-	_, err := db.Select(&clans, "select * from clans where game_id=$1 order by name", gameID)
+	_, err := db.Select(&clans, "select * from clans where game_id=$1 and deleted_at=0 order by name", gameID)
 	if err != nil {
 		return nil, err
 	}
@@ -740,6 +874,40 @@ func GetAllClans(db DB, gameID string) ([]Clan, error) {
 	return clans, nil
 }
 
+// GetClansPage returns a page of the clans in a given game ordered by creation date and id
+// The page starts after the cursor, if any, and the returned cursor is nil when there are no more pages.
+func GetClansPage(db DB, gameID string, cursor *ClansCursor, limit int) ([]Clan, *ClansCursor, error) {
+	if gameID == "" {
+		return nil, nil, &EmptyGameIDError{"Clan"}
+	}
+
+	var clans []Clan
+	var err error
+	if cursor == nil {
+		_, err = db.Select(&clans, `
+		SELECT * FROM clans
+		WHERE game_id=$1 AND deleted_at=0
+		ORDER BY created_at, id
+		LIMIT $2`, gameID, limit)
+	} else {
+		_, err = db.Select(&clans, `
+		SELECT * FROM clans
+		WHERE game_id=$1 AND deleted_at=0 AND (created_at, id) > ($2, $3)
+		ORDER BY created_at, id
+		LIMIT $4`, gameID, cursor.CreatedAt, cursor.ID, limit)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nextCursor *ClansCursor
+	if limit > 0 && len(clans) == limit {
+		last := clans[len(clans)-1]
+		nextCursor = &ClansCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return clans, nextCursor, nil
+}
+
 // GetClanMembers gets only the ids of then clan members
 func GetClanMembers(db DB, gameID, publicID string) (map[string]interface{}, error) {
 	clan, err := GetClanByPublicID(db, gameID, publicID)
@@ -847,6 +1015,7 @@ func GetClanDetails(db DB, gameID string, clan *Clan, maxClansPerPlayer int, opt
 	result["allowApplication"] = details[0].ClanAllowApplication
 	result["autoJoin"] = details[0].ClanAutoJoin
 	result["membershipCount"] = details[0].ClanMembershipCount
+	result["version"] = clan.Version
 
 	result["owner"] = map[string]interface{}{
 		"publicID": details[0].OwnerPublicID,
@@ -976,33 +1145,76 @@ func searchClanByID(db DB, gameID, publicID string) []Clan {
 func SearchClan(
 	db DB, mongo interfaces.MongoDB, gameID, term string, from int, pageSize int64, searchMethod lib.SearchMethod,
 ) ([]Clan, error) {
+	clans, _, err := SearchClanPage(db, mongo, gameID, term, nil, nil, nil, from, pageSize, searchMethod)
+	return clans, err
+}
+
+// SearchClanPage returns a page of the clans that pass the filters for a given term (by name or publicID)
+// Clans are ordered by the sort, or by text score, and id. Pages start after the cursor, if any,
+// and the returned cursor is nil when there are no more pages.
+func SearchClanPage(
+	db DB, mongo interfaces.MongoDB, gameID, term string, filters *ClanSearchFilters, sort *ClanSearchSort,
+	cursor *ClansCursor, from int, pageSize int64, searchMethod lib.SearchMethod,
+) ([]Clan, *ClansCursor, error) {
 	if term == "" {
-		return nil, &EmptySearchTermError{}
+		return nil, nil, &EmptySearchTermError{}
 	}
 
-	clans := searchClanByID(db, gameID, term)
-	if clans != nil {
-		return clans, nil
+	if cursor == nil {
+		clans := searchClanByID(db, gameID, term)
+		if clans != nil && filters.Match(&clans[0]) {
+			return clans, nil, nil
+		}
 	}
 
-	var filter interface{}
-	if searchMethod == lib.SearchMethodRegex {
-		escapedTerm := fmt.Sprintf(`^\Q%s\E`, term)
-		filter = bson.M{"name": bson.M{"$regex": escapedTerm}}
+	useTextSearch := searchMethod != lib.SearchMethodRegex
+	pipeline := []bson.M{}
+	if useTextSearch {
+		pipeline = append(pipeline,
+			bson.M{"$match": bson.M{"$text": bson.M{"$search": term}}},
+			bson.M{"$addFields": bson.M{"textSearchScore": bson.M{"$meta": "textScore"}}},
+		)
 	} else {
-		filter = bson.M{"$text": bson.M{"$search": term}}
+		escapedTerm := fmt.Sprintf(`^\Q%s\E`, term)
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"name": bson.M{"$regex": escapedTerm}}})
+	}
+	if filtersMatch := filters.mongoMatch(); len(filtersMatch) > 0 {
+		pipeline = append(pipeline, bson.M{"$match": filtersMatch})
+	}
+
+	if cursor != nil {
+		if !sort.Relevance() {
+			pipeline = append(pipeline, bson.M{"$match": sort.mongoAfterCursor(cursor)})
+		} else if useTextSearch {
+			pipeline = append(pipeline, bson.M{"$match": bson.M{"$or": []bson.M{
+				{"textSearchScore": bson.M{"$lt": cursor.TextScore}},
+				{"textSearchScore": cursor.TextScore, "id": bson.M{"$gt": cursor.ID}},
+			}}})
+		} else {
+			pipeline = append(pipeline, bson.M{"$match": bson.M{"id": bson.M{"$gt": cursor.ID}}})
+		}
+	}
+
+	sortFields := bson.D{{Name: "id", Value: 1}}
+	if !sort.Relevance() {
+		sortFields = append(bson.D{{Name: sort.Field, Value: sort.direction()}}, sortFields...)
+	} else if useTextSearch {
+		sortFields = append(bson.D{{Name: "textSearchScore", Value: -1}}, sortFields...)
+	}
+	pipeline = append(pipeline, bson.M{"$sort": sortFields})
+	if from > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": from})
+	}
+	cursorOptions := bson.M{}
+	if pageSize > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": pageSize})
+		cursorOptions["batchSize"] = pageSize
 	}
 
-	projection := bson.M{"textSearchScore": bson.M{"$meta": "textScore"}}
 	cmd := bson.D{
-		{Name: "find", Value: fmt.Sprintf("clans_%s", gameID)},
-		{Name: "filter", Value: filter},
-		{Name: "projection", Value: projection},
-		{Name: "sort", Value: projection},
-		{Name: "limit", Value: pageSize},
-		{Name: "skip", Value: from},
-		{Name: "batchSize", Value: pageSize},
-		{Name: "singleBatch", Value: true},
+		{Name: "aggregate", Value: fmt.Sprintf("clans_%s", gameID)},
+		{Name: "pipeline", Value: pipeline},
+		{Name: "cursor", Value: cursorOptions},
 	}
 
 	var res struct {
@@ -1016,16 +1228,30 @@ func SearchClan(
 	}
 
 	if err := mongo.Run(cmd, &res); err != nil {
-		return []Clan{}, err
+		return []Clan{}, nil, err
 	}
-	clans = make([]Clan, len(res.Cursor.FirstBatch))
+	clans := make([]Clan, len(res.Cursor.FirstBatch))
 	for i, raw := range res.Cursor.FirstBatch {
 		if err := raw.Unmarshal(&clans[i]); err != nil {
-			return []Clan{}, err
+			return []Clan{}, nil, err
 		}
 	}
 
-	return clans, nil
+	var nextCursor *ClansCursor
+	if pageSize > 0 && int64(len(clans)) == pageSize {
+		var score struct {
+			TextSearchScore float64 `bson:"textSearchScore"`
+		}
+		if err := res.Cursor.FirstBatch[len(clans)-1].Unmarshal(&score); err != nil {
+			return []Clan{}, nil, err
+		}
+		last := clans[len(clans)-1]
+		nextCursor = &ClansCursor{TextScore: score.TextSearchScore, ID: last.ID}
+		if !sort.Relevance() {
+			nextCursor = &ClansCursor{SortValue: sort.value(&last), ID: last.ID}
+		}
+	}
+	return clans, nextCursor, nil
 }
 
 // GetClanAndOwnerByPublicID returns the clan as well as the owner of a clan by clan's public id
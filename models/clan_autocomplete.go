@@ -0,0 +1,99 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/topfreegames/extensions/mongo/interfaces"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// minClanNamePrefixLength is the length of the shortest prefix stored for each clan name word
+const minClanNamePrefixLength = 4 // TODO: how to bring the app Viper config here?
+
+// ClanSuggestion is a clan whose name starts with an autocomplete prefix
+type ClanSuggestion struct {
+	PublicID        string `bson:"publicId"`
+	Name            string `bson:"name"`
+	MembershipCount int    `bson:"membershipCount"`
+}
+
+// Serialize returns a JSON with the clan suggestion details
+func (s *ClanSuggestion) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"publicID":        s.PublicID,
+		"name":            s.Name,
+		"membershipCount": s.MembershipCount,
+	}
+}
+
+// FoldClanName returns the name in lower case and without accents, so "Clã" and "CLA" are equivalent
+func FoldClanName(name string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, strings.ToLower(name))
+	if err != nil {
+		return strings.ToLower(name)
+	}
+	return folded
+}
+
+// clanNamePrefixesMatch returns the MongoDB query of the clans whose names start with the prefix
+// Every word but the last must be a whole word of the name, while the last one may be incomplete.
+// Incomplete words shorter than the stored prefixes are matched by a prefix regex, which still uses the index.
+func clanNamePrefixesMatch(prefix string) bson.M {
+	words := strings.Fields(FoldClanName(prefix))
+	conditions := make([]bson.M, len(words))
+	for i, word := range words {
+		if i == len(words)-1 && len([]rune(word)) < minClanNamePrefixLength {
+			conditions[i] = bson.M{"namePrefixes": bson.M{"$regex": fmt.Sprintf("^%s", regexp.QuoteMeta(word))}}
+		} else {
+			conditions[i] = bson.M{"namePrefixes": word}
+		}
+	}
+	return bson.M{"$and": conditions}
+}
+
+// AutocompleteClanNames returns the clans whose names start with the prefix, biggest clans first
+// Case and accents are ignored. It returns an empty list if the prefix has no words or the limit is zero.
+func AutocompleteClanNames(mongo interfaces.MongoDB, gameID, prefix string, limit int) ([]ClanSuggestion, error) {
+	if strings.TrimSpace(prefix) == "" || limit <= 0 {
+		return []ClanSuggestion{}, nil
+	}
+
+	pipeline := []bson.M{
+		{"$match": clanNamePrefixesMatch(prefix)},
+		{"$sort": bson.D{{Name: "membershipCount", Value: -1}, {Name: "id", Value: 1}}},
+		{"$limit": limit},
+		{"$project": bson.M{"_id": 0, "publicId": 1, "name": 1, "membershipCount": 1}},
+	}
+	cmd := bson.D{
+		{Name: "aggregate", Value: fmt.Sprintf("clans_%s", gameID)},
+		{Name: "pipeline", Value: pipeline},
+		{Name: "cursor", Value: bson.M{"batchSize": limit}},
+	}
+
+	var res struct {
+		Cursor struct {
+			FirstBatch []ClanSuggestion `bson:"firstBatch"`
+		} `bson:"cursor"`
+	}
+	if err := mongo.Run(cmd, &res); err != nil {
+		return nil, err
+	}
+	if res.Cursor.FirstBatch == nil {
+		return []ClanSuggestion{}, nil
+	}
+	return res.Cursor.FirstBatch, nil
+}
@@ -0,0 +1,121 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/extensions/mongo/interfaces"
+	"github.com/topfreegames/khan/testing"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Clan Autocomplete Model", func() {
+	var testDb DB
+	var testMongo interfaces.MongoDB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+		testMongo, err = GetTestMongo()
+		Expect(err).NotTo(HaveOccurred())
+
+		ConfigureAndStartGoWorkers()
+	})
+
+	Describe("Fold Clan Name", func() {
+		It("Should ignore case and accents", func() {
+			Expect(FoldClanName("Clã dos BRAVOS")).To(Equal("cla dos bravos"))
+			Expect(FoldClanName("Ærøskøbing Ünïcödé")).To(Equal("ærøskøbing unicode"))
+		})
+
+		It("Should store the folded name prefixes", func() {
+			clan := &Clan{Name: "Clã Água"}
+			Expect(clan.NewClanWithNamePrefixes().NamePrefixes).To(Equal([]string{"cla", "agua"}))
+		})
+	})
+
+	Describe("Autocomplete Clan Names", func() {
+		var gameID string
+		var clans []*Clan
+
+		BeforeEach(func() {
+			var err error
+			var player *Player
+			player, clans, err = GetTestClans(testDb, "", "", 3)
+			Expect(err).NotTo(HaveOccurred())
+			gameID = player.GameID
+
+			err = testing.CreateClanNamePrefixesIndexInMongo(GetTestMongo, gameID)
+			Expect(err).NotTo(HaveOccurred())
+
+			names := []string{"Clã dos Bravos", "Clan Brasil", "Other"}
+			membershipCounts := []int{3, 1, 2}
+			for i, clan := range clans {
+				clan.Name = names[i]
+				clan.MembershipCount = membershipCounts[i]
+				_, err = testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		autocomplete := func(prefix string, limit int) []string {
+			suggestions, err := AutocompleteClanNames(testMongo, gameID, prefix, limit)
+			Expect(err).NotTo(HaveOccurred())
+			names := []string{}
+			for _, suggestion := range suggestions {
+				names = append(names, suggestion.Name)
+			}
+			return names
+		}
+
+		It("Should return the clans that start with the prefix, biggest first", func() {
+			Eventually(func() []string {
+				return autocomplete("CLA", 10)
+			}).Should(Equal([]string{"Clã dos Bravos", "Clan Brasil"}))
+
+			Expect(autocomplete("cla", 1)).To(Equal([]string{"Clã dos Bravos"}))
+			Expect(autocomplete("clan", 10)).To(Equal([]string{"Clan Brasil"}))
+			Expect(autocomplete("brav", 10)).To(Equal([]string{"Clã dos Bravos"}))
+		})
+
+		It("Should match every word of the prefix", func() {
+			Eventually(func() []string {
+				return autocomplete("clã bra", 10)
+			}).Should(Equal([]string{"Clã dos Bravos"}))
+
+			Expect(autocomplete("clan bras", 10)).To(Equal([]string{"Clan Brasil"}))
+			Expect(autocomplete("clan bravos", 10)).To(BeEmpty())
+		})
+
+		It("Should return the suggestion details", func() {
+			var suggestions []ClanSuggestion
+			Eventually(func() []ClanSuggestion {
+				var err error
+				suggestions, err = AutocompleteClanNames(testMongo, gameID, "other", 10)
+				Expect(err).NotTo(HaveOccurred())
+				return suggestions
+			}).Should(HaveLen(1))
+
+			Expect(suggestions[0].PublicID).To(Equal(clans[2].PublicID))
+			Expect(suggestions[0].MembershipCount).To(Equal(2))
+			Expect(suggestions[0].Serialize()).To(Equal(map[string]interface{}{
+				"publicID":        clans[2].PublicID,
+				"name":            "Other",
+				"membershipCount": 2,
+			}))
+		})
+
+		It("Should return an empty list for empty prefixes", func() {
+			Expect(autocomplete("  ", 10)).To(BeEmpty())
+			Expect(autocomplete("clan", 0)).To(BeEmpty())
+		})
+	})
+})
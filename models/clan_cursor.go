@@ -0,0 +1,57 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// ClansCursor is the position of the last clan of a page of clans
+// Clan listings are keyed on (createdAt, id), clan searches on (textScore, id) and
+// sorted clan searches on (sortValue, id).
+// Clients get it as an opaque string and send it back to fetch the next page.
+type ClansCursor struct {
+	CreatedAt int64       `json:"c,omitempty"`
+	TextScore float64     `json:"s,omitempty"`
+	SortValue interface{} `json:"v,omitempty"`
+	ID        int64       `json:"i"`
+}
+
+// Encode returns the opaque representation of the cursor
+func (c *ClansCursor) Encode() string {
+	if c == nil {
+		return ""
+	}
+	cursorJSON, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(cursorJSON)
+}
+
+// DecodeClansCursor parses a cursor returned by Encode
+// An empty cursor means the first page and is decoded as nil.
+func DecodeClansCursor(cursor string) (*ClansCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	invalidCursorErr := &InvalidArgumentError{
+		Param:    "cursor",
+		Expected: "a cursor returned by a previous page",
+		Got:      cursor,
+	}
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalidCursorErr
+	}
+	var c ClansCursor
+	err = json.Unmarshal(cursorJSON, &c)
+	if err != nil {
+		return nil, invalidCursorErr
+	}
+	return &c, nil
+}
@@ -0,0 +1,132 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"context"
+
+	"github.com/topfreegames/khan/es"
+	"gopkg.in/olivere/elastic.v5"
+)
+
+func (f *ClanSearchFilters) esQueries() []elastic.Query {
+	queries := []elastic.Query{}
+	if f == nil {
+		return queries
+	}
+	if f.AllowApplication != nil {
+		queries = append(queries, elastic.NewTermQuery("allowApplication", *f.AllowApplication))
+	}
+	if f.AutoJoin != nil {
+		queries = append(queries, elastic.NewTermQuery("autoJoin", *f.AutoJoin))
+	}
+	if f.MinMembershipCount != nil || f.MaxMembershipCount != nil {
+		rangeQuery := elastic.NewRangeQuery("membershipCount")
+		if f.MinMembershipCount != nil {
+			rangeQuery = rangeQuery.Gte(*f.MinMembershipCount)
+		}
+		if f.MaxMembershipCount != nil {
+			rangeQuery = rangeQuery.Lte(*f.MaxMembershipCount)
+		}
+		queries = append(queries, rangeQuery)
+	}
+	for key, value := range f.Metadata {
+		queries = append(queries, elastic.NewMatchPhraseQuery(ClanSearchMetadataPrefix+key, value))
+	}
+	for key, min := range f.MinMetadata {
+		queries = append(queries, elastic.NewRangeQuery(ClanSearchMetadataPrefix+key).Gte(min))
+	}
+	for key, max := range f.MaxMetadata {
+		queries = append(queries, elastic.NewRangeQuery(ClanSearchMetadataPrefix+key).Lte(max))
+	}
+	return queries
+}
+
+// esSorter returns the elasticsearch sort of the field
+// Names are sorted by their keyword field, since text fields can't be sorted.
+func (s *ClanSearchSort) esSorter() elastic.Sorter {
+	if s.Relevance() {
+		return elastic.NewScoreSort().Desc()
+	}
+	field := s.Field
+	if field == ClanSearchSortByName {
+		field = "name.keyword"
+	}
+	return elastic.NewFieldSort(field).Order(s.Ascending)
+}
+
+// SearchClanES returns a page of the clans indexed in elasticsearch for a given term (by name or publicID)
+// Names match the term fuzzily and clans are ordered by the sort, or by score, and id. Pages start
+// after the cursor, if any, and the returned cursor is nil when there are no more pages.
+func SearchClanES(
+	ctx context.Context, db DB, esClient *es.Client, gameID, term string,
+	filters *ClanSearchFilters, sort *ClanSearchSort, cursor *ClansCursor, from int, pageSize int64,
+) ([]ClanSearchHit, *ClansCursor, error) {
+	if term == "" {
+		return nil, nil, &EmptySearchTermError{}
+	}
+
+	if cursor == nil {
+		clans := searchClanByID(db, gameID, term)
+		if clans != nil && filters.Match(&clans[0]) {
+			return []ClanSearchHit{{Clan: clans[0]}}, nil, nil
+		}
+	}
+
+	nameQuery := elastic.NewBoolQuery().Should(
+		elastic.NewMatchQuery("name", term).Fuzziness("AUTO").Operator("and"),
+		elastic.NewMatchPhrasePrefixQuery("name", term),
+	)
+	query := elastic.NewBoolQuery().
+		Must(nameQuery).
+		Filter(elastic.NewMatchPhraseQuery("gameId", gameID)).
+		Filter(filters.esQueries()...)
+
+	search := esClient.Client.Search().
+		Index(esClient.GetIndexName(gameID)).
+		Type("clan").
+		Query(query).
+		Highlight(elastic.NewHighlight().Fields(elastic.NewHighlighterField("name"))).
+		SortBy(sort.esSorter(), elastic.NewFieldSort("id").Asc())
+	if cursor != nil && sort.Relevance() {
+		search = search.SearchAfter(cursor.TextScore, cursor.ID)
+	} else if cursor != nil {
+		search = search.SearchAfter(cursor.SortValue, cursor.ID)
+	} else if from > 0 {
+		search = search.From(from)
+	}
+	if pageSize > 0 {
+		search = search.Size(int(pageSize))
+	}
+
+	res, err := search.Do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hits := make([]ClanSearchHit, len(res.Hits.Hits))
+	for i, hit := range res.Hits.Hits {
+		clan, err := GetClanFromJSON(*hit.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+		hits[i] = ClanSearchHit{Clan: *clan, Highlight: hit.Highlight}
+	}
+
+	var nextCursor *ClansCursor
+	if pageSize > 0 && int64(len(hits)) == pageSize {
+		last := res.Hits.Hits[len(hits)-1]
+		if sort.Relevance() {
+			score, _ := last.Sort[0].(float64)
+			nextCursor = &ClansCursor{TextScore: score, ID: hits[len(hits)-1].ID}
+		} else {
+			nextCursor = &ClansCursor{SortValue: last.Sort[0], ID: hits[len(hits)-1].ID}
+		}
+	}
+	return hits, nextCursor, nil
+}
@@ -0,0 +1,80 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Clan Search Filters", func() {
+	yes := true
+	two := 2
+
+	clan := &Clan{
+		AllowApplication: true,
+		AutoJoin:         false,
+		MembershipCount:  3,
+		Metadata:         map[string]interface{}{"region": "eu", "level": float64(10)},
+	}
+
+	It("Should be empty without filters", func() {
+		var filters *ClanSearchFilters
+		Expect(filters.Empty()).To(BeTrue())
+		Expect(filters.Match(clan)).To(BeTrue())
+		Expect((&ClanSearchFilters{Metadata: map[string]interface{}{}}).Empty()).To(BeTrue())
+	})
+
+	It("Should match the clans that pass all filters", func() {
+		filters := &ClanSearchFilters{
+			AllowApplication:   &yes,
+			MinMembershipCount: &two,
+			Metadata:           map[string]interface{}{"region": "eu", "level": "10"},
+		}
+		Expect(filters.Empty()).To(BeFalse())
+		Expect(filters.Match(clan)).To(BeTrue())
+	})
+
+	It("Should not match the clans that fail a filter", func() {
+		Expect((&ClanSearchFilters{AutoJoin: &yes}).Match(clan)).To(BeFalse())
+		Expect((&ClanSearchFilters{MaxMembershipCount: &two}).Match(clan)).To(BeFalse())
+		Expect((&ClanSearchFilters{Metadata: map[string]interface{}{"region": "us"}}).Match(clan)).To(BeFalse())
+		Expect((&ClanSearchFilters{Metadata: map[string]interface{}{"language": "en"}}).Match(clan)).To(BeFalse())
+	})
+
+	It("Should match the clans within the metadata ranges", func() {
+		Expect((&ClanSearchFilters{MinMetadata: map[string]float64{"level": 10}}).Match(clan)).To(BeTrue())
+		Expect((&ClanSearchFilters{MaxMetadata: map[string]float64{"level": 9}}).Match(clan)).To(BeFalse())
+		Expect((&ClanSearchFilters{MinMetadata: map[string]float64{"region": 1}}).Match(clan)).To(BeFalse())
+	})
+
+	It("Should filter the clans with free slots", func() {
+		game := &Game{MaxMembers: 3}
+		filters := &ClanSearchFilters{}
+		filters.SetHasFreeSlots(game, true)
+		Expect(*filters.MaxMembershipCount).To(Equal(2))
+		Expect(filters.Match(clan)).To(BeFalse())
+
+		filters = &ClanSearchFilters{}
+		filters.SetHasFreeSlots(game, false)
+		Expect(*filters.MinMembershipCount).To(Equal(3))
+		Expect(filters.Match(clan)).To(BeTrue())
+	})
+
+	It("Should only accept the game clan search metadata keys", func() {
+		game := &Game{ClanSearchMetadataKeys: "region, level"}
+		Expect((&ClanSearchFilters{Metadata: map[string]interface{}{"region": "eu"}}).Validate(game)).To(Succeed())
+		Expect((&ClanSearchFilters{MaxMetadata: map[string]float64{"trophies": 1}}).Validate(game)).NotTo(Succeed())
+		Expect((&ClanSearchSort{Field: "metadata.level"}).Validate(game)).To(Succeed())
+		Expect((&ClanSearchSort{Field: "metadata.trophies"}).Validate(game)).NotTo(Succeed())
+		Expect((&ClanSearchSort{Field: ClanSearchSortByName}).Validate(game)).To(Succeed())
+		Expect((&ClanSearchSort{Field: "level"}).Validate(game)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,205 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/topfreegames/extensions/mongo/interfaces"
+	"github.com/topfreegames/khan/es"
+	"gopkg.in/olivere/elastic.v5"
+)
+
+// CountClans returns the number of clans in a given game
+func CountClans(db DB, gameID string) (int64, error) {
+	return db.SelectInt("SELECT COUNT(*) FROM clans WHERE game_id=$1 AND deleted_at=0", gameID)
+}
+
+// GetClanMongoDocument returns the clan as written to MongoDB by the mongo worker
+func GetClanMongoDocument(clan *Clan) (map[string]interface{}, error) {
+	body, err := json.Marshal(clan.NewClanWithNamePrefixes())
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	err = json.Unmarshal(body, &doc)
+	return doc, err
+}
+
+// BulkUpsertClansIntoMongoDB writes the clans to the MongoDB collection in a single command
+func BulkUpsertClansIntoMongoDB(mongo interfaces.MongoDB, collection string, clans []Clan) error {
+	if len(clans) == 0 {
+		return nil
+	}
+
+	updates := make([]bson.M, len(clans))
+	for i := range clans {
+		doc, err := GetClanMongoDocument(&clans[i])
+		if err != nil {
+			return err
+		}
+		updates[i] = bson.M{"q": bson.M{"_id": clans[i].PublicID}, "u": doc, "upsert": true}
+	}
+	cmd := bson.D{
+		{Name: "update", Value: collection},
+		{Name: "updates", Value: updates},
+		{Name: "ordered", Value: false},
+	}
+
+	var res struct {
+		OK          int           `bson:"ok"`
+		WriteErrors []interface{} `bson:"writeErrors"`
+	}
+	if err := mongo.Run(cmd, &res); err != nil {
+		return err
+	}
+	if res.OK != 1 || len(res.WriteErrors) > 0 {
+		return fmt.Errorf("failed to write %d of %d clans into %s", len(res.WriteErrors), len(clans), collection)
+	}
+	return nil
+}
+
+// CreateMongoDBCollection creates an empty MongoDB collection
+func CreateMongoDBCollection(mongo interfaces.MongoDB, collection string) error {
+	return mongo.Run(bson.D{{Name: "create", Value: collection}}, nil)
+}
+
+// ReplaceMongoDBCollection atomically replaces the target collection with the source one and drops the source
+// The documents are copied by an aggregation $out stage, which keeps the indexes of the target collection.
+func ReplaceMongoDBCollection(mongo interfaces.MongoDB, source, target string) error {
+	cmd := bson.D{
+		{Name: "aggregate", Value: source},
+		{Name: "pipeline", Value: []bson.M{{"$out": target}}},
+		{Name: "cursor", Value: bson.M{}},
+	}
+	if err := mongo.Run(cmd, nil); err != nil {
+		return err
+	}
+	return mongo.Run(bson.D{{Name: "drop", Value: source}}, nil)
+}
+
+// BulkIndexClansIntoElasticSearch writes the clans to the elasticsearch index in a single request
+func BulkIndexClansIntoElasticSearch(ctx context.Context, esClient *es.Client, index string, clans []Clan) error {
+	if len(clans) == 0 {
+		return nil
+	}
+
+	bulk := esClient.Client.Bulk()
+	for i := range clans {
+		bulk = bulk.Add(elastic.NewBulkIndexRequest().
+			Index(index).
+			Type("clan").
+			Id(clans[i].PublicID).
+			Doc(clans[i]))
+	}
+	res, err := bulk.Do(ctx)
+	if err != nil {
+		return err
+	}
+	if failed := res.Failed(); len(failed) > 0 {
+		return fmt.Errorf("failed to index %d of %d clans into %s: %v", len(failed), len(clans), index, failed[0].Error)
+	}
+	return nil
+}
+
+// CreateElasticSearchIndexLike creates the index with the mappings and the analysis, shards and replicas
+// settings of the source index or alias, so clans are indexed into it as into the source
+// The index is created with the default settings if the source does not exist.
+func CreateElasticSearchIndexLike(ctx context.Context, esClient *es.Client, index, source string) error {
+	body := map[string]interface{}{}
+	exists, err := esClient.Client.IndexExists(source).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		// an alias may point to several indexes while it is swapped, they all have the same mappings
+		mappings, err := esClient.Client.GetMapping().Index(source).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, indexMappings := range mappings {
+			if indexMappings, ok := indexMappings.(map[string]interface{}); ok {
+				body["mappings"] = indexMappings["mappings"]
+				break
+			}
+		}
+
+		settings, err := esClient.Client.IndexGetSettings(source).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, indexSettings := range settings {
+			current, _ := indexSettings.Settings["index"].(map[string]interface{})
+			copied := map[string]interface{}{}
+			for _, key := range []string{"analysis", "number_of_shards", "number_of_replicas"} {
+				if value, ok := current[key]; ok {
+					copied[key] = value
+				}
+			}
+			body["settings"] = map[string]interface{}{"index": copied}
+			break
+		}
+	}
+
+	_, err = esClient.Client.CreateIndex(index).BodyJson(body).Do(ctx)
+	return err
+}
+
+// SwapElasticSearchAlias atomically points the alias to the index, removing it from the indexes it pointed to
+// The previous indexes are deleted. An index named as the alias is deleted in the same request that creates
+// the alias, since both can't exist at the same time.
+func SwapElasticSearchAlias(ctx context.Context, esClient *es.Client, alias, index string) error {
+	previousIndexes := []string{}
+	exists, err := esClient.Client.IndexExists(alias).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		res, err := esClient.Client.Aliases().Index(alias).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for previousIndex := range res.Indices {
+			previousIndexes = append(previousIndexes, previousIndex)
+		}
+	}
+
+	actions := []map[string]interface{}{
+		{"add": map[string]interface{}{"index": index, "alias": alias}},
+	}
+	for _, previousIndex := range previousIndexes {
+		switch previousIndex {
+		case index:
+		case alias:
+			actions = append(actions, map[string]interface{}{
+				"remove_index": map[string]interface{}{"index": alias},
+			})
+		default:
+			actions = append(actions, map[string]interface{}{
+				"remove": map[string]interface{}{"index": previousIndex, "alias": alias},
+			})
+		}
+	}
+	body := map[string]interface{}{"actions": actions}
+	if _, err := esClient.Client.PerformRequest(ctx, "POST", "/_aliases", nil, body); err != nil {
+		return err
+	}
+
+	for _, previousIndex := range previousIndexes {
+		if previousIndex == alias || previousIndex == index {
+			continue
+		}
+		if _, err := esClient.Client.DeleteIndex(previousIndex).Do(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
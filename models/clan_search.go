@@ -0,0 +1,297 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// ClanSearchSortByRelevance orders the searched clans by how well they match the term
+const ClanSearchSortByRelevance = "relevance"
+
+// ClanSearchSortByMembershipCount orders the searched clans by membership count
+const ClanSearchSortByMembershipCount = "membershipCount"
+
+// ClanSearchSortByCreatedAt orders the searched clans by creation date
+const ClanSearchSortByCreatedAt = "createdAt"
+
+// ClanSearchSortByName orders the searched clans by name
+const ClanSearchSortByName = "name"
+
+// ClanSearchMetadataPrefix prefixes the metadata keys clans can be filtered and sorted by
+const ClanSearchMetadataPrefix = "metadata."
+
+// ClanSearchFilters restricts the clans returned by a search
+// Nil fields do not filter the clans.
+type ClanSearchFilters struct {
+	AllowApplication   *bool
+	AutoJoin           *bool
+	MinMembershipCount *int
+	MaxMembershipCount *int
+	// Metadata has the values the clans metadata keys must be equal to
+	Metadata map[string]interface{}
+	// MinMetadata and MaxMetadata have the bounds of the clans numeric metadata keys
+	MinMetadata map[string]float64
+	MaxMetadata map[string]float64
+}
+
+// ClanSearchSort orders the clans returned by a search
+// Ties are broken by the clan id.
+type ClanSearchSort struct {
+	// Field is one of the ClanSearchSortBy constants or metadata.<key>
+	Field     string
+	Ascending bool
+}
+
+// ClanSearchHit is a clan found by a search
+type ClanSearchHit struct {
+	Clan
+	// Highlight has the fragments of each clan field that matched the search term
+	Highlight map[string][]string
+}
+
+// GetClanSearchMetadataKeys returns the clan metadata keys declared in the game clanSearchMetadataKeys
+func (g *Game) GetClanSearchMetadataKeys() []string {
+	keys := []string{}
+	for _, key := range strings.Split(g.ClanSearchMetadataKeys, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// IsClanSearchMetadataKey returns whether the game clans can be filtered and sorted by the metadata key
+func (g *Game) IsClanSearchMetadataKey(key string) bool {
+	for _, k := range g.GetClanSearchMetadataKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty returns whether the filters do not filter any clan
+func (f *ClanSearchFilters) Empty() bool {
+	return f == nil || (f.AllowApplication == nil && f.AutoJoin == nil &&
+		f.MinMembershipCount == nil && f.MaxMembershipCount == nil &&
+		len(f.Metadata) == 0 && len(f.MinMetadata) == 0 && len(f.MaxMetadata) == 0)
+}
+
+// SetHasFreeSlots restricts the clans to the ones with (or without) room for another member in the game
+func (f *ClanSearchFilters) SetHasFreeSlots(game *Game, hasFreeSlots bool) {
+	if hasFreeSlots {
+		maxMembershipCount := game.MaxMembers - 1
+		if f.MaxMembershipCount == nil || *f.MaxMembershipCount > maxMembershipCount {
+			f.MaxMembershipCount = &maxMembershipCount
+		}
+		return
+	}
+	minMembershipCount := game.MaxMembers
+	if f.MinMembershipCount == nil || *f.MinMembershipCount < minMembershipCount {
+		f.MinMembershipCount = &minMembershipCount
+	}
+}
+
+// Validate returns an error if the filters use metadata keys the game clans can't be searched by
+func (f *ClanSearchFilters) Validate(game *Game) error {
+	for _, key := range f.metadataKeys() {
+		if !game.IsClanSearchMetadataKey(key) {
+			return &InvalidArgumentError{
+				Param:    ClanSearchMetadataPrefix + key,
+				Expected: "a key in the game clanSearchMetadataKeys",
+				Got:      key,
+			}
+		}
+	}
+	return nil
+}
+
+func (f *ClanSearchFilters) metadataKeys() []string {
+	keys := []string{}
+	if f == nil {
+		return keys
+	}
+	for key := range f.Metadata {
+		keys = append(keys, key)
+	}
+	for key := range f.MinMetadata {
+		keys = append(keys, key)
+	}
+	for key := range f.MaxMetadata {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Match returns whether the clan passes the filters
+func (f *ClanSearchFilters) Match(clan *Clan) bool {
+	if f == nil {
+		return true
+	}
+	if f.AllowApplication != nil && clan.AllowApplication != *f.AllowApplication {
+		return false
+	}
+	if f.AutoJoin != nil && clan.AutoJoin != *f.AutoJoin {
+		return false
+	}
+	if f.MinMembershipCount != nil && clan.MembershipCount < *f.MinMembershipCount {
+		return false
+	}
+	if f.MaxMembershipCount != nil && clan.MembershipCount > *f.MaxMembershipCount {
+		return false
+	}
+	for key, value := range f.Metadata {
+		clanValue, ok := clan.Metadata[key]
+		if !ok || fmt.Sprint(clanValue) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	for key, min := range f.MinMetadata {
+		clanValue, ok := toFloat64(clan.Metadata[key])
+		if !ok || clanValue < min {
+			return false
+		}
+	}
+	for key, max := range f.MaxMetadata {
+		clanValue, ok := toFloat64(clan.Metadata[key])
+		if !ok || clanValue > max {
+			return false
+		}
+	}
+	return true
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// mongoMatch returns the MongoDB query of the filters
+// Equality filters match both the string and the numeric values of the metadata keys.
+func (f *ClanSearchFilters) mongoMatch() bson.M {
+	match := bson.M{}
+	if f == nil {
+		return match
+	}
+	if f.AllowApplication != nil {
+		match["allowApplication"] = *f.AllowApplication
+	}
+	if f.AutoJoin != nil {
+		match["autoJoin"] = *f.AutoJoin
+	}
+	membershipCount := bson.M{}
+	if f.MinMembershipCount != nil {
+		membershipCount["$gte"] = *f.MinMembershipCount
+	}
+	if f.MaxMembershipCount != nil {
+		membershipCount["$lte"] = *f.MaxMembershipCount
+	}
+	if len(membershipCount) > 0 {
+		match["membershipCount"] = membershipCount
+	}
+	for key, value := range f.Metadata {
+		values := []interface{}{value}
+		if number, ok := toFloat64(value); ok {
+			values = append(values, number)
+		}
+		match[ClanSearchMetadataPrefix+key] = bson.M{"$in": values}
+	}
+	for key, min := range f.MinMetadata {
+		metadataCondition(match, key)["$gte"] = min
+	}
+	for key, max := range f.MaxMetadata {
+		metadataCondition(match, key)["$lte"] = max
+	}
+	return match
+}
+
+func metadataCondition(match bson.M, key string) bson.M {
+	field := ClanSearchMetadataPrefix + key
+	condition, ok := match[field].(bson.M)
+	if !ok {
+		condition = bson.M{}
+		match[field] = condition
+	}
+	return condition
+}
+
+// Relevance returns whether the clans are ordered by how well they match the term
+func (s *ClanSearchSort) Relevance() bool {
+	return s == nil || s.Field == "" || s.Field == ClanSearchSortByRelevance
+}
+
+// Validate returns an error if the game clans can't be sorted by the field
+func (s *ClanSearchSort) Validate(game *Game) error {
+	if s.Relevance() {
+		return nil
+	}
+	switch s.Field {
+	case ClanSearchSortByMembershipCount, ClanSearchSortByCreatedAt, ClanSearchSortByName:
+		return nil
+	}
+	if strings.HasPrefix(s.Field, ClanSearchMetadataPrefix) &&
+		game.IsClanSearchMetadataKey(strings.TrimPrefix(s.Field, ClanSearchMetadataPrefix)) {
+		return nil
+	}
+	return &InvalidArgumentError{
+		Param: "sort",
+		Expected: fmt.Sprintf(
+			"'%s', '%s', '%s', '%s' or metadata.<key> for a key in the game clanSearchMetadataKeys",
+			ClanSearchSortByRelevance, ClanSearchSortByMembershipCount, ClanSearchSortByCreatedAt, ClanSearchSortByName,
+		),
+		Got: s.Field,
+	}
+}
+
+// value returns the value of the sort field in the clan
+func (s *ClanSearchSort) value(clan *Clan) interface{} {
+	switch s.Field {
+	case ClanSearchSortByMembershipCount:
+		return clan.MembershipCount
+	case ClanSearchSortByCreatedAt:
+		return clan.CreatedAt
+	case ClanSearchSortByName:
+		return clan.Name
+	}
+	return clan.Metadata[strings.TrimPrefix(s.Field, ClanSearchMetadataPrefix)]
+}
+
+// mongoAfterCursor returns the MongoDB query of the clans after the cursor in the sort order
+func (s *ClanSearchSort) mongoAfterCursor(cursor *ClansCursor) bson.M {
+	operator := "$lt"
+	if s.Ascending {
+		operator = "$gt"
+	}
+	return bson.M{"$or": []bson.M{
+		{s.Field: bson.M{operator: cursor.SortValue}},
+		{s.Field: cursor.SortValue, "id": bson.M{"$gt": cursor.ID}},
+	}}
+}
+
+func (s *ClanSearchSort) direction() int {
+	if s.Ascending {
+		return 1
+	}
+	return -1
+}
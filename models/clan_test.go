@@ -436,6 +436,28 @@ var _ = Describe("Clan Model", func() {
 				Expect(dbClan.OwnerID).To(Equal(clan.OwnerID))
 			})
 
+			It("Should increment the Clan version with UpdateClan", func() {
+				player, clans, err := GetTestClans(testDb, "", "", 1)
+				Expect(err).NotTo(HaveOccurred())
+				clan := clans[0]
+				Expect(clan.Version).To(BeEquivalentTo(1))
+
+				err = CheckClanVersion(testDb, clan.GameID, clan.PublicID, 1)
+				Expect(err).NotTo(HaveOccurred())
+
+				updClan, err := UpdateClan(
+					testDb, clan.GameID, clan.PublicID, clan.Name, player.PublicID,
+					map[string]interface{}{"x": "1"}, clan.AllowApplication, clan.AutoJoin,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updClan.Version).To(BeEquivalentTo(2))
+
+				err = CheckClanVersion(testDb, clan.GameID, clan.PublicID, 1)
+				Expect(err).To(BeAssignableToTypeOf(&VersionMismatchError{}))
+				err = CheckClanVersion(testDb, clan.GameID, "unknown-clan", 1)
+				Expect(err).To(BeAssignableToTypeOf(&ModelNotFoundError{}))
+			})
+
 			It("Should not update a Clan if player is not the clan owner with UpdateClan", func() {
 				_, clans, err := GetTestClans(testDb, "", "", 1)
 				Expect(err).NotTo(HaveOccurred())
@@ -578,6 +600,85 @@ var _ = Describe("Clan Model", func() {
 			})
 		})
 
+		Describe("Disband Clan", func() {
+			It("Should disband the clan and end its memberships if clan owner", func() {
+				_, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 2, 0, 0, 1, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				disbandedClan, dbOwner, members, err := DisbandClan(testDb, clan.GameID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(disbandedClan.ID).To(Equal(clan.ID))
+				Expect(disbandedClan.DeletedAt).To(BeNumerically(">", util.NowMilli()-1000))
+				Expect(dbOwner.ID).To(Equal(owner.ID))
+				Expect(members).To(HaveLen(2))
+				memberIDs := []int64{members[0].Player.ID, members[1].Player.ID}
+				Expect(memberIDs).To(ConsistOf(players[0].ID, players[1].ID))
+				Expect(members[0].Level).To(Equal("Member"))
+
+				_, err = GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Clan was not found with id: %s", clan.PublicID)))
+
+				dbClan, err := GetClanByID(testDb, clan.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.MembershipCount).To(Equal(0))
+
+				for _, membership := range memberships {
+					dbMembership, err := GetMembershipByID(testDb, membership.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dbMembership.Approved).To(BeFalse())
+					Expect(dbMembership.DeletedBy).To(Equal(owner.ID))
+					Expect(dbMembership.DeletedAt).To(BeNumerically(">", util.NowMilli()-1000))
+				}
+
+				for _, player := range players {
+					dbPlayer, err := GetPlayerByID(testDb, player.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dbPlayer.MembershipCount).To(Equal(0))
+				}
+
+				dbPlayer, err := GetPlayerByID(testDb, owner.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbPlayer.OwnershipCount).To(Equal(0))
+			})
+
+			It("Should disband the clan on behalf of the game", func() {
+				_, clan, _, _, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, members, err := AdminDisbandClan(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(members).To(HaveLen(1))
+
+				_, err = GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("Should not disband the clan without a requestor", func() {
+				_, clan, _, _, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, err = DisbandClan(testDb, clan.GameID, clan.PublicID, "")
+				Expect(err).To(BeAssignableToTypeOf(&ForbiddenError{}))
+
+				_, err = GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should not disband the clan if requestor is not the owner", func() {
+				_, clan, _, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, err = DisbandClan(testDb, clan.GameID, clan.PublicID, players[0].PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s doesn't own clan %s. GameId: %s", players[0].PublicID, clan.PublicID, clan.GameID)))
+
+				dbClan, err := GetClanByPublicID(testDb, clan.GameID, clan.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.MembershipCount).To(Equal(clan.MembershipCount))
+			})
+		})
+
 		Describe("Transfer Clan Ownership", func() {
 			Describe("Should transfer the Clan ownership with TransferClanOwnership if clan owner", func() {
 				It("And first clan owner and next owner memberhip exists", func() {
@@ -747,6 +848,46 @@ var _ = Describe("Clan Model", func() {
 			})
 		})
 
+		Describe("Get Clans Page", func() {
+			It("Should iterate through all clans ordered by creation date", func() {
+				player, _, err := GetTestClans(testDb, "", "", 5)
+				Expect(err).NotTo(HaveOccurred())
+
+				var allClans []Clan
+				var cursor *ClansCursor
+				for i := 0; i < 3; i++ {
+					var clans []Clan
+					clans, cursor, err = GetClansPage(testDb, player.GameID, cursor, 2)
+					Expect(err).NotTo(HaveOccurred())
+					allClans = append(allClans, clans...)
+					if cursor == nil {
+						break
+					}
+
+					cursor, err = DecodeClansCursor(cursor.Encode())
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(cursor).To(BeNil())
+				Expect(allClans).To(HaveLen(5))
+
+				publicIDs := map[string]bool{}
+				for i, clan := range allClans {
+					publicIDs[clan.PublicID] = true
+					if i > 0 {
+						Expect(clan.CreatedAt).To(BeNumerically(">=", allClans[i-1].CreatedAt))
+					}
+				}
+				Expect(publicIDs).To(HaveLen(5))
+			})
+
+			It("Should fail when cursor is invalid", func() {
+				cursor, err := DecodeClansCursor("invalid cursor")
+				Expect(cursor).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Invalid argument for parameter 'cursor'"))
+			})
+		})
+
 		Describe("Get Clan Members", func() {
 			It("Should get clan player ids", func() {
 				gameID := uuid.NewV4().String()
@@ -1097,6 +1238,70 @@ var _ = Describe("Clan Model", func() {
 				Eventually(func() ([]Clan, error) { return SearchClan(testDb, testMongo, player.GameID, "💩clán", 10, lib.SearchMethodText) }).Should(HaveLen(10))
 			})
 
+			It("Should iterate through the search results with a cursor", func() {
+				err := testing.CreateClanNameTextIndexInMongo(GetTestMongo, player.GameID)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(func() ([]Clan, error) {
+					return SearchClan(testDb, testMongo, player.GameID, "SEARCH", 0, 10, lib.SearchMethodText)
+				}).Should(HaveLen(10))
+
+				publicIDs := map[string]bool{}
+				var cursor *ClansCursor
+				for i := 0; i < 3; i++ {
+					var clans []Clan
+					clans, cursor, err = SearchClanPage(testDb, testMongo, player.GameID, "SEARCH", nil, nil, cursor, 0, 4, lib.SearchMethodText)
+					Expect(err).NotTo(HaveOccurred())
+					for _, clan := range clans {
+						publicIDs[clan.PublicID] = true
+					}
+				}
+				Expect(cursor).To(BeNil())
+				Expect(publicIDs).To(HaveLen(10))
+			})
+
+			It("Should filter and sort the search results", func() {
+				err := testing.CreateClanNameTextIndexInMongo(GetTestMongo, player.GameID)
+				Expect(err).NotTo(HaveOccurred())
+				for i, clan := range realClans {
+					clan.MembershipCount = i + 1
+					clan.AllowApplication = i%2 == 0
+					clan.Metadata = map[string]interface{}{"trophies": i * 10}
+					_, err = testDb.Update(clan)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				allowApplication := true
+				minMembershipCount := 3
+				filters := &ClanSearchFilters{
+					AllowApplication:   &allowApplication,
+					MinMembershipCount: &minMembershipCount,
+					MaxMetadata:        map[string]float64{"trophies": 60},
+				}
+				sort := &ClanSearchSort{Field: ClanSearchSortByMembershipCount}
+				search := func(cursor *ClansCursor, pageSize int64) ([]int, *ClansCursor) {
+					clans, nextCursor, err := SearchClanPage(
+						testDb, testMongo, player.GameID, "SEARCH", filters, sort, cursor, 0, pageSize, lib.SearchMethodText,
+					)
+					Expect(err).NotTo(HaveOccurred())
+					counts := []int{}
+					for _, clan := range clans {
+						counts = append(counts, clan.MembershipCount)
+					}
+					return counts, nextCursor
+				}
+
+				Eventually(func() []int {
+					counts, _ := search(nil, 10)
+					return counts
+				}).Should(Equal([]int{7, 5, 3}))
+
+				counts, cursor := search(nil, 2)
+				Expect(counts).To(Equal([]int{7, 5}))
+				Expect(cursor).NotTo(BeNil())
+				counts, _ = search(cursor, 2)
+				Expect(counts).To(Equal([]int{3}))
+			})
+
 			It("Should return clan by full public ID as search term", func() {
 				searchClanID := realClans[0].PublicID
 				Eventually(func() ([]Clan, error) { return SearchClan(testDb, testMongo, player.GameID, searchClanID, 10, lib.SearchMethodText) }).Should(HaveLen(1))
@@ -0,0 +1,174 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import "fmt"
+
+// CounterChange is a denormalized counter fixed by a reconciliation
+type CounterChange struct {
+	ID       int64  `db:"id"`
+	PublicID string `db:"public_id"`
+	Previous int    `db:"previous"`
+	Current  int    `db:"current"`
+}
+
+// Serialize returns a JSON with the counter change details
+func (c *CounterChange) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"publicID": c.PublicID,
+		"previous": c.Previous,
+		"current":  c.Current,
+	}
+}
+
+// CountsReconciliation has the counters of a game fixed by a reconciliation
+type CountsReconciliation struct {
+	GameID                 string
+	Clans                  int
+	Players                int
+	ClanMembershipCounts   []*CounterChange
+	PlayerMembershipCounts []*CounterChange
+	PlayerOwnershipCounts  []*CounterChange
+}
+
+// NewCountsReconciliation returns an empty reconciliation of the game counters
+func NewCountsReconciliation(gameID string) *CountsReconciliation {
+	return &CountsReconciliation{
+		GameID:                 gameID,
+		ClanMembershipCounts:   []*CounterChange{},
+		PlayerMembershipCounts: []*CounterChange{},
+		PlayerOwnershipCounts:  []*CounterChange{},
+	}
+}
+
+// Serialize returns a JSON with the reconciliation details
+func (r *CountsReconciliation) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":                 r.GameID,
+		"clans":                  r.Clans,
+		"players":                r.Players,
+		"clanMembershipCounts":   serializeCounterChanges(r.ClanMembershipCounts),
+		"playerMembershipCounts": serializeCounterChanges(r.PlayerMembershipCounts),
+		"playerOwnershipCounts":  serializeCounterChanges(r.PlayerOwnershipCounts),
+	}
+}
+
+func serializeCounterChanges(changes []*CounterChange) []map[string]interface{} {
+	serialized := make([]map[string]interface{}, len(changes))
+	for i, change := range changes {
+		serialized[i] = change.Serialize()
+	}
+	return serialized
+}
+
+// getReconciliationBatch returns the number of rows of the game in the batch of up to limit rows
+// with id greater than afterID, and the id of the last one
+func getReconciliationBatch(db DB, table, gameID string, afterID int64, limit int) (int, int64, error) {
+	var batch struct {
+		Count  int   `db:"count"`
+		LastID int64 `db:"last_id"`
+	}
+	query := fmt.Sprintf(`
+	SELECT COUNT(*) AS count, COALESCE(MAX(id), 0) AS last_id
+	FROM (
+		SELECT id FROM %s WHERE game_id=$1 AND id > $2 ORDER BY id LIMIT $3
+	) batch`, table)
+	err := db.SelectOne(&batch, query, gameID, afterID, limit)
+	return batch.Count, batch.LastID, err
+}
+
+// ReconcileClanMembershipCounts recomputes the membership count of up to limit clans of the game
+// with id greater than afterID, fixing the wrong ones
+// Search and top clans updates are enqueued for the fixed clans. Returns the fixed counters, the
+// number of clans in the batch and the id of the last one, to be used as afterID of the next batch.
+func ReconcileClanMembershipCounts(db DB, gameID string, afterID int64, limit int) ([]*CounterChange, int, int64, error) {
+	count, lastID, err := getReconciliationBatch(db, "clans", gameID, afterID, limit)
+	if err != nil || count == 0 {
+		return []*CounterChange{}, count, lastID, err
+	}
+
+	changes := []*CounterChange{}
+	query := `
+	UPDATE clans SET membership_count=counts.current
+	FROM (
+		SELECT c.id, c.membership_count AS previous, (
+			SELECT COUNT(*) + 1
+			FROM memberships m
+			WHERE
+				m.clan_id = c.id AND m.deleted_at = 0 AND m.approved = true AND
+				m.denied = false AND m.banned = false
+		) AS current
+		FROM clans c
+		WHERE c.game_id=$1 AND c.id > $2 AND c.id <= $3 AND c.deleted_at=0
+	) AS counts
+	WHERE clans.id=counts.id AND counts.previous <> counts.current
+	RETURNING clans.id, clans.public_id, counts.previous, counts.current
+	`
+	if _, err = db.Select(&changes, query, gameID, afterID, lastID); err != nil {
+		return nil, count, lastID, err
+	}
+
+	for _, change := range changes {
+		if err = updateClanIntoIndexes(db, change.ID); err != nil {
+			return nil, count, lastID, err
+		}
+	}
+	return changes, count, lastID, nil
+}
+
+// ReconcilePlayerCounts recomputes the membership and ownership counts of up to limit players of
+// the game with id greater than afterID, fixing the wrong ones
+// Returns the fixed membership and ownership counters, the number of players in the batch and the
+// id of the last one, to be used as afterID of the next batch.
+func ReconcilePlayerCounts(db DB, gameID string, afterID int64, limit int) ([]*CounterChange, []*CounterChange, int, int64, error) {
+	count, lastID, err := getReconciliationBatch(db, "players", gameID, afterID, limit)
+	if err != nil || count == 0 {
+		return []*CounterChange{}, []*CounterChange{}, count, lastID, err
+	}
+
+	membershipChanges := []*CounterChange{}
+	query := `
+	UPDATE players SET membership_count=counts.current
+	FROM (
+		SELECT p.id, p.membership_count AS previous, (
+			SELECT COUNT(*)
+			FROM memberships m
+			WHERE
+				m.player_id = p.id AND m.deleted_at = 0 AND m.approved = true AND
+				m.denied = false AND m.banned = false
+		) AS current
+		FROM players p
+		WHERE p.game_id=$1 AND p.id > $2 AND p.id <= $3
+	) AS counts
+	WHERE players.id=counts.id AND counts.previous <> counts.current
+	RETURNING players.id, players.public_id, counts.previous, counts.current
+	`
+	if _, err = db.Select(&membershipChanges, query, gameID, afterID, lastID); err != nil {
+		return nil, nil, count, lastID, err
+	}
+
+	ownershipChanges := []*CounterChange{}
+	query = `
+	UPDATE players SET ownership_count=counts.current
+	FROM (
+		SELECT p.id, p.ownership_count AS previous, (
+			SELECT COUNT(*)
+			FROM clans c
+			WHERE c.owner_id = p.id AND c.deleted_at = 0
+		) AS current
+		FROM players p
+		WHERE p.game_id=$1 AND p.id > $2 AND p.id <= $3
+	) AS counts
+	WHERE players.id=counts.id AND counts.previous <> counts.current
+	RETURNING players.id, players.public_id, counts.previous, counts.current
+	`
+	if _, err = db.Select(&ownershipChanges, query, gameID, afterID, lastID); err != nil {
+		return nil, nil, count, lastID, err
+	}
+	return membershipChanges, ownershipChanges, count, lastID, nil
+}
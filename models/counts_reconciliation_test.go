@@ -0,0 +1,114 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Counts Reconciliation Model", func() {
+	var testDb DB
+	var game *Game
+	var clan *Clan
+	var owner *Player
+	var players []*Player
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ConfigureAndStartGoWorkers()
+		Expect(err).NotTo(HaveOccurred())
+
+		game, clan, owner, players, _, err = GetClanWithMemberships(testDb, 2, 1, 0, 1, "", uuid.NewV4().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = testDb.Exec("UPDATE clans SET membership_count=10 WHERE id=$1", clan.ID)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = testDb.Exec("UPDATE players SET membership_count=5, ownership_count=0 WHERE id=$1", owner.ID)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = testDb.Exec("UPDATE players SET membership_count=0 WHERE id=$1", players[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	findChange := func(changes []*CounterChange, publicID string) *CounterChange {
+		for _, change := range changes {
+			if change.PublicID == publicID {
+				return change
+			}
+		}
+		return nil
+	}
+
+	Describe("Reconcile Clan Membership Counts", func() {
+		It("Should fix the wrong clan membership counts", func() {
+			changes, count, lastID, err := ReconcileClanMembershipCounts(testDb, game.PublicID, 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+			Expect(lastID).To(Equal(clan.ID))
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Serialize()).To(Equal(map[string]interface{}{
+				"publicID": clan.PublicID,
+				"previous": 10,
+				"current":  3,
+			}))
+
+			dbClan, err := GetClanByID(testDb, clan.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbClan.MembershipCount).To(Equal(3))
+		})
+
+		It("Should return an empty batch after the last clan", func() {
+			changes, count, _, err := ReconcileClanMembershipCounts(testDb, game.PublicID, clan.ID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	Describe("Reconcile Player Counts", func() {
+		It("Should fix the wrong player membership and ownership counts", func() {
+			membershipChanges, ownershipChanges, count, _, err := ReconcilePlayerCounts(testDb, game.PublicID, 0, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(len(players) + 1))
+
+			change := findChange(membershipChanges, owner.PublicID)
+			Expect(change).NotTo(BeNil())
+			Expect(change.Previous).To(Equal(5))
+			Expect(change.Current).To(Equal(0))
+			change = findChange(membershipChanges, players[0].PublicID)
+			Expect(change).NotTo(BeNil())
+			Expect(change.Previous).To(Equal(0))
+			Expect(change.Current).To(Equal(1))
+			change = findChange(ownershipChanges, owner.PublicID)
+			Expect(change).NotTo(BeNil())
+			Expect(change.Previous).To(Equal(0))
+			Expect(change.Current).To(Equal(1))
+
+			dbOwner, err := GetPlayerByID(testDb, owner.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbOwner.MembershipCount).To(Equal(0))
+			Expect(dbOwner.OwnershipCount).To(Equal(1))
+		})
+
+		It("Should read the players in batches", func() {
+			_, _, count, lastID, err := ReconcilePlayerCounts(testDb, game.PublicID, 0, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			_, _, count, _, err = ReconcilePlayerCounts(testDb, game.PublicID, lastID, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(len(players) - 1))
+		})
+	})
+})
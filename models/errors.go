@@ -175,6 +175,26 @@ func (e *MustWaitMembershipCooldownError) Error() string {
 	return fmt.Sprintf("Player %s must wait %d seconds before creating a membership in clan %s.", e.PlayerID, e.Time, e.ClanID)
 }
 
+// PlayerIsBannedFromClanError identifies that the player was banned from the clan
+type PlayerIsBannedFromClanError struct {
+	PlayerID string
+	ClanID   string
+}
+
+func (e *PlayerIsBannedFromClanError) Error() string {
+	return fmt.Sprintf("Player %s is banned from clan %s.", e.PlayerID, e.ClanID)
+}
+
+// PlayerIsNotBannedFromClanError identifies that the player is not banned from the clan
+type PlayerIsNotBannedFromClanError struct {
+	PlayerID string
+	ClanID   string
+}
+
+func (e *PlayerIsNotBannedFromClanError) Error() string {
+	return fmt.Sprintf("Player %s is not banned from clan %s.", e.PlayerID, e.ClanID)
+}
+
 // CouldNotFindAllClansError identifies that one or more of the requested clans do not exist
 type CouldNotFindAllClansError struct {
 	gameID  string
@@ -213,3 +233,82 @@ type InvalidCastToGorpSQLExecutorError struct {
 func (e *InvalidCastToGorpSQLExecutorError) Error() string {
 	return "Invalid cast to gorp.SqlExecutor"
 }
+
+// InvalidHookFilterError identifies that a hook filter expression could not be parsed
+type InvalidHookFilterError struct {
+	Filter string
+	Reason string
+}
+
+func (e *InvalidHookFilterError) Error() string {
+	return fmt.Sprintf("Hook filter %q is invalid: %s", e.Filter, e.Reason)
+}
+
+// PreHookDeniedError identifies that a pre hook denied the action
+type PreHookDeniedError struct {
+	Reason string
+}
+
+func (e *PreHookDeniedError) Error() string {
+	if e.Reason == "" {
+		return "Action denied by pre hook"
+	}
+	return e.Reason
+}
+
+// PreHookFailedError identifies that a fail-closed pre hook could not approve the action
+type PreHookFailedError struct {
+	HookPublicID string
+	Reason       string
+}
+
+func (e *PreHookFailedError) Error() string {
+	return fmt.Sprintf("Pre hook %s failed: %s", e.HookPublicID, e.Reason)
+}
+
+// InvalidAPIKeyScopeError identifies that an api key scope does not exist
+type InvalidAPIKeyScopeError struct {
+	Scope string
+}
+
+func (e *InvalidAPIKeyScopeError) Error() string {
+	return fmt.Sprintf("API key scope %q is invalid, it must be read-only, player-write or admin", e.Scope)
+}
+
+// APIKeyRevokedError identifies that an api key was revoked
+type APIKeyRevokedError struct {
+	PublicID string
+}
+
+func (e *APIKeyRevokedError) Error() string {
+	return fmt.Sprintf("API key %s was revoked", e.PublicID)
+}
+
+// InvalidPlayerTokenConfigError identifies that a player token config is invalid
+type InvalidPlayerTokenConfigError struct {
+	Reason string
+}
+
+func (e *InvalidPlayerTokenConfigError) Error() string {
+	return fmt.Sprintf("Player token config is invalid: %s", e.Reason)
+}
+
+// InvalidPlayerTokenError identifies that a player token could not be verified
+type InvalidPlayerTokenError struct {
+	Reason string
+}
+
+func (e *InvalidPlayerTokenError) Error() string {
+	return fmt.Sprintf("Player token is invalid: %s", e.Reason)
+}
+
+// VersionMismatchError identifies that a model was changed since the version the request expected
+type VersionMismatchError struct {
+	Type     string
+	ID       interface{}
+	Expected int64
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("%s %v is not at version %d", e.Type, e.ID, e.Expected)
+}
@@ -47,7 +47,7 @@ var GameFactory = factory.NewFactory(
 
 // HookFactory is responsible for constructing event hook instances
 var HookFactory = factory.NewFactory(
-	&Hook{EventType: GameUpdatedHook, URL: "http://test/game-created"},
+	&Hook{EventType: GameUpdatedHook, URL: "http://test/game-created", Kind: HookKindPost},
 )
 
 // CreateHookFactory is responsible for creating a test hook instance with the associated game
@@ -452,7 +452,7 @@ func GetTestClanWithRandomPublicIDAndName(db DB, gameID string, ownerID int64) (
 	if err != nil {
 		return nil, err
 	}
-	err = clan.UpdateClanIntoMongoDB()
+	err = clan.UpdateClanIntoMongoDB(db)
 	if err != nil {
 		return nil, err
 	}
@@ -471,7 +471,7 @@ func GetTestClanWithName(db DB, gameID, name string, ownerID int64) (*Clan, erro
 	if err != nil {
 		return nil, err
 	}
-	err = clan.UpdateClanIntoMongoDB()
+	err = clan.UpdateClanIntoMongoDB(db)
 	if err != nil {
 		return nil, err
 	}
@@ -516,7 +516,7 @@ func GetTestClans(db DB, gameID string, publicIDTemplate string, numberOfClans i
 		if err != nil {
 			return nil, nil, err
 		}
-		err = clan.UpdateClanIntoMongoDB()
+		err = clan.UpdateClanIntoMongoDB(db)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -42,6 +42,9 @@ type Game struct {
 	MaxPendingInvites                              int                    `db:"max_pending_invites"`
 	ClanUpdateMetadataFieldsHookTriggerWhitelist   string                 `db:"clan_metadata_fields_whitelist"`
 	PlayerUpdateMetadataFieldsHookTriggerWhitelist string                 `db:"player_metadata_fields_whitelist"`
+	TopClansMetadataDimensions                     string                 `db:"top_clans_metadata_dimensions"`
+	ClanSearchMetadataKeys                         string                 `db:"clan_search_metadata_keys"`
+	Version                                        int64                  `db:"version"`
 }
 
 // PreInsert populates fields before inserting a new game
@@ -64,6 +67,38 @@ func (g *Game) PreUpdate(s gorp.SqlExecutor) error {
 	return nil
 }
 
+// Serialize returns a JSON with game details
+func (g *Game) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"publicID":                      g.PublicID,
+		"name":                          g.Name,
+		"membershipLevels":              g.MembershipLevels,
+		"metadata":                      g.Metadata,
+		"minMembershipLevel":            g.MinMembershipLevel,
+		"maxMembershipLevel":            g.MaxMembershipLevel,
+		"minLevelToAcceptApplication":   g.MinLevelToAcceptApplication,
+		"minLevelToCreateInvitation":    g.MinLevelToCreateInvitation,
+		"minLevelToRemoveMember":        g.MinLevelToRemoveMember,
+		"minLevelOffsetToRemoveMember":  g.MinLevelOffsetToRemoveMember,
+		"minLevelOffsetToPromoteMember": g.MinLevelOffsetToPromoteMember,
+		"minLevelOffsetToDemoteMember":  g.MinLevelOffsetToDemoteMember,
+		"maxMembers":                    g.MaxMembers,
+		"maxClansPerPlayer":             g.MaxClansPerPlayer,
+		"cooldownAfterDeny":             g.CooldownAfterDeny,
+		"cooldownAfterDelete":           g.CooldownAfterDelete,
+		"cooldownBeforeApply":           g.CooldownBeforeApply,
+		"cooldownBeforeInvite":          g.CooldownBeforeInvite,
+		"maxPendingInvites":             g.MaxPendingInvites,
+		"clanHookFieldsWhitelist":       g.ClanUpdateMetadataFieldsHookTriggerWhitelist,
+		"playerHookFieldsWhitelist":     g.PlayerUpdateMetadataFieldsHookTriggerWhitelist,
+		"topClansMetadataDimensions":    g.TopClansMetadataDimensions,
+		"clanSearchMetadataKeys":        g.ClanSearchMetadataKeys,
+		"createdAt":                     g.CreatedAt,
+		"updatedAt":                     g.UpdatedAt,
+		"version":                       g.Version,
+	}
+}
+
 // GetGameByID returns a game by id
 func GetGameByID(db DB, id int) (*Game, error) {
 	obj, err := db.Get(Game{}, id)
@@ -112,6 +147,8 @@ func CreateGame(
 	cooldownBeforeInvite, maxPendingInvites int, upsert bool,
 	clanUpdateMetadataFieldsHookTriggerWhitelist string,
 	playerUpdateMetadataFieldsHookTriggerWhitelist string,
+	topClansMetadataDimensions string,
+	clanSearchMetadataKeys string,
 ) (*Game, error) {
 	levelsJSON, err := json.Marshal(levels)
 	if err != nil {
@@ -150,10 +187,12 @@ func CreateGame(
 				max_pending_invites,
 				clan_metadata_fields_whitelist,
 				player_metadata_fields_whitelist,
+				top_clans_metadata_dimensions,
+				clan_search_metadata_keys,
 				created_at,
 				updated_at
 			)
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $22)%s`
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $24)%s`
 	onConflict := ` ON CONFLICT (public_id)
 			DO UPDATE set
 				name=$2,
@@ -176,7 +215,10 @@ func CreateGame(
 				max_pending_invites=$19,
 				clan_metadata_fields_whitelist=$20,
 				player_metadata_fields_whitelist=$21,
-				updated_at=$22
+				top_clans_metadata_dimensions=$22,
+				clan_search_metadata_keys=$23,
+				updated_at=$24,
+				version=games.version+1
 			WHERE games.public_id=$1`
 
 	if upsert {
@@ -207,7 +249,9 @@ func CreateGame(
 		maxPendingInvites,                              // $19
 		clanUpdateMetadataFieldsHookTriggerWhitelist,   // $20
 		playerUpdateMetadataFieldsHookTriggerWhitelist, // $21
-		util.NowMilli(),                                // $22
+		topClansMetadataDimensions,                     // $22
+		clanSearchMetadataKeys,                         // $23
+		util.NowMilli(),                                // $24
 	)
 	if err != nil {
 		return nil, err
@@ -215,6 +259,22 @@ func CreateGame(
 	return GetGameByPublicID(db, publicID)
 }
 
+// CheckGameVersion locks the game and returns a VersionMismatchError if it is not at the given version
+func CheckGameVersion(db DB, publicID string, version int64) error {
+	current, err := db.SelectInt("SELECT version FROM games WHERE public_id=$1 FOR UPDATE", publicID)
+	if err != nil {
+		return err
+	}
+	// versions start at 1, so SelectInt only returns 0 when the game does not exist
+	if current == 0 {
+		return &ModelNotFoundError{"Game", publicID}
+	}
+	if current != version {
+		return &VersionMismatchError{"Game", publicID, version}
+	}
+	return nil
+}
+
 // UpdateGame updates an existing game
 func UpdateGame(
 	db DB, publicID, name string, levels, metadata map[string]interface{},
@@ -223,6 +283,8 @@ func UpdateGame(
 	cooldownBeforeApply, cooldownBeforeInvite, maxPendingInvites int,
 	clanUpdateMetadataFieldsHookTriggerWhitelist string,
 	playerUpdateMetadataFieldsHookTriggerWhitelist string,
+	topClansMetadataDimensions string,
+	clanSearchMetadataKeys string,
 ) (*Game, error) {
 	return CreateGame(
 		db, publicID, name, levels, metadata, minLevelAccept, minLevelCreate,
@@ -231,5 +293,7 @@ func UpdateGame(
 		cooldownBeforeInvite, maxPendingInvites, true,
 		clanUpdateMetadataFieldsHookTriggerWhitelist,
 		playerUpdateMetadataFieldsHookTriggerWhitelist,
+		topClansMetadataDimensions,
+		clanSearchMetadataKeys,
 	)
 }
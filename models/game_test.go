@@ -151,6 +151,8 @@ var _ = Describe("Game Model", func() {
 			maxPendingInvites := 20
 			clanUpdateMetadataFieldsHookTriggerWhitelist := "x"
 			playerUpdateMetadataFieldsHookTriggerWhitelist := "y,z"
+			topClansMetadataDimensions := "trophies"
+			clanSearchMetadataKeys := "region"
 
 			game, err := CreateGame(
 				testDb,
@@ -174,6 +176,8 @@ var _ = Describe("Game Model", func() {
 				false,
 				clanUpdateMetadataFieldsHookTriggerWhitelist,
 				playerUpdateMetadataFieldsHookTriggerWhitelist,
+				topClansMetadataDimensions,
+				clanSearchMetadataKeys,
 			)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(game.ID).NotTo(Equal(0))
@@ -198,6 +202,8 @@ var _ = Describe("Game Model", func() {
 			Expect(dbGame.MaxPendingInvites).To(Equal(maxPendingInvites))
 			Expect(dbGame.ClanUpdateMetadataFieldsHookTriggerWhitelist).To(Equal("x"))
 			Expect(dbGame.PlayerUpdateMetadataFieldsHookTriggerWhitelist).To(Equal("y,z"))
+			Expect(dbGame.TopClansMetadataDimensions).To(Equal("trophies"))
+			Expect(dbGame.ClanSearchMetadataKeys).To(Equal("region"))
 
 			for k, v := range dbGame.MembershipLevels {
 				Expect(v.(float64)).To(BeEquivalentTo(game.MembershipLevels[k]))
@@ -219,7 +225,7 @@ var _ = Describe("Game Model", func() {
 				map[string]interface{}{"Member": 1, "Elder": 2, "CoLeader": 3},
 				map[string]interface{}{"x": "a"},
 				5, 4, 7, 1, 1, 1, 100, 1, 5, 15, 8, 25, 20,
-				"x", "y,z",
+				"x", "y,z", "trophies", "region",
 			)
 
 			Expect(err).NotTo(HaveOccurred())
@@ -261,7 +267,7 @@ var _ = Describe("Game Model", func() {
 				map[string]interface{}{"Member": 1, "Elder": 2, "CoLeader": 3},
 				map[string]interface{}{"x": "a"},
 				5, 4, 7, 1, 1, 1, 100, 1, 10, 30, 8, 25, 20,
-				"x", "y,z",
+				"x", "y,z", "trophies", "region",
 			)
 
 			Expect(err).NotTo(HaveOccurred())
@@ -304,7 +310,7 @@ var _ = Describe("Game Model", func() {
 				map[string]interface{}{"Member": 1, "Elder": 2, "CoLeader": 3},
 				map[string]interface{}{"x": "a"},
 				5, 4, 7, 1, 1, 0, 100, 1, 0, 0, 8, 25, 20,
-				"x", "y,z",
+				"x", "y,z", "trophies", "region",
 			)
 
 			Expect(err).To(HaveOccurred())
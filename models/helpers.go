@@ -80,11 +80,16 @@ func InitDb(host string, user string, port int, sslmode string, dbName string, p
 		TypeConverter: util.TypeConverter{},
 	}
 
-	dbmap.AddTableWithName(Game{}, "games").SetKeys(true, "ID")
-	dbmap.AddTableWithName(Player{}, "players").SetKeys(true, "ID")
-	dbmap.AddTableWithName(Clan{}, "clans").SetKeys(true, "ID")
+	dbmap.AddTableWithName(Game{}, "games").SetKeys(true, "ID").SetVersionCol("Version")
+	dbmap.AddTableWithName(Player{}, "players").SetKeys(true, "ID").SetVersionCol("Version")
+	dbmap.AddTableWithName(Clan{}, "clans").SetKeys(true, "ID").SetVersionCol("Version")
 	dbmap.AddTableWithName(Membership{}, "memberships").SetKeys(true, "ID")
 	dbmap.AddTableWithName(Hook{}, "hooks").SetKeys(true, "ID")
+	dbmap.AddTableWithName(HookDeadLetter{}, "hook_dead_letters").SetKeys(true, "ID")
+	dbmap.AddTableWithName(AuditEvent{}, "audit_events").SetKeys(true, "ID")
+	dbmap.AddTableWithName(OutboxMessage{}, "outbox").SetKeys(true, "ID")
+	dbmap.AddTableWithName(APIKey{}, "api_keys").SetKeys(true, "ID")
+	dbmap.AddTableWithName(PlayerTokenConfig{}, "player_token_configs").SetKeys(true, "ID")
 
 	// dbmap.TraceOn("[gorp]", log.New(os.Stdout, "KHAN:", log.Lmicroseconds))
 	return egorp.New(dbmap, dbName), nil
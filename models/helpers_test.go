@@ -10,6 +10,8 @@ package models_test
 import (
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	workers "github.com/jrallison/go-workers"
 	uuid "github.com/satori/go.uuid"
@@ -19,6 +21,7 @@ import (
 	"github.com/topfreegames/khan/mongo"
 	"github.com/topfreegames/khan/queues"
 	kt "github.com/topfreegames/khan/testing"
+	"github.com/uber-go/zap"
 )
 
 // GetTestDB returns a connection to the test database
@@ -86,5 +89,19 @@ func ConfigureAndStartGoWorkers() error {
 	mongoWorker := models.NewMongoWorker(l, config)
 	workers.Process(queues.KhanMongoQueue, mongoWorker.PerformUpdateMongo, workerCount)
 	workers.Start()
+	return startOutboxRelay(l)
+}
+
+var outboxRelayOnce sync.Once
+
+// startOutboxRelay publishes the jobs written to the test database outbox
+func startOutboxRelay(l zap.Logger) error {
+	db, err := models.GetDB("localhost", "khan_test", 5433, "disable", "khan_test", "")
+	if err != nil {
+		return err
+	}
+	outboxRelayOnce.Do(func() {
+		go models.NewOutboxRelay(l, db, 100, 10*time.Millisecond, time.Hour).Run()
+	})
 	return nil
 }
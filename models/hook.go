@@ -53,17 +53,68 @@ const (
 
 	//MembershipLeftHook happens when a player leaves a clan
 	MembershipLeftHook = 12
+
+	//MembershipBannedHook happens when a player is banned from a clan
+	MembershipBannedHook = 13
+
+	//MembershipUnbannedHook happens when a player is unbanned from a clan
+	MembershipUnbannedHook = 14
+
+	//ClanDisbandedHook happens when a clan is disbanded by its owner or by the game
+	ClanDisbandedHook = 15
+)
+
+const (
+	//HookKindPost hooks are notified asynchronously after the action happens
+	HookKindPost = "post"
+
+	//HookKindPre hooks are called synchronously before the action happens and may deny or change it
+	HookKindPre = "pre"
 )
 
 // Hook identifies a webhook for a given event
 type Hook struct {
-	ID        int    `db:"id"`
-	GameID    string `db:"game_id"`
-	PublicID  string `db:"public_id"`
-	EventType int    `db:"event_type"`
-	URL       string `db:"url"`
-	CreatedAt int64  `db:"created_at"`
-	UpdatedAt int64  `db:"updated_at"`
+	ID            int    `db:"id"`
+	GameID        string `db:"game_id"`
+	PublicID      string `db:"public_id"`
+	EventType     int    `db:"event_type"`
+	URL           string `db:"url"`
+	Secret        string `db:"secret"`
+	Filter        string `db:"filter"`
+	BatchSize     int    `db:"batch_size"`
+	BatchInterval int    `db:"batch_interval"`
+	Kind          string `db:"kind"`
+	FailOpen      bool   `db:"fail_open"`
+	CreatedAt     int64  `db:"created_at"`
+	UpdatedAt     int64  `db:"updated_at"`
+
+	// compiledFilter is the parsed Filter, or the error parsing it, set when the hook is loaded
+	compiledFilter    *HookFilter `db:"-"`
+	compiledFilterErr error       `db:"-"`
+}
+
+// compileFilter parses the hook filter once, so events are matched without parsing it again
+func (h *Hook) compileFilter() {
+	h.compiledFilter, h.compiledFilterErr = nil, nil
+	if h.Filter != "" {
+		h.compiledFilter, h.compiledFilterErr = ParseHookFilter(h.Filter)
+	}
+}
+
+// MatchFilter returns whether the payload satisfies the hook filter, if any
+// Hooks not loaded by GetHooksByGameID or CreateHook have their filter parsed on every call.
+func (h *Hook) MatchFilter(payload map[string]interface{}) (bool, error) {
+	if h.Filter == "" {
+		return true, nil
+	}
+	filter, err := h.compiledFilter, h.compiledFilterErr
+	if filter == nil && err == nil {
+		filter, err = ParseHookFilter(h.Filter)
+	}
+	if err != nil {
+		return false, err
+	}
+	return filter.Match(payload), nil
 }
 
 // PreInsert populates fields before inserting a new hook
@@ -79,6 +130,34 @@ func (h *Hook) PreUpdate(s gorp.SqlExecutor) error {
 	return nil
 }
 
+// Batched returns whether the events of this hook are delivered in batches
+func (h *Hook) Batched() bool {
+	return h.BatchInterval > 0 || h.BatchSize > 0
+}
+
+// Pre returns whether the hook is called before the action happens
+func (h *Hook) Pre() bool {
+	return h.Kind == HookKindPre
+}
+
+// Serialize returns a JSON with hook details
+func (h *Hook) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":        h.GameID,
+		"publicID":      h.PublicID,
+		"type":          h.EventType,
+		"hookURL":       h.URL,
+		"signed":        h.Secret != "",
+		"filter":        h.Filter,
+		"batchSize":     h.BatchSize,
+		"batchInterval": h.BatchInterval,
+		"kind":          h.Kind,
+		"failOpen":      h.FailOpen,
+		"createdAt":     h.CreatedAt,
+		"updatedAt":     h.UpdatedAt,
+	}
+}
+
 // GetHookByID returns a hook by id
 func GetHookByID(db DB, id int) (*Hook, error) {
 	obj, err := db.Get(Hook{}, id)
@@ -112,24 +191,60 @@ func GetHookByDetails(db DB, gameID string, eventType int, hookURL string) *Hook
 }
 
 // CreateHook returns a newly created event hook
-func CreateHook(db DB, gameID string, eventType int, url string) (*Hook, error) {
+// If the hook already exists its signing secret, filter, batching, kind and failure policy are updated instead.
+func CreateHook(
+	db DB, gameID string, eventType int, url string,
+	secret string, filter string, batchSize, batchInterval int,
+	kind string, failOpen bool,
+) (*Hook, error) {
+	if kind == "" {
+		kind = HookKindPost
+	}
+	if filter != "" {
+		if _, err := ParseHookFilter(filter); err != nil {
+			return nil, err
+		}
+	}
+
 	hook := GetHookByDetails(db, gameID, eventType, url)
 
 	if hook != nil {
+		if hook.Secret != secret || hook.Filter != filter ||
+			hook.BatchSize != batchSize || hook.BatchInterval != batchInterval ||
+			hook.Kind != kind || hook.FailOpen != failOpen {
+			hook.Secret = secret
+			hook.Filter = filter
+			hook.BatchSize = batchSize
+			hook.BatchInterval = batchInterval
+			hook.Kind = kind
+			hook.FailOpen = failOpen
+			_, err := db.Update(hook)
+			if err != nil {
+				return nil, err
+			}
+		}
+		hook.compileFilter()
 		return hook, nil
 	}
 
 	publicID := uuid.NewV4().String()
 	hook = &Hook{
-		GameID:    gameID,
-		PublicID:  publicID,
-		EventType: eventType,
-		URL:       url,
+		GameID:        gameID,
+		PublicID:      publicID,
+		EventType:     eventType,
+		URL:           url,
+		Secret:        secret,
+		Filter:        filter,
+		BatchSize:     batchSize,
+		BatchInterval: batchInterval,
+		Kind:          kind,
+		FailOpen:      failOpen,
 	}
 	err := db.Insert(hook)
 	if err != nil {
 		return nil, err
 	}
+	hook.compileFilter()
 	return hook, nil
 }
 
@@ -152,3 +267,16 @@ func GetAllHooks(db DB) ([]*Hook, error) {
 	}
 	return hooks, nil
 }
+
+// GetHooksByGameID returns all the hooks registered for the given game
+func GetHooksByGameID(db DB, gameID string) ([]*Hook, error) {
+	var hooks []*Hook
+	_, err := db.Select(&hooks, "SELECT * FROM hooks WHERE game_id=$1 ORDER BY event_type, created_at", gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range hooks {
+		hook.compileFilter()
+	}
+	return hooks, nil
+}
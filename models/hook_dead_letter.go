@@ -0,0 +1,118 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/khan/util"
+
+	"github.com/go-gorp/gorp"
+)
+
+// HookDeadLetter stores a webhook delivery that failed after exhausting all its retries
+type HookDeadLetter struct {
+	ID           int64                  `db:"id"`
+	GameID       string                 `db:"game_id"`
+	PublicID     string                 `db:"public_id"`
+	HookPublicID string                 `db:"hook_public_id"`
+	EventType    int                    `db:"event_type"`
+	URL          string                 `db:"url"`
+	Payload      map[string]interface{} `db:"payload"`
+	Batched      bool                   `db:"batched"`
+	LastError    string                 `db:"last_error"`
+	Attempts     int                    `db:"attempts"`
+	CreatedAt    int64                  `db:"created_at"`
+	UpdatedAt    int64                  `db:"updated_at"`
+}
+
+// PreInsert populates fields before inserting a new hook dead letter
+func (h *HookDeadLetter) PreInsert(s gorp.SqlExecutor) error {
+	h.CreatedAt = util.NowMilli()
+	h.UpdatedAt = h.CreatedAt
+	return nil
+}
+
+// PreUpdate populates fields before updating a hook dead letter
+func (h *HookDeadLetter) PreUpdate(s gorp.SqlExecutor) error {
+	h.UpdatedAt = util.NowMilli()
+	return nil
+}
+
+// Serialize returns a JSON with hook dead letter details
+func (h *HookDeadLetter) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":       h.GameID,
+		"publicID":     h.PublicID,
+		"hookPublicID": h.HookPublicID,
+		"type":         h.EventType,
+		"hookURL":      h.URL,
+		"payload":      h.Payload,
+		"batched":      h.Batched,
+		"lastError":    h.LastError,
+		"attempts":     h.Attempts,
+		"createdAt":    h.CreatedAt,
+	}
+}
+
+// GetHookDeadLetterByPublicID returns a hook dead letter by game id and public id
+func GetHookDeadLetterByPublicID(db DB, gameID, publicID string) (*HookDeadLetter, error) {
+	var deadLetter HookDeadLetter
+	err := db.SelectOne(&deadLetter, "SELECT * FROM hook_dead_letters WHERE game_id=$1 AND public_id=$2", gameID, publicID)
+	if err != nil {
+		return nil, &ModelNotFoundError{"HookDeadLetter", publicID}
+	}
+	return &deadLetter, nil
+}
+
+// GetHookDeadLettersByGameID returns the most recent hook dead letters for the given game
+func GetHookDeadLettersByGameID(db DB, gameID string, limit int) ([]*HookDeadLetter, error) {
+	var deadLetters []*HookDeadLetter
+	_, err := db.Select(
+		&deadLetters,
+		"SELECT * FROM hook_dead_letters WHERE game_id=$1 ORDER BY created_at DESC LIMIT $2",
+		gameID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+// CreateHookDeadLetter stores a failed hook delivery
+// Batched deliveries keep their events in the "events" key of the payload.
+func CreateHookDeadLetter(
+	db DB, gameID, hookPublicID string, eventType int, url string,
+	payload map[string]interface{}, batched bool, lastError string, attempts int,
+) (*HookDeadLetter, error) {
+	deadLetter := &HookDeadLetter{
+		GameID:       gameID,
+		PublicID:     uuid.NewV4().String(),
+		HookPublicID: hookPublicID,
+		EventType:    eventType,
+		URL:          url,
+		Payload:      payload,
+		Batched:      batched,
+		LastError:    lastError,
+		Attempts:     attempts,
+	}
+	err := db.Insert(deadLetter)
+	if err != nil {
+		return nil, err
+	}
+	return deadLetter, nil
+}
+
+// RemoveHookDeadLetter removes a hook dead letter by public ID
+func RemoveHookDeadLetter(db DB, gameID, publicID string) error {
+	deadLetter, err := GetHookDeadLetterByPublicID(db, gameID, publicID)
+	if err != nil {
+		return err
+	}
+	_, err = db.Delete(deadLetter)
+	return err
+}
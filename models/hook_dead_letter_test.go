@@ -0,0 +1,101 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Hook Dead Letter Model", func() {
+	var testDb DB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Create Hook Dead Letter", func() {
+		It("Should create a new hook dead letter", func() {
+			hook, err := CreateHookFactory(testDb, "", ClanCreatedHook, "http://test/created")
+			Expect(err).NotTo(HaveOccurred())
+
+			deadLetter, err := CreateHookDeadLetter(
+				testDb, hook.GameID, hook.PublicID, hook.EventType, hook.URL,
+				map[string]interface{}{"publicID": "clan-id"}, false, "connection refused", 3,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetter.ID).NotTo(BeEquivalentTo(0))
+			Expect(deadLetter.PublicID).NotTo(BeEmpty())
+
+			dbDeadLetter, err := GetHookDeadLetterByPublicID(testDb, hook.GameID, deadLetter.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbDeadLetter.HookPublicID).To(Equal(hook.PublicID))
+			Expect(dbDeadLetter.EventType).To(Equal(ClanCreatedHook))
+			Expect(dbDeadLetter.URL).To(Equal(hook.URL))
+			Expect(dbDeadLetter.Payload["publicID"]).To(Equal("clan-id"))
+			Expect(dbDeadLetter.LastError).To(Equal("connection refused"))
+			Expect(dbDeadLetter.Attempts).To(Equal(3))
+			Expect(dbDeadLetter.CreatedAt).To(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("Get Hook Dead Letter By Public ID", func() {
+		It("Should fail if hook dead letter does not exist", func() {
+			publicID := uuid.NewV4().String()
+			_, err := GetHookDeadLetterByPublicID(testDb, "game-id", publicID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("HookDeadLetter was not found with id: " + publicID))
+		})
+	})
+
+	Describe("Get Hook Dead Letters By Game ID", func() {
+		It("Should get the dead letters for the given game", func() {
+			hook, err := CreateHookFactory(testDb, "", ClanCreatedHook, "http://test/created")
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				_, err = CreateHookDeadLetter(
+					testDb, hook.GameID, hook.PublicID, hook.EventType, hook.URL,
+					map[string]interface{}{}, false, "status 500", 6,
+				)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			deadLetters, err := GetHookDeadLettersByGameID(testDb, hook.GameID, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(2))
+			for _, deadLetter := range deadLetters {
+				Expect(deadLetter.GameID).To(Equal(hook.GameID))
+			}
+		})
+	})
+
+	Describe("Remove Hook Dead Letter", func() {
+		It("Should remove a hook dead letter", func() {
+			hook, err := CreateHookFactory(testDb, "", ClanCreatedHook, "http://test/created")
+			Expect(err).NotTo(HaveOccurred())
+
+			deadLetter, err := CreateHookDeadLetter(
+				testDb, hook.GameID, hook.PublicID, hook.EventType, hook.URL,
+				map[string]interface{}{}, false, "status 500", 6,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = RemoveHookDeadLetter(testDb, hook.GameID, deadLetter.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+
+			number, err := testDb.SelectInt("select count(*) from hook_dead_letters where id=$1", deadLetter.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(number).To(BeEquivalentTo(0))
+		})
+	})
+})
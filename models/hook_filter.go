@@ -0,0 +1,467 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HookFilter is a parsed hook filter expression that can be matched against event payloads.
+//
+// Expressions compare payload fields, addressed by dotted paths, with literals:
+//
+//	clan.metadata.region == "EU"
+//	membership.level in ["CoLeader", "Elder"] && clan.membershipCount >= 10
+//
+// Supported operators are ==, !=, >, >=, <, <=, in and not in. Comparisons can
+// be combined with && (and), || (or), ! (not) and parentheses. Literals are
+// double quoted strings, numbers, true, false, null and lists of those.
+// Missing payload fields are null.
+type HookFilter struct {
+	Expression string
+	root       hookFilterNode
+}
+
+// ParseHookFilter parses a hook filter expression
+func ParseHookFilter(expression string) (*HookFilter, error) {
+	tokens, err := tokenizeHookFilter(expression)
+	if err != nil {
+		return nil, &InvalidHookFilterError{expression, err.Error()}
+	}
+
+	p := &hookFilterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %s", p.peek().value)
+	}
+	if err != nil {
+		return nil, &InvalidHookFilterError{expression, err.Error()}
+	}
+
+	return &HookFilter{Expression: expression, root: root}, nil
+}
+
+// Match returns whether the payload satisfies the filter
+func (f *HookFilter) Match(payload map[string]interface{}) bool {
+	return f.root.match(payload)
+}
+
+type hookFilterNode interface {
+	match(payload map[string]interface{}) bool
+}
+
+type hookFilterAnd struct{ left, right hookFilterNode }
+
+func (n *hookFilterAnd) match(payload map[string]interface{}) bool {
+	return n.left.match(payload) && n.right.match(payload)
+}
+
+type hookFilterOr struct{ left, right hookFilterNode }
+
+func (n *hookFilterOr) match(payload map[string]interface{}) bool {
+	return n.left.match(payload) || n.right.match(payload)
+}
+
+type hookFilterNot struct{ node hookFilterNode }
+
+func (n *hookFilterNot) match(payload map[string]interface{}) bool {
+	return !n.node.match(payload)
+}
+
+type hookFilterComparison struct {
+	path     []string
+	operator string
+	value    interface{}
+}
+
+func (n *hookFilterComparison) match(payload map[string]interface{}) bool {
+	value := lookupHookFilterPath(payload, n.path)
+
+	switch n.operator {
+	case "==":
+		return hookFilterEqual(value, n.value)
+	case "!=":
+		return !hookFilterEqual(value, n.value)
+	case "in", "not in":
+		found := false
+		for _, item := range n.value.([]interface{}) {
+			if hookFilterEqual(value, item) {
+				found = true
+				break
+			}
+		}
+		return found == (n.operator == "in")
+	}
+
+	cmp, ok := hookFilterCompare(value, n.value)
+	if !ok {
+		return false
+	}
+	switch n.operator {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func lookupHookFilterPath(payload map[string]interface{}, path []string) interface{} {
+	var current interface{} = payload
+	for _, key := range path {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = obj[key]
+	}
+	return current
+}
+
+func hookFilterNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func hookFilterEqual(a, b interface{}) bool {
+	if af, ok := hookFilterNumber(a); ok {
+		bf, ok := hookFilterNumber(b)
+		return ok && af == bf
+	}
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	}
+	return false
+}
+
+func hookFilterCompare(a, b interface{}) (int, bool) {
+	if af, ok := hookFilterNumber(a); ok {
+		bf, ok := hookFilterNumber(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(as, bs), true
+	}
+	return 0, false
+}
+
+const (
+	hookFilterTokenIdent = iota
+	hookFilterTokenString
+	hookFilterTokenNumber
+	hookFilterTokenSymbol
+)
+
+type hookFilterToken struct {
+	kind  int
+	value string
+}
+
+func isHookFilterIdentChar(c byte, first bool) bool {
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return true
+	}
+	return !first && (c == '.' || (c >= '0' && c <= '9'))
+}
+
+func tokenizeHookFilter(expression string) ([]hookFilterToken, error) {
+	var tokens []hookFilterToken
+	i := 0
+	for i < len(expression) {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(expression) && expression[j] != '"' {
+				if expression[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expression) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			value, err := strconv.Unquote(expression[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d", i)
+			}
+			tokens = append(tokens, hookFilterToken{hookFilterTokenString, value})
+			i = j + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(expression) && (expression[j] == '.' || (expression[j] >= '0' && expression[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, hookFilterToken{hookFilterTokenNumber, expression[i:j]})
+			i = j
+		case isHookFilterIdentChar(c, true):
+			j := i + 1
+			for j < len(expression) && isHookFilterIdentChar(expression[j], false) {
+				j++
+			}
+			tokens = append(tokens, hookFilterToken{hookFilterTokenIdent, expression[i:j]})
+			i = j
+		default:
+			symbol := ""
+			for _, s := range []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(expression[i:], s) {
+					symbol = s
+					break
+				}
+			}
+			if symbol == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, hookFilterToken{hookFilterTokenSymbol, symbol})
+			i += len(symbol)
+		}
+	}
+	return tokens, nil
+}
+
+type hookFilterParser struct {
+	tokens []hookFilterToken
+	pos    int
+}
+
+func (p *hookFilterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *hookFilterParser) peek() hookFilterToken {
+	if p.done() {
+		return hookFilterToken{hookFilterTokenSymbol, "end of expression"}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is one of the given symbols or keywords
+func (p *hookFilterParser) accept(values ...string) bool {
+	if p.done() {
+		return false
+	}
+	token := p.tokens[p.pos]
+	if token.kind != hookFilterTokenSymbol && token.kind != hookFilterTokenIdent {
+		return false
+	}
+	for _, value := range values {
+		if token.value == value {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *hookFilterParser) expect(value string) error {
+	if !p.accept(value) {
+		return fmt.Errorf("expected %s but got %s", value, p.peek().value)
+	}
+	return nil
+}
+
+func (p *hookFilterParser) parseOr() (hookFilterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &hookFilterOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *hookFilterParser) parseAnd() (hookFilterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &hookFilterAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *hookFilterParser) parseUnary() (hookFilterNode, error) {
+	if p.accept("!", "not") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &hookFilterNot{node}, nil
+	}
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *hookFilterParser) parseComparison() (hookFilterNode, error) {
+	token := p.peek()
+	if p.done() || token.kind != hookFilterTokenIdent || isHookFilterKeyword(token.value) {
+		return nil, fmt.Errorf("expected a payload field but got %s", token.value)
+	}
+	p.pos++
+	path := strings.Split(token.value, ".")
+	for _, key := range path {
+		if key == "" {
+			return nil, fmt.Errorf("invalid payload field %s", token.value)
+		}
+	}
+
+	var operator string
+	switch {
+	case p.accept("==", "!=", ">=", "<=", ">", "<"):
+		operator = p.tokens[p.pos-1].value
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if operator != "==" && operator != "!=" {
+			if _, ok := hookFilterNumber(value); !ok {
+				if _, ok := value.(string); !ok {
+					return nil, fmt.Errorf("%s requires a number or a string", operator)
+				}
+			}
+		}
+		return &hookFilterComparison{path, operator, value}, nil
+	case p.accept("in"):
+		operator = "in"
+	case p.accept("not"):
+		if err := p.expect("in"); err != nil {
+			return nil, err
+		}
+		operator = "not in"
+	default:
+		return nil, fmt.Errorf("expected an operator but got %s", p.peek().value)
+	}
+
+	list, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	return &hookFilterComparison{path, operator, list}, nil
+}
+
+func (p *hookFilterParser) parseList() ([]interface{}, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	list := []interface{}{}
+	if p.accept("]") {
+		return list, nil
+	}
+	for {
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+		if p.accept("]") {
+			return list, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *hookFilterParser) parseLiteral() (interface{}, error) {
+	token := p.peek()
+	if p.done() {
+		return nil, fmt.Errorf("expected a value but got %s", token.value)
+	}
+	p.pos++
+
+	switch token.kind {
+	case hookFilterTokenString:
+		return token.value, nil
+	case hookFilterTokenNumber:
+		value, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", token.value)
+		}
+		return value, nil
+	case hookFilterTokenIdent:
+		switch token.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("expected a value but got %s", token.value)
+}
+
+func isHookFilterKeyword(value string) bool {
+	switch value {
+	case "and", "or", "not", "in", "true", "false", "null":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Hook Filter", func() {
+	payload := map[string]interface{}{
+		"gameID": "game-id",
+		"clan": map[string]interface{}{
+			"publicID":         "clan-id",
+			"membershipCount":  json.Number("12"),
+			"allowApplication": true,
+			"metadata": map[string]interface{}{
+				"region": "EU",
+			},
+		},
+		"membership": map[string]interface{}{
+			"level": "CoLeader",
+		},
+	}
+
+	match := func(expression string) bool {
+		filter, err := ParseHookFilter(expression)
+		Expect(err).NotTo(HaveOccurred())
+		return filter.Match(payload)
+	}
+
+	Describe("Parse Hook Filter", func() {
+		It("Should fail for invalid expressions", func() {
+			for _, expression := range []string{
+				"",
+				`clan.metadata.region`,
+				`clan.metadata.region ==`,
+				`clan.metadata.region == "EU`,
+				`clan.metadata.region = "EU"`,
+				`clan.metadata.region in "EU"`,
+				`clan.metadata.region in ["EU"`,
+				`clan.membershipCount > true`,
+				`(clan.metadata.region == "EU"`,
+				`clan.metadata.region == "EU" membership.level == "Member"`,
+				`"EU" == clan.metadata.region`,
+			} {
+				_, err := ParseHookFilter(expression)
+				Expect(err).To(HaveOccurred(), expression)
+				Expect(err.Error()).To(ContainSubstring("Hook filter"))
+			}
+		})
+	})
+
+	Describe("Match", func() {
+		It("Should compare strings", func() {
+			Expect(match(`clan.metadata.region == "EU"`)).To(BeTrue())
+			Expect(match(`clan.metadata.region == "US"`)).To(BeFalse())
+			Expect(match(`clan.metadata.region != "US"`)).To(BeTrue())
+		})
+
+		It("Should compare numbers", func() {
+			Expect(match(`clan.membershipCount == 12`)).To(BeTrue())
+			Expect(match(`clan.membershipCount >= 10`)).To(BeTrue())
+			Expect(match(`clan.membershipCount < 10`)).To(BeFalse())
+		})
+
+		It("Should compare booleans", func() {
+			Expect(match(`clan.allowApplication == true`)).To(BeTrue())
+			Expect(match(`clan.allowApplication == false`)).To(BeFalse())
+		})
+
+		It("Should check lists", func() {
+			Expect(match(`membership.level in ["CoLeader", "Elder"]`)).To(BeTrue())
+			Expect(match(`membership.level in ["Member"]`)).To(BeFalse())
+			Expect(match(`membership.level not in ["Member"]`)).To(BeTrue())
+			Expect(match(`membership.level in []`)).To(BeFalse())
+		})
+
+		It("Should treat missing fields as null", func() {
+			Expect(match(`clan.metadata.country == null`)).To(BeTrue())
+			Expect(match(`player.publicID == "player-id"`)).To(BeFalse())
+			Expect(match(`clan.publicID.invalid != null`)).To(BeFalse())
+			Expect(match(`clan.metadata.country > 1`)).To(BeFalse())
+		})
+
+		It("Should combine expressions", func() {
+			Expect(match(`clan.metadata.region == "EU" && membership.level == "CoLeader"`)).To(BeTrue())
+			Expect(match(`clan.metadata.region == "EU" and membership.level == "Member"`)).To(BeFalse())
+			Expect(match(`clan.metadata.region == "US" || membership.level == "CoLeader"`)).To(BeTrue())
+			Expect(match(`!(clan.metadata.region == "US" or membership.level == "Member")`)).To(BeTrue())
+			Expect(match(`not clan.metadata.region == "EU"`)).To(BeFalse())
+			Expect(match(`clan.metadata.region == "US" || clan.metadata.region == "EU" && membership.level == "Member"`)).To(BeFalse())
+		})
+	})
+})
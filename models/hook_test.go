@@ -102,6 +102,12 @@ var _ = Describe("Hook Model", func() {
 					game.PublicID,
 					GameUpdatedHook,
 					"http://test/created",
+					"",
+					"",
+					0,
+					0,
+					"",
+					false,
 				)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hook.ID).NotTo(BeEquivalentTo(0))
@@ -123,6 +129,12 @@ var _ = Describe("Hook Model", func() {
 					gameID,
 					GameUpdatedHook,
 					"http://test/created",
+					"",
+					"",
+					0,
+					0,
+					"",
+					false,
 				)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hook2.ID == hook.ID).To(BeTrue())
@@ -135,6 +147,175 @@ var _ = Describe("Hook Model", func() {
 				Expect(dbHook.URL).To(Equal(hook.URL))
 			})
 
+			It("Should create a new Hook with a signing secret", func() {
+				gameID := uuid.NewV4().String()
+				_, err := CreateHookFactory(testDb, gameID, GameUpdatedHook, "http://test/other")
+				Expect(err).NotTo(HaveOccurred())
+
+				hook, err := CreateHook(
+					testDb,
+					gameID,
+					GameUpdatedHook,
+					"http://test/signed",
+					"my-secret",
+					"",
+					0,
+					0,
+					"",
+					false,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Secret).To(Equal("my-secret"))
+				Expect(dbHook.Serialize()["signed"]).To(BeTrue())
+			})
+
+			It("Create same Hook updates its signing secret", func() {
+				gameID := uuid.NewV4().String()
+				hook, err := CreateHookFactory(testDb, gameID, GameUpdatedHook, "http://test/created")
+				Expect(err).NotTo(HaveOccurred())
+
+				hook2, err := CreateHook(
+					testDb,
+					gameID,
+					GameUpdatedHook,
+					"http://test/created",
+					"new-secret",
+					"",
+					0,
+					0,
+					"",
+					false,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook2.ID).To(Equal(hook.ID))
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Secret).To(Equal("new-secret"))
+			})
+
+			It("Should create a new Hook with a filter", func() {
+				gameID := uuid.NewV4().String()
+				_, err := CreateHookFactory(testDb, gameID, ClanUpdatedHook, "http://test/other")
+				Expect(err).NotTo(HaveOccurred())
+
+				hook, err := CreateHook(
+					testDb,
+					gameID,
+					ClanUpdatedHook,
+					"http://test/filtered",
+					"",
+					`clan.metadata.region == "EU"`,
+					0,
+					0,
+					"",
+					false,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Filter).To(Equal(`clan.metadata.region == "EU"`))
+				Expect(dbHook.Serialize()["filter"]).To(Equal(`clan.metadata.region == "EU"`))
+			})
+
+			It("Should not create a Hook with an invalid filter", func() {
+				hook, err := CreateHook(
+					testDb,
+					uuid.NewV4().String(),
+					ClanUpdatedHook,
+					"http://test/filtered",
+					"",
+					`clan.metadata.region ==`,
+					0,
+					0,
+					"",
+					false,
+				)
+				Expect(hook).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Hook filter"))
+			})
+
+			It("Should create a new Hook with batching", func() {
+				gameID := uuid.NewV4().String()
+				_, err := CreateHookFactory(testDb, gameID, PlayerUpdatedHook, "http://test/other")
+				Expect(err).NotTo(HaveOccurred())
+
+				hook, err := CreateHook(
+					testDb,
+					gameID,
+					PlayerUpdatedHook,
+					"http://test/batched",
+					"",
+					"",
+					100,
+					5000,
+					"",
+					false,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook.Batched()).To(BeTrue())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.BatchSize).To(Equal(100))
+				Expect(dbHook.BatchInterval).To(Equal(5000))
+			})
+
+			It("Should create a new fail-open pre Hook", func() {
+				gameID := uuid.NewV4().String()
+				_, err := CreateHookFactory(testDb, gameID, ClanCreatedHook, "http://test/other")
+				Expect(err).NotTo(HaveOccurred())
+
+				hook, err := CreateHook(
+					testDb,
+					gameID,
+					ClanCreatedHook,
+					"http://test/pre",
+					"",
+					"",
+					0,
+					0,
+					HookKindPre,
+					true,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook.Pre()).To(BeTrue())
+
+				dbHook, err := GetHookByID(testDb, hook.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbHook.Kind).To(Equal(HookKindPre))
+				Expect(dbHook.FailOpen).To(BeTrue())
+				Expect(dbHook.Serialize()["kind"]).To(Equal(HookKindPre))
+				Expect(dbHook.Serialize()["failOpen"]).To(BeTrue())
+			})
+
+			It("Should create a post Hook by default", func() {
+				gameID := uuid.NewV4().String()
+				hook, err := CreateHookFactory(testDb, gameID, ClanCreatedHook, "http://test/pre")
+				Expect(err).NotTo(HaveOccurred())
+
+				hook2, err := CreateHook(
+					testDb,
+					gameID,
+					ClanCreatedHook,
+					"http://test/pre",
+					"",
+					"",
+					0,
+					0,
+					"",
+					false,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hook2.ID).To(Equal(hook.ID))
+				Expect(hook2.Kind).To(Equal(HookKindPost))
+				Expect(hook2.Pre()).To(BeFalse())
+			})
 		})
 
 		Describe("Remove Hook", func() {
@@ -169,5 +350,63 @@ var _ = Describe("Hook Model", func() {
 			})
 		})
 
+		Describe("Get Hooks By Game ID", func() {
+			It("Should get only the hooks for the given game", func() {
+				gameID := uuid.NewV4().String()
+				_, err := GetTestHooks(testDb, gameID, 3)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherGameID := uuid.NewV4().String()
+				_, err = GetTestHooks(testDb, otherGameID, 2)
+				Expect(err).NotTo(HaveOccurred())
+
+				hooks, err := GetHooksByGameID(testDb, gameID)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hooks).To(HaveLen(6))
+				for _, hook := range hooks {
+					Expect(hook.GameID).To(Equal(gameID))
+				}
+			})
+
+			It("Should return no hooks for a game without hooks", func() {
+				hooks, err := GetHooksByGameID(testDb, uuid.NewV4().String())
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hooks).To(BeEmpty())
+			})
+
+			It("Should match payloads with the filters of the hooks", func() {
+				gameID := uuid.NewV4().String()
+				invalid, err := CreateHookFactory(testDb, gameID, ClanUpdatedHook, "http://test/invalid")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = testDb.Exec("UPDATE hooks SET filter=$1 WHERE id=$2", "clan.name ==", invalid.ID)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = CreateHook(testDb, gameID, ClanUpdatedHook, "http://test/all", "", "", 0, 0, "", false)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = CreateHook(testDb, gameID, ClanUpdatedHook, "http://test/eu", "", `clan.metadata.region == "EU"`, 0, 0, "", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				hooks, err := GetHooksByGameID(testDb, gameID)
+				Expect(err).NotTo(HaveOccurred())
+				hooksByURL := map[string]*Hook{}
+				for _, hook := range hooks {
+					hooksByURL[hook.URL] = hook
+				}
+
+				payload := map[string]interface{}{
+					"clan": map[string]interface{}{"metadata": map[string]interface{}{"region": "US"}},
+				}
+				matched, err := hooksByURL["http://test/all"].MatchFilter(payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matched).To(BeTrue())
+				matched, err = hooksByURL["http://test/eu"].MatchFilter(payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matched).To(BeFalse())
+				_, err = hooksByURL["http://test/invalid"].MatchFilter(payload)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 	})
 })
@@ -0,0 +1,236 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/topfreegames/extensions/mongo/interfaces"
+	"github.com/topfreegames/khan/es"
+	"github.com/topfreegames/khan/queues"
+	"gopkg.in/olivere/elastic.v5"
+)
+
+// index stores verified against postgres
+const (
+	IndexStoreMongo         = "mongo"
+	IndexStoreElasticsearch = "elasticsearch"
+)
+
+// indexVerifyBatchSize is the number of documents read at once from the index stores,
+// and of clans read at once from postgres to repair them
+const indexVerifyBatchSize = 1000
+
+// IndexDrift has the public IDs of the clans whose documents in an index store differ from postgres
+type IndexDrift struct {
+	GameID string
+	Store  string
+	// Missing clans are in postgres but not in the store
+	Missing []string
+	// Extra clans are in the store but not in postgres
+	Extra []string
+	// Stale clans have a different updatedAt in the store
+	Stale []string
+}
+
+// Empty returns whether the store is consistent with postgres
+func (d *IndexDrift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Stale) == 0
+}
+
+// Serialize returns a JSON with the index drift details
+func (d *IndexDrift) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"gameID":  d.GameID,
+		"store":   d.Store,
+		"missing": d.Missing,
+		"extra":   d.Extra,
+		"stale":   d.Stale,
+	}
+}
+
+// GetClanVersions returns the updatedAt of each clan in a given game, by public ID
+func GetClanVersions(db DB, gameID string) (map[string]int64, error) {
+	var rows []struct {
+		PublicID  string `db:"public_id"`
+		UpdatedAt int64  `db:"updated_at"`
+	}
+	_, err := db.Select(&rows, "SELECT public_id, updated_at FROM clans WHERE game_id=$1 AND deleted_at=0", gameID)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		versions[row.PublicID] = row.UpdatedAt
+	}
+	return versions, nil
+}
+
+// GetMongoDBClanVersions returns the updatedAt of each clan in the MongoDB collection, by public ID
+func GetMongoDBClanVersions(mongo interfaces.MongoDB, collection string) (map[string]int64, error) {
+	type document struct {
+		PublicID  string  `bson:"_id"`
+		UpdatedAt float64 `bson:"updatedAt"`
+	}
+	var res struct {
+		Cursor struct {
+			ID         int64      `bson:"id"`
+			FirstBatch []document `bson:"firstBatch"`
+			NextBatch  []document `bson:"nextBatch"`
+		} `bson:"cursor"`
+	}
+
+	versions := map[string]int64{}
+	cmd := bson.D{
+		{Name: "find", Value: collection},
+		{Name: "projection", Value: bson.M{"updatedAt": 1}},
+		{Name: "batchSize", Value: indexVerifyBatchSize},
+	}
+	for {
+		if err := mongo.Run(cmd, &res); err != nil {
+			return nil, err
+		}
+		for _, doc := range append(res.Cursor.FirstBatch, res.Cursor.NextBatch...) {
+			versions[doc.PublicID] = int64(doc.UpdatedAt)
+		}
+		if res.Cursor.ID == 0 {
+			return versions, nil
+		}
+		cmd = bson.D{
+			{Name: "getMore", Value: res.Cursor.ID},
+			{Name: "collection", Value: collection},
+			{Name: "batchSize", Value: indexVerifyBatchSize},
+		}
+		res.Cursor.FirstBatch = nil
+		res.Cursor.NextBatch = nil
+	}
+}
+
+// GetElasticSearchClanVersions returns the updatedAt of each clan in the elasticsearch index, by public ID
+// A missing index has no clans.
+func GetElasticSearchClanVersions(ctx context.Context, esClient *es.Client, index string) (map[string]int64, error) {
+	scroll := esClient.Client.Scroll(index).
+		Type("clan").
+		Size(indexVerifyBatchSize).
+		FetchSourceContext(elastic.NewFetchSourceContext(true).Include("publicId", "updatedAt"))
+	defer scroll.Clear(ctx)
+
+	versions := map[string]int64{}
+	for {
+		res, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return versions, nil
+		}
+		if elastic.IsNotFound(err) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range res.Hits.Hits {
+			var doc struct {
+				UpdatedAt int64 `json:"updatedAt"`
+			}
+			if hit.Source != nil {
+				if err := json.Unmarshal(*hit.Source, &doc); err != nil {
+					return nil, err
+				}
+			}
+			versions[hit.Id] = doc.UpdatedAt
+		}
+	}
+}
+
+// CompareClanVersions returns the drift of the store versions from the postgres versions
+func CompareClanVersions(gameID, store string, expected, actual map[string]int64) *IndexDrift {
+	drift := &IndexDrift{GameID: gameID, Store: store, Missing: []string{}, Extra: []string{}, Stale: []string{}}
+	for publicID, updatedAt := range expected {
+		storeUpdatedAt, ok := actual[publicID]
+		if !ok {
+			drift.Missing = append(drift.Missing, publicID)
+		} else if storeUpdatedAt != updatedAt {
+			drift.Stale = append(drift.Stale, publicID)
+		}
+	}
+	for publicID := range actual {
+		if _, ok := expected[publicID]; !ok {
+			drift.Extra = append(drift.Extra, publicID)
+		}
+	}
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.Extra)
+	sort.Strings(drift.Stale)
+	return drift
+}
+
+// RepairIndexDrift enqueues the jobs that write the missing and stale clans to the store and
+// delete the extra ones from it, returning the number of jobs enqueued
+// The clans are read from postgres in batches of indexVerifyBatchSize.
+func RepairIndexDrift(db DB, esClient *es.Client, drift *IndexDrift) (int, error) {
+	enqueued := 0
+	publicIDs := append(append([]string{}, drift.Missing...), drift.Stale...)
+	for start := 0; start < len(publicIDs); start += indexVerifyBatchSize {
+		end := start + indexVerifyBatchSize
+		if end > len(publicIDs) {
+			end = len(publicIDs)
+		}
+		// clans deleted since the drift was computed are skipped
+		clans, err := GetClansByPublicIDs(db, drift.GameID, publicIDs[start:end])
+		if _, ok := err.(*CouldNotFindAllClansError); err != nil && !ok {
+			return enqueued, err
+		}
+		for i := range clans {
+			if err := enqueueIndexRepair(db, esClient, drift, clans[i].PublicID, &clans[i]); err != nil {
+				return enqueued, err
+			}
+			enqueued++
+		}
+	}
+
+	for _, publicID := range drift.Extra {
+		if err := enqueueIndexRepair(db, esClient, drift, publicID, nil); err != nil {
+			return enqueued, err
+		}
+		enqueued++
+	}
+	return enqueued, nil
+}
+
+// enqueueIndexRepair enqueues the job that writes the clan to the store, or deletes it if clan is nil
+func enqueueIndexRepair(db DB, esClient *es.Client, drift *IndexDrift, publicID string, clan *Clan) error {
+	op := "delete"
+	var doc interface{} = map[string]interface{}{}
+	if clan != nil {
+		op = "update"
+		doc = clan.NewClanWithNamePrefixes()
+	}
+
+	if drift.Store == IndexStoreMongo {
+		return EnqueueOutbox(db, queues.KhanMongoQueue, map[string]interface{}{
+			"game":   drift.GameID,
+			"op":     op,
+			"clan":   doc,
+			"clanID": publicID,
+		})
+	}
+
+	if clan != nil {
+		op = "index"
+		doc = clan
+	}
+	return EnqueueOutbox(db, queues.KhanESQueue, map[string]interface{}{
+		"index":  esClient.GetIndexName(drift.GameID),
+		"op":     op,
+		"clan":   doc,
+		"clanID": publicID,
+	})
+}
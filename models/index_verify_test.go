@@ -0,0 +1,128 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/extensions/mongo/interfaces"
+	"github.com/topfreegames/khan/queues"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Index Verify Model", func() {
+	var testDb DB
+	var testMongo interfaces.MongoDB
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+		testMongo, err = GetTestMongo()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ConfigureAndStartGoWorkers()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Compare Clan Versions", func() {
+		It("Should return the missing, extra and stale clans sorted", func() {
+			expected := map[string]int64{"b": 1, "a": 1, "c": 2, "d": 3}
+			actual := map[string]int64{"c": 2, "d": 4, "e": 1}
+			drift := CompareClanVersions("game", IndexStoreMongo, expected, actual)
+			Expect(drift.Empty()).To(BeFalse())
+			Expect(drift.Serialize()).To(Equal(map[string]interface{}{
+				"gameID":  "game",
+				"store":   IndexStoreMongo,
+				"missing": []string{"a", "b"},
+				"extra":   []string{"e"},
+				"stale":   []string{"d"},
+			}))
+		})
+
+		It("Should return an empty drift if the versions match", func() {
+			versions := map[string]int64{"a": 1}
+			Expect(CompareClanVersions("game", IndexStoreMongo, versions, versions).Empty()).To(BeTrue())
+		})
+	})
+
+	Describe("Clan Versions", func() {
+		var gameID string
+		var clans []*Clan
+
+		BeforeEach(func() {
+			var err error
+			gameID = uuid.NewV4().String()
+			_, clans, err = GetTestClans(testDb, gameID, "", 3)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should return the updatedAt of the clans in postgres", func() {
+			versions, err := GetClanVersions(testDb, gameID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(3))
+			for _, clan := range clans {
+				Expect(versions[clan.PublicID]).To(Equal(clan.UpdatedAt))
+			}
+		})
+
+		It("Should return the updatedAt of the clans in mongo", func() {
+			collection := fmt.Sprintf("clans_%s", gameID)
+			err := BulkUpsertClansIntoMongoDB(testMongo, collection, []Clan{*clans[0], *clans[1]})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := GetMongoDBClanVersions(testMongo, collection)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal(map[string]int64{
+				clans[0].PublicID: clans[0].UpdatedAt,
+				clans[1].PublicID: clans[1].UpdatedAt,
+			}))
+		})
+
+		It("Should enqueue jobs that repair the drifted clans", func() {
+			extraID := uuid.NewV4().String()
+			drift := &IndexDrift{
+				GameID:  gameID,
+				Store:   IndexStoreMongo,
+				Missing: []string{clans[0].PublicID},
+				Extra:   []string{extraID},
+				Stale:   []string{clans[1].PublicID},
+			}
+			enqueued, err := RepairIndexDrift(testDb, nil, drift)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(enqueued).To(Equal(3))
+
+			var messages []*OutboxMessage
+			_, err = testDb.Select(&messages, "SELECT * FROM outbox WHERE queue=$1 AND args->>'game'=$2", queues.KhanMongoQueue, gameID)
+			Expect(err).NotTo(HaveOccurred())
+			ops := map[string]string{}
+			for _, message := range messages {
+				ops[message.Args["clanID"].(string)] = message.Args["op"].(string)
+			}
+			Expect(ops).To(HaveKeyWithValue(clans[0].PublicID, "update"))
+			Expect(ops).To(HaveKeyWithValue(clans[1].PublicID, "update"))
+			Expect(ops).To(HaveKeyWithValue(extraID, "delete"))
+		})
+
+		It("Should repair drifts with more clans than a batch", func() {
+			missing := []string{}
+			for i := 0; i < 1500; i++ {
+				missing = append(missing, uuid.NewV4().String())
+			}
+			missing = append(missing, clans[0].PublicID, clans[1].PublicID)
+			drift := &IndexDrift{GameID: gameID, Store: IndexStoreMongo, Missing: missing}
+			enqueued, err := RepairIndexDrift(testDb, nil, drift)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(enqueued).To(Equal(2))
+		})
+	})
+})
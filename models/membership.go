@@ -36,6 +36,8 @@ type Membership struct {
 	DeletedAt   int64         `db:"deleted_at"`
 	ApprovedAt  int64         `db:"approved_at"`
 	DeniedAt    int64         `db:"denied_at"`
+	BannedAt    int64         `db:"banned_at"`
+	BannedBy    int64         `db:"banned_by"`
 	Message     string        `db:"message"`
 }
 
@@ -156,13 +158,15 @@ func GetOldestMemberWithHighestLevel(db DB, gameID, clanPublicID string) (*Membe
 	return memberships[0], nil
 }
 
+// clanReachedMaxMemberships locks the clan until the end of the transaction and checks its
+// membership count, so concurrent memberships of the clan can't exceed the game MaxMembers
 func clanReachedMaxMemberships(db DB, game *Game, clan *Clan, clanID int64) error {
-	var err error
-	if clan == nil {
-		clan, err = GetClanByID(db, clanID)
-		if err != nil {
-			return err
-		}
+	if clan != nil {
+		clanID = clan.ID
+	}
+	clan, err := getClanByIDForUpdate(db, clanID)
+	if err != nil {
+		return err
 	}
 	if clan.MembershipCount >= game.MaxMembers {
 		return &ClanReachedMaxMembersError{clan.PublicID}
@@ -188,8 +192,14 @@ func playerReachedMaxInvites(db DB, game *Game, playerID int64) error {
 	return nil
 }
 
+// playerReachedMaxClans locks the player until the end of the transaction and checks its
+// membership and ownership counts, so concurrent memberships can't exceed the game MaxClansPerPlayer
 func playerReachedMaxClans(db DB, game *Game, player *Player) error {
 	playerID := player.ID
+	player, err := getPlayerByIDForUpdate(db, playerID)
+	if err != nil {
+		return err
+	}
 	if player.MembershipCount+player.OwnershipCount >= game.MaxClansPerPlayer {
 		err := UpdatePlayerMembershipCount(db, playerID)
 		if err != nil {
@@ -341,6 +351,9 @@ func validateMembership(db DB, game *Game, membership *Membership, clan *Clan, p
 	playerID := int64(-1)
 	previousMembership := false
 	if membership != nil {
+		if membership.BannedAt > 0 {
+			return -1, false, &PlayerIsBannedFromClanError{playerPublicID, clan.PublicID}
+		}
 		previousMembership = true
 		nowInMilliseconds := util.NowMilli()
 		applicationInOpenClan := requestorPublicID == playerPublicID && clan.AllowApplication && clan.AutoJoin
@@ -530,6 +543,91 @@ func DeleteMembership(db DB, game *Game, gameID, playerPublicID, clanPublicID, r
 	return nil, &PlayerCannotPerformMembershipActionError{"delete", playerPublicID, clanPublicID, requestorPublicID}
 }
 
+// BanMember soft deletes the membership and prevents the player from applying or being invited to the clan again
+func BanMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string) (*Membership, error) {
+	clan, err := GetClanByPublicID(db, gameID, clanPublicID)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := GetMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
+	if _, ok := err.(*ModelNotFoundError); ok {
+		// players that never had a membership in the clan can be banned as well
+		membership, err = newBannedMembership(db, game, clan, playerPublicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the owner has no membership of their own, so it would be created by the ban
+	if membership.PlayerID == clan.OwnerID {
+		return nil, &PlayerCannotPerformMembershipActionError{"ban", playerPublicID, clanPublicID, requestorPublicID}
+	}
+	if membership.BannedAt > 0 {
+		return nil, &PlayerIsBannedFromClanError{playerPublicID, clanPublicID}
+	}
+	bannedBy, err := getBanRequestorID(db, game, gameID, membership, playerPublicID, clanPublicID, requestorPublicID, "ban")
+	if err != nil {
+		return nil, err
+	}
+	if membership.ID == 0 {
+		return createBannedMembershipHelper(db, membership, bannedBy)
+	}
+	return banMembershipHelper(db, membership, bannedBy)
+}
+
+// newBannedMembership returns an unsaved membership, at the lowest level of the game,
+// for banning a player that has no membership in the clan
+func newBannedMembership(db DB, game *Game, clan *Clan, playerPublicID string) (*Membership, error) {
+	player, err := GetPlayerByPublicID(db, clan.GameID, playerPublicID)
+	if err != nil {
+		return nil, err
+	}
+	return &Membership{
+		GameID:   clan.GameID,
+		ClanID:   clan.ID,
+		PlayerID: player.ID,
+		Level:    GetLevelByLevelInt(game.MinMembershipLevel, game.MembershipLevels),
+	}, nil
+}
+
+// UnbanMember allows a banned player to apply or be invited to the clan again
+func UnbanMember(db DB, game *Game, gameID, playerPublicID, clanPublicID, requestorPublicID string) (*Membership, error) {
+	membership, err := GetMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, playerPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if membership.BannedAt == 0 {
+		return nil, &PlayerIsNotBannedFromClanError{playerPublicID, clanPublicID}
+	}
+	_, err = getBanRequestorID(db, game, gameID, membership, playerPublicID, clanPublicID, requestorPublicID, "unban")
+	if err != nil {
+		return nil, err
+	}
+	return unbanMembershipHelper(db, membership)
+}
+
+// getBanRequestorID returns the id of the requestor if they can ban or unban the player
+// The same levels required to remove a member are required to ban or unban them.
+func getBanRequestorID(db DB, game *Game, gameID string, membership *Membership, playerPublicID, clanPublicID, requestorPublicID, action string) (int64, error) {
+	if playerPublicID == requestorPublicID {
+		return -1, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clanPublicID, requestorPublicID}
+	}
+	reqMembership, _ := GetValidMembershipByClanAndPlayerPublicID(db, gameID, clanPublicID, requestorPublicID)
+	if reqMembership == nil {
+		clan, clanErr := GetClanByPublicIDAndOwnerPublicID(db, gameID, clanPublicID, requestorPublicID)
+		if clanErr != nil {
+			return -1, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clanPublicID, requestorPublicID}
+		}
+		return clan.OwnerID, nil
+	}
+
+	levelInt := GetLevelIntByLevel(membership.Level, game.MembershipLevels)
+	reqLevelInt := GetLevelIntByLevel(reqMembership.Level, game.MembershipLevels)
+	if isValidMember(reqMembership) && reqLevelInt >= game.MinLevelToRemoveMember && reqLevelInt >= levelInt+game.MinLevelOffsetToRemoveMember {
+		return reqMembership.PlayerID, nil
+	}
+	return -1, &PlayerCannotPerformMembershipActionError{action, playerPublicID, clanPublicID, requestorPublicID}
+}
+
 func isValidMember(membership *Membership) bool {
 	return membership.Approved && !membership.Denied
 }
@@ -675,6 +773,65 @@ func deleteMembershipHelper(db DB, membership *Membership, deletedBy int64) (*Me
 	return membership, err
 }
 
+func banMembershipHelper(db DB, membership *Membership, bannedBy int64) (*Membership, error) {
+	membershipWasApproved := membership.Approved
+	now := util.NowMilli()
+	if membership.DeletedAt == 0 {
+		membership.DeletedAt = now
+		membership.DeletedBy = bannedBy
+	}
+	membership.Approved = false
+	membership.Denied = false
+	membership.Banned = true
+	membership.BannedAt = now
+	membership.BannedBy = bannedBy
+
+	_, err := db.Update(membership)
+	if err != nil {
+		return nil, err
+	}
+
+	if membershipWasApproved {
+		err := UpdatePlayerMembershipCount(db, membership.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		err = UpdateClanMembershipCount(db, membership.ClanID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return membership, nil
+}
+
+func createBannedMembershipHelper(db DB, membership *Membership, bannedBy int64) (*Membership, error) {
+	now := util.NowMilli()
+	membership.RequestorID = bannedBy
+	membership.DeletedAt = now
+	membership.DeletedBy = bannedBy
+	membership.Banned = true
+	membership.BannedAt = now
+	membership.BannedBy = bannedBy
+
+	err := db.Insert(membership)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func unbanMembershipHelper(db DB, membership *Membership) (*Membership, error) {
+	membership.Banned = false
+	membership.BannedAt = 0
+	membership.BannedBy = 0
+
+	_, err := db.Update(membership)
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
 // GetLevelByLevelInt returns the level string given the level int
 func GetLevelByLevelInt(levelInt int, levels map[string]interface{}) string {
 	for k, v := range levels {
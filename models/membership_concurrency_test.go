@@ -0,0 +1,204 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Membership Concurrency", func() {
+	var testDb interfaces.Database
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetDB("localhost", "khan_test", 5433, "disable", "khan_test", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ConfigureAndStartGoWorkers()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	createGame := func(maxMembers, maxClansPerPlayer int) *Game {
+		game := GameFactory.MustCreateWithOption(map[string]interface{}{
+			"MaxMembers":        maxMembers,
+			"MaxClansPerPlayer": maxClansPerPlayer,
+		}).(*Game)
+		err := testDb.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+		return game
+	}
+
+	createPlayers := func(game *Game, count int) []*Player {
+		players := make([]*Player, count)
+		for i := range players {
+			players[i] = PlayerFactory.MustCreateWithOption(map[string]interface{}{
+				"GameID": game.PublicID,
+			}).(*Player)
+			err := testDb.Insert(players[i])
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return players
+	}
+
+	createClan := func(game *Game, autoJoin bool) (*Clan, *Player) {
+		owner := createPlayers(game, 1)[0]
+		owner.OwnershipCount = 1
+		_, err := testDb.Update(owner)
+		Expect(err).NotTo(HaveOccurred())
+
+		clan := ClanFactory.MustCreateWithOption(map[string]interface{}{
+			"GameID":           game.PublicID,
+			"OwnerID":          owner.ID,
+			"MembershipCount":  1,
+			"AllowApplication": true,
+			"AutoJoin":         autoJoin,
+		}).(*Clan)
+		err = testDb.Insert(clan)
+		Expect(err).NotTo(HaveOccurred())
+		return clan, owner
+	}
+
+	// runConcurrently runs count transactions at once, committing the ones whose f succeeds
+	runConcurrently := func(count int, f func(tx DB, i int) error) []error {
+		errs := make([]error, count)
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				tx, err := testDb.Begin()
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				if err = f(tx, i); err != nil {
+					tx.Rollback()
+					errs[i] = err
+					return
+				}
+				errs[i] = tx.Commit()
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+		return errs
+	}
+
+	countSucceeded := func(errs []error, expectedErr error) int {
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			Expect(err).To(BeAssignableToTypeOf(expectedErr))
+		}
+		return succeeded
+	}
+
+	countApprovedMemberships := func(column string, id int64) int {
+		count, err := testDb.SelectInt(
+			"SELECT COUNT(*) FROM memberships WHERE "+column+"=$1 AND approved=true AND deleted_at=0", id,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		return int(count)
+	}
+
+	It("Should not exceed the max members of an auto join clan", func() {
+		game := createGame(3, 1)
+		clan, _ := createClan(game, true)
+		players := createPlayers(game, 10)
+
+		errs := runConcurrently(len(players), func(tx DB, i int) error {
+			_, err := CreateMembership(
+				tx, game, game.PublicID, "Member", players[i].PublicID, clan.PublicID, players[i].PublicID, "",
+			)
+			return err
+		})
+
+		Expect(countSucceeded(errs, &ClanReachedMaxMembersError{})).To(Equal(2))
+		Expect(countApprovedMemberships("clan_id", clan.ID)).To(Equal(2))
+		dbClan, err := GetClanByID(testDb, clan.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dbClan.MembershipCount).To(Equal(3))
+	})
+
+	It("Should not exceed the max members when approving applications", func() {
+		game := createGame(2, 1)
+		clan, owner := createClan(game, false)
+		players := createPlayers(game, 5)
+		for _, player := range players {
+			_, err := CreateMembership(
+				testDb, game, game.PublicID, "Member", player.PublicID, clan.PublicID, player.PublicID, "",
+			)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		errs := runConcurrently(len(players), func(tx DB, i int) error {
+			_, err := ApproveOrDenyMembershipApplication(
+				tx, game, game.PublicID, players[i].PublicID, clan.PublicID, owner.PublicID, "approve",
+			)
+			return err
+		})
+
+		Expect(countSucceeded(errs, &ClanReachedMaxMembersError{})).To(Equal(1))
+		Expect(countApprovedMemberships("clan_id", clan.ID)).To(Equal(1))
+		dbClan, err := GetClanByID(testDb, clan.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dbClan.MembershipCount).To(Equal(2))
+	})
+
+	It("Should not exceed the max clans per player", func() {
+		game := createGame(100, 2)
+		player := createPlayers(game, 1)[0]
+		clans := make([]*Clan, 5)
+		for i := range clans {
+			clans[i], _ = createClan(game, true)
+		}
+
+		errs := runConcurrently(len(clans), func(tx DB, i int) error {
+			_, err := CreateMembership(
+				tx, game, game.PublicID, "Member", player.PublicID, clans[i].PublicID, player.PublicID, "",
+			)
+			return err
+		})
+
+		Expect(countSucceeded(errs, &PlayerReachedMaxClansError{})).To(Equal(2))
+		Expect(countApprovedMemberships("player_id", player.ID)).To(Equal(2))
+		dbPlayer, err := GetPlayerByID(testDb, player.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dbPlayer.MembershipCount).To(Equal(2))
+	})
+
+	It("Should not exceed the max clans per player when creating clans", func() {
+		game := createGame(100, 1)
+		player := createPlayers(game, 1)[0]
+
+		errs := runConcurrently(5, func(tx DB, i int) error {
+			_, err := CreateClan(
+				tx, game.PublicID, uuid.NewV4().String(), "clan", player.PublicID,
+				map[string]interface{}{}, true, false, game.MaxClansPerPlayer,
+			)
+			return err
+		})
+
+		Expect(countSucceeded(errs, &PlayerReachedMaxClansError{})).To(Equal(1))
+		dbPlayer, err := GetPlayerByID(testDb, player.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dbPlayer.OwnershipCount).To(Equal(1))
+	})
+})
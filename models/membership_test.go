@@ -2770,5 +2770,155 @@ var _ = Describe("Membership Model", func() {
 				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[1].PublicID, "delete", players[0].PublicID, clan.PublicID)))
 			})
 		})
+
+		Describe("Should ban a member with BanMember", func() {
+			It("If requestor is the owner", func() {
+				game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Banned).To(BeTrue())
+				Expect(dbMembership.BannedBy).To(Equal(owner.ID))
+				Expect(dbMembership.BannedAt).To(BeNumerically(">", util.NowMilli()-1000))
+				Expect(dbMembership.Approved).To(BeFalse())
+				Expect(dbMembership.DeletedBy).To(Equal(owner.ID))
+				Expect(dbMembership.DeletedAt).To(BeNumerically(">", util.NowMilli()-1000))
+
+				dbPlayer, err := GetPlayerByID(testDb, memberships[0].PlayerID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbPlayer.MembershipCount).To(Equal(0))
+
+				dbClan, err := GetClanByID(testDb, clan.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbClan.MembershipCount).To(Equal(1))
+			})
+
+			It("If requestor has enough level and offset", func() {
+				game, clan, _, players, memberships, err := GetClanWithMemberships(testDb, 2, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[1].Level = "CoLeader"
+				_, err = testDb.Update(memberships[1])
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, players[1].PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.BannedBy).To(Equal(players[1].ID))
+			})
+
+			It("And prevent new applications and invitations", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedError := &PlayerIsBannedFromClanError{players[0].PublicID, clan.PublicID}
+				_, err = CreateMembership(testDb, game, game.PublicID, "Member", players[0].PublicID, clan.PublicID, players[0].PublicID, "")
+				Expect(err).To(Equal(expectedError))
+				_, err = CreateMembership(testDb, game, game.PublicID, "Member", players[0].PublicID, clan.PublicID, owner.PublicID, "")
+				Expect(err).To(Equal(expectedError))
+			})
+
+			It("If the player never had a membership in the clan", func() {
+				game, clan, owner, _, _, err := GetClanWithMemberships(testDb, 0, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				player := PlayerFactory.MustCreateWithOption(map[string]interface{}{
+					"GameID": game.PublicID,
+				}).(*Player)
+				err = testDb.Insert(player)
+				Expect(err).NotTo(HaveOccurred())
+
+				membership, err := BanMember(testDb, game, clan.GameID, player.PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByClanAndPlayerPublicID(testDb, game.PublicID, clan.PublicID, player.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.ID).To(Equal(membership.ID))
+				Expect(dbMembership.Banned).To(BeTrue())
+				Expect(dbMembership.BannedBy).To(Equal(owner.ID))
+				Expect(dbMembership.Approved).To(BeFalse())
+				Expect(dbMembership.DeletedAt).To(BeNumerically(">", 0))
+
+				_, err = CreateMembership(testDb, game, game.PublicID, "Member", player.PublicID, clan.PublicID, player.PublicID, "")
+				Expect(err).To(Equal(&PlayerIsBannedFromClanError{player.PublicID, clan.PublicID}))
+			})
+		})
+
+		Describe("Should not ban a member with BanMember", func() {
+			It("If requestor does not have enough offset", func() {
+				game, clan, _, players, _, err := GetClanWithMemberships(testDb, 2, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, players[1].PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Player %s cannot %s membership for player %s and clan %s", players[1].PublicID, "ban", players[0].PublicID, clan.PublicID)))
+			})
+
+			It("If player is the clan owner", func() {
+				game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				memberships[0].Level = "CoLeader"
+				_, err = testDb.Update(memberships[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, owner.PublicID, clan.PublicID, players[0].PublicID)
+				Expect(err).To(Equal(&PlayerCannotPerformMembershipActionError{"ban", owner.PublicID, clan.PublicID, players[0].PublicID}))
+
+				_, err = GetMembershipByClanAndPlayerPublicID(testDb, game.PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("Membership was not found with id: %s", owner.PublicID)))
+			})
+
+			It("If player is already banned", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).To(Equal(&PlayerIsBannedFromClanError{players[0].PublicID, clan.PublicID}))
+			})
+		})
+
+		Describe("Should unban a member with UnbanMember", func() {
+			It("And allow new invitations", func() {
+				game, clan, owner, players, memberships, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = BanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UnbanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).NotTo(HaveOccurred())
+
+				dbMembership, err := GetMembershipByID(testDb, memberships[0].ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbMembership.Banned).To(BeFalse())
+				Expect(dbMembership.BannedAt).To(Equal(int64(0)))
+				Expect(dbMembership.DeletedAt).To(BeNumerically(">", 0))
+
+				_, err = CreateMembership(testDb, game, game.PublicID, "Member", players[0].PublicID, clan.PublicID, owner.PublicID, "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should fail if player is not banned", func() {
+				game, clan, owner, players, _, err := GetClanWithMemberships(testDb, 1, 0, 0, 0, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = UnbanMember(testDb, game, clan.GameID, players[0].PublicID, clan.PublicID, owner.PublicID)
+				Expect(err).To(Equal(&PlayerIsNotBannedFromClanError{players[0].PublicID, clan.PublicID}))
+			})
+		})
 	})
 })
@@ -0,0 +1,186 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	workers "github.com/jrallison/go-workers"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	"github.com/topfreegames/khan/util"
+	"github.com/uber-go/zap"
+)
+
+// OutboxMessage is a job for the workers queues stored with the model change that originated it
+// Since it is written in the same transaction as the change, it is published only if the change is committed.
+type OutboxMessage struct {
+	ID          int64                  `db:"id"`
+	Queue       string                 `db:"queue"`
+	Args        map[string]interface{} `db:"args"`
+	CreatedAt   int64                  `db:"created_at"`
+	PublishedAt int64                  `db:"published_at"`
+}
+
+// PreInsert populates fields before inserting a new outbox message
+func (m *OutboxMessage) PreInsert(s gorp.SqlExecutor) error {
+	m.CreatedAt = util.NowMilli()
+	return nil
+}
+
+// EnqueueOutbox stores a job to be published to the queue by the outbox relay
+func EnqueueOutbox(db DB, queue string, args map[string]interface{}) error {
+	// workers are not configured when khan is used as a library
+	if workers.Config == nil {
+		return nil
+	}
+	return db.Insert(&OutboxMessage{Queue: queue, Args: args})
+}
+
+// GetPendingOutboxMessages returns the oldest outbox messages not published yet
+// Messages locked by other relays are skipped.
+func GetPendingOutboxMessages(db DB, limit int) ([]*OutboxMessage, error) {
+	var messages []*OutboxMessage
+	_, err := db.Select(
+		&messages,
+		"SELECT * FROM outbox WHERE published_at=0 ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED",
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkOutboxMessagesPublished records the publication of the outbox messages
+func MarkOutboxMessagesPublished(db DB, messages []*OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	publishedAt := util.NowMilli()
+	params := []interface{}{publishedAt}
+	placeholders := []string{}
+	for i, message := range messages {
+		message.PublishedAt = publishedAt
+		params = append(params, message.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+2))
+	}
+
+	query := fmt.Sprintf("UPDATE outbox SET published_at=$1 WHERE id IN (%s)", strings.Join(placeholders, ","))
+	_, err := db.Exec(query, params...)
+	return err
+}
+
+// PruneOutbox removes the outbox messages published before the retention period
+func PruneOutbox(db DB, retention time.Duration) (int, error) {
+	publishedBefore := util.NowMilli() - int64(retention/time.Millisecond)
+	return runAndReturnRowsAffected(
+		"DELETE FROM outbox WHERE published_at > 0 AND published_at < $1",
+		db, publishedBefore,
+	)
+}
+
+// OutboxRelay publishes the outbox messages to the workers queues
+// Messages are published at least once: if the relay stops before recording a
+// publication, the message is published again.
+type OutboxRelay struct {
+	Logger       zap.Logger
+	DB           interfaces.Database
+	BatchSize    int
+	PollInterval time.Duration
+	Retention    time.Duration
+}
+
+// NewOutboxRelay creates and returns a new outbox relay
+func NewOutboxRelay(logger zap.Logger, db interfaces.Database, batchSize int, pollInterval, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		Logger:       logger,
+		DB:           db,
+		BatchSize:    batchSize,
+		PollInterval: pollInterval,
+		Retention:    retention,
+	}
+}
+
+// Run relays the pending outbox messages every PollInterval and prunes the published ones
+func (r *OutboxRelay) Run() {
+	l := r.Logger.With(
+		zap.String("source", "OutboxRelay"),
+		zap.String("operation", "Run"),
+	)
+
+	var prunedAt time.Time
+	for {
+		published, err := r.Relay()
+		if err != nil {
+			l.Error("Failed to relay outbox messages", zap.Error(err))
+		} else if published > 0 {
+			l.Debug("Outbox messages relayed", zap.Int("published", published))
+		}
+
+		if time.Since(prunedAt) > time.Minute {
+			pruned, err := PruneOutbox(r.DB, r.Retention)
+			if err != nil {
+				l.Error("Failed to prune outbox messages", zap.Error(err))
+			} else {
+				l.Debug("Outbox messages pruned", zap.Int("pruned", pruned))
+			}
+			prunedAt = time.Now()
+		}
+
+		time.Sleep(r.PollInterval)
+	}
+}
+
+// Relay publishes all the pending outbox messages and returns how many were published
+func (r *OutboxRelay) Relay() (int, error) {
+	total := 0
+	for {
+		published, err := r.publishBatch()
+		total += published
+		if err != nil || published < r.BatchSize {
+			return total, err
+		}
+	}
+}
+
+func (r *OutboxRelay) publishBatch() (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	messages, err := GetPendingOutboxMessages(tx, r.BatchSize)
+	if err != nil || len(messages) == 0 {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, message := range messages {
+		_, err = workers.Enqueue(message.Queue, "Add", message.Args)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	err = MarkOutboxMessagesPublished(tx, messages)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return len(messages), nil
+}
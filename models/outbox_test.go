@@ -0,0 +1,143 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/extensions/gorp/interfaces"
+	kt "github.com/topfreegames/khan/testing"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Outbox Model", func() {
+	var testDb interfaces.Database
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetDB("localhost", "khan_test", 5433, "disable", "khan_test", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ConfigureAndStartGoWorkers()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	getOutboxMessages := func(queue string) []*OutboxMessage {
+		var messages []*OutboxMessage
+		_, err := testDb.Select(&messages, "SELECT * FROM outbox WHERE queue=$1 ORDER BY id", queue)
+		Expect(err).NotTo(HaveOccurred())
+		return messages
+	}
+
+	getQueueLength := func(queue string) int {
+		conn := workers.Config.Pool.Get()
+		defer conn.Close()
+		length, err := redis.Int(conn.Do("LLEN", workers.Config.Namespace+"queue:"+queue))
+		Expect(err).NotTo(HaveOccurred())
+		return length
+	}
+
+	Describe("Enqueue Outbox", func() {
+		It("Should store the job with the transaction", func() {
+			queue := uuid.NewV4().String()
+			tx, err := testDb.Begin()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = EnqueueOutbox(tx, queue, map[string]interface{}{"gameID": "game", "count": 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getOutboxMessages(queue)).To(BeEmpty())
+
+			err = tx.Commit()
+			Expect(err).NotTo(HaveOccurred())
+
+			messages := getOutboxMessages(queue)
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].Args["gameID"]).To(Equal("game"))
+			Expect(messages[0].Args["count"]).To(BeEquivalentTo(1))
+			Expect(messages[0].CreatedAt).To(BeNumerically(">", 0))
+		})
+
+		It("Should not store the job if the transaction is rolled back", func() {
+			queue := uuid.NewV4().String()
+			tx, err := testDb.Begin()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = EnqueueOutbox(tx, queue, map[string]interface{}{"gameID": "game"})
+			Expect(err).NotTo(HaveOccurred())
+			err = tx.Rollback()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(getOutboxMessages(queue)).To(BeEmpty())
+			Consistently(func() int {
+				return getQueueLength(queue)
+			}, 50*time.Millisecond).Should(Equal(0))
+		})
+	})
+
+	Describe("Outbox Relay", func() {
+		It("Should publish the pending jobs once", func() {
+			queue := uuid.NewV4().String()
+			for i := 0; i < 3; i++ {
+				err := EnqueueOutbox(testDb, queue, map[string]interface{}{"index": i})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			relay := NewOutboxRelay(kt.NewMockLogger(), testDb, 2, time.Millisecond, time.Hour)
+			_, err := relay.Relay()
+			Expect(err).NotTo(HaveOccurred())
+
+			// the relay started by the workers may publish some of the jobs concurrently
+			Eventually(func() int {
+				published := 0
+				for _, message := range getOutboxMessages(queue) {
+					if message.PublishedAt > 0 {
+						published++
+					}
+				}
+				return published
+			}).Should(Equal(3))
+			Expect(getQueueLength(queue)).To(Equal(3))
+
+			_, err = relay.Relay()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getQueueLength(queue)).To(Equal(3))
+		})
+	})
+
+	Describe("Prune Outbox", func() {
+		It("Should remove the jobs published before the retention period", func() {
+			queue := uuid.NewV4().String()
+			old := &OutboxMessage{Queue: queue, Args: map[string]interface{}{}}
+			err := testDb.Insert(old)
+			Expect(err).NotTo(HaveOccurred())
+			old.PublishedAt = time.Now().Add(-2*time.Hour).UnixNano() / int64(time.Millisecond)
+			_, err = testDb.Update(old)
+			Expect(err).NotTo(HaveOccurred())
+
+			recent := &OutboxMessage{Queue: queue, Args: map[string]interface{}{}}
+			err = testDb.Insert(recent)
+			Expect(err).NotTo(HaveOccurred())
+			err = MarkOutboxMessagesPublished(testDb, []*OutboxMessage{recent})
+			Expect(err).NotTo(HaveOccurred())
+
+			pruned, err := PruneOutbox(testDb, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(BeNumerically(">=", 1))
+
+			messages := getOutboxMessages(queue)
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal(recent.ID))
+		})
+	})
+})
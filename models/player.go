@@ -27,6 +27,7 @@ type Player struct {
 	OwnershipCount  int                    `db:"ownership_count"`
 	CreatedAt       int64                  `db:"created_at"`
 	UpdatedAt       int64                  `db:"updated_at"`
+	Version         int64                  `db:"version"`
 }
 
 // PreInsert populates fields before inserting a new player
@@ -89,7 +90,7 @@ func UpdatePlayerOwnershipCount(db DB, id int64) error {
 	FROM (
 		SELECT COUNT(*) as count
 		FROM clans c
-		WHERE c.owner_id = $1
+		WHERE c.owner_id = $1 AND c.deleted_at = 0
 	) as ownership
 	WHERE players.id=$1
 	`
@@ -122,6 +123,20 @@ func GetPlayerByID(db DB, id int64) (*Player, error) {
 	return player, nil
 }
 
+// getPlayerByIDForUpdate returns a player by id, locking it until the end of the transaction
+// Concurrent transactions that lock the player wait for this one, so they read its up to date counts.
+func getPlayerByIDForUpdate(db DB, id int64) (*Player, error) {
+	var players []*Player
+	_, err := db.Select(&players, "SELECT * FROM players WHERE id=$1 FOR UPDATE", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(players) == 0 {
+		return nil, &ModelNotFoundError{"Player", id}
+	}
+	return players[0], nil
+}
+
 // GetPlayerByPublicID returns a player by their public id
 func GetPlayerByPublicID(db DB, gameID string, publicID string) (*Player, error) {
 	var players []*Player
@@ -146,7 +161,7 @@ func CreatePlayer(db DB, gameID, publicID, name string, metadata map[string]inte
 			INSERT INTO players(game_id, public_id, name, metadata, created_at, updated_at)
 						VALUES($1, $2, $3, $4, $5, $5)%s RETURNING id`
 	onConflict := ` ON CONFLICT (game_id, public_id)
-			DO UPDATE set name=$3, metadata=$4, updated_at=$5
+			DO UPDATE set name=$3, metadata=$4, updated_at=$5, version=players.version+1
 			WHERE players.game_id=$1 and players.public_id=$2`
 
 	if upsert {
@@ -164,6 +179,25 @@ func CreatePlayer(db DB, gameID, publicID, name string, metadata map[string]inte
 	return GetPlayerByID(db, lastID)
 }
 
+// CheckPlayerVersion locks the player and returns a VersionMismatchError if it is not at the given version
+func CheckPlayerVersion(db DB, gameID, publicID string, version int64) error {
+	current, err := db.SelectInt(
+		"SELECT version FROM players WHERE game_id=$1 AND public_id=$2 FOR UPDATE",
+		gameID, publicID,
+	)
+	if err != nil {
+		return err
+	}
+	// versions start at 1, so SelectInt only returns 0 when the player does not exist
+	if current == 0 {
+		return &ModelNotFoundError{"Player", publicID}
+	}
+	if current != version {
+		return &VersionMismatchError{"Player", publicID, version}
+	}
+	return nil
+}
+
 // UpdatePlayer updates an existing player
 func UpdatePlayer(db DB, gameID, publicID, name string, metadata map[string]interface{}) (*Player, error) {
 	return CreatePlayer(db, gameID, publicID, name, metadata, true)
@@ -174,7 +208,7 @@ func GetPlayerOwnershipDetails(db DB, gameID, publicID string) (map[string]inter
 	query := `
 	SELECT c.*
 	FROM players p
-	INNER JOIN clans c ON c.owner_id=p.id
+	INNER JOIN clans c ON c.owner_id=p.id AND c.deleted_at=0
 	WHERE p.game_id=$1 AND p.public_id=$2 
 	`
 
@@ -286,6 +320,7 @@ func GetPlayerMembershipDetails(db DB, gameID, publicID string) (map[string]inte
 	result["publicID"] = details[0].PlayerPublicID
 	result["createdAt"] = details[0].PlayerCreatedAt
 	result["updatedAt"] = details[0].PlayerUpdatedAt
+	result["version"] = player.Version
 
 	if details[0].MembershipLevel.Valid {
 		// Player has memberships
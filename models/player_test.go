@@ -139,6 +139,21 @@ var _ = Describe("Player Model", func() {
 				Expect(dbPlayer.Metadata["x"]).To(BeEquivalentTo(metadata["x"]))
 			})
 
+			It("Should increment the Player version with UpdatePlayer", func() {
+				_, player, err := CreatePlayerFactory(testDb, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(player.Version).To(BeEquivalentTo(1))
+
+				updPlayer, err := UpdatePlayer(testDb, player.GameID, player.PublicID, player.Name, map[string]interface{}{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updPlayer.Version).To(BeEquivalentTo(2))
+
+				err = CheckPlayerVersion(testDb, player.GameID, player.PublicID, 2)
+				Expect(err).NotTo(HaveOccurred())
+				err = CheckPlayerVersion(testDb, player.GameID, player.PublicID, 1)
+				Expect(err).To(BeAssignableToTypeOf(&VersionMismatchError{}))
+			})
+
 			It("Should create Player with UpdatePlayer if player does not exist", func() {
 				game := GameFactory.MustCreate().(*Game)
 				err := testDb.Insert(game)
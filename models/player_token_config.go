@@ -0,0 +1,196 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/go-gorp/gorp"
+	"github.com/topfreegames/khan/util"
+)
+
+// player token signing algorithms
+const (
+	PlayerTokenAlgorithmHS256 = "HS256"
+	PlayerTokenAlgorithmRS256 = "RS256"
+)
+
+// minPlayerTokenSecretLength is the minimum length of HS256 secrets, as many bytes as the hash
+const minPlayerTokenSecretLength = 32
+
+// PlayerTokenConfig configures how game clients can call Khan with player tokens
+// Tokens are JWTs signed with the game key, and their subject is the public id of the player.
+type PlayerTokenConfig struct {
+	ID            int64  `db:"id"`
+	GameID        string `db:"game_id"`
+	Algorithm     string `db:"algorithm"`
+	Key           string `db:"key"`
+	Issuer        string `db:"issuer"`
+	Audience      string `db:"audience"`
+	AllowedRoutes string `db:"allowed_routes"`
+	CreatedAt     int64  `db:"created_at"`
+	UpdatedAt     int64  `db:"updated_at"`
+}
+
+// PreInsert populates fields before inserting a new player token config
+func (c *PlayerTokenConfig) PreInsert(s gorp.SqlExecutor) error {
+	c.CreatedAt = util.NowMilli()
+	c.UpdatedAt = c.CreatedAt
+	return nil
+}
+
+// PreUpdate populates fields before updating a player token config
+func (c *PlayerTokenConfig) PreUpdate(s gorp.SqlExecutor) error {
+	c.UpdatedAt = util.NowMilli()
+	return nil
+}
+
+// Serialize returns a JSON with player token config details
+// HS256 secrets are never returned, RS256 keys are public.
+func (c *PlayerTokenConfig) Serialize() map[string]interface{} {
+	result := map[string]interface{}{
+		"gameID":        c.GameID,
+		"algorithm":     c.Algorithm,
+		"issuer":        c.Issuer,
+		"audience":      c.Audience,
+		"allowedRoutes": c.GetAllowedRoutes(),
+		"createdAt":     c.CreatedAt,
+		"updatedAt":     c.UpdatedAt,
+	}
+	if c.Algorithm == PlayerTokenAlgorithmRS256 {
+		result["key"] = c.Key
+	}
+	return result
+}
+
+// GetAllowedRoutes returns the routes that player tokens can use, as "METHOD /path"
+func (c *PlayerTokenConfig) GetAllowedRoutes() []string {
+	routes := []string{}
+	for _, route := range strings.Split(c.AllowedRoutes, ",") {
+		if route = strings.TrimSpace(route); route != "" {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// AllowsRoute returns whether player tokens can use the route, given as "METHOD /path"
+func (c *PlayerTokenConfig) AllowsRoute(route string) bool {
+	for _, allowed := range c.GetAllowedRoutes() {
+		if allowed == route {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *PlayerTokenConfig) verificationKey() (interface{}, error) {
+	switch c.Algorithm {
+	case PlayerTokenAlgorithmHS256:
+		if len(c.Key) < minPlayerTokenSecretLength {
+			return nil, &InvalidPlayerTokenConfigError{
+				fmt.Sprintf("HS256 key should have at least %d characters", minPlayerTokenSecretLength),
+			}
+		}
+		return []byte(c.Key), nil
+	case PlayerTokenAlgorithmRS256:
+		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(c.Key))
+		if err != nil {
+			return nil, &InvalidPlayerTokenConfigError{"RS256 key should be a PEM encoded RSA public key"}
+		}
+		return key, nil
+	}
+	return nil, &InvalidPlayerTokenConfigError{
+		fmt.Sprintf("algorithm should be %s or %s", PlayerTokenAlgorithmHS256, PlayerTokenAlgorithmRS256),
+	}
+}
+
+// ParseToken verifies the player token, returning the public id of its player
+// Only the configured algorithm is accepted, tokens must expire and their issuer and audience
+// must match the configured ones, if any.
+func (c *PlayerTokenConfig) ParseToken(token string) (string, error) {
+	key, err := c.verificationKey()
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{ValidMethods: []string{c.Algorithm}}
+	_, err = parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	})
+	if err != nil {
+		return "", &InvalidPlayerTokenError{err.Error()}
+	}
+	if _, ok := claims["exp"]; !ok {
+		return "", &InvalidPlayerTokenError{"token has no expiration"}
+	}
+	if c.Issuer != "" && !claims.VerifyIssuer(c.Issuer, true) {
+		return "", &InvalidPlayerTokenError{"token issuer is invalid"}
+	}
+	if c.Audience != "" && !claims.VerifyAudience(c.Audience, true) {
+		return "", &InvalidPlayerTokenError{"token audience is invalid"}
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return "", &InvalidPlayerTokenError{"token has no subject"}
+	}
+	return subject, nil
+}
+
+// GetPlayerTokenConfigByGameID returns the player token config of the game
+func GetPlayerTokenConfigByGameID(db DB, gameID string) (*PlayerTokenConfig, error) {
+	var config PlayerTokenConfig
+	err := db.SelectOne(&config, "SELECT * FROM player_token_configs WHERE game_id=$1", gameID)
+	if err != nil {
+		return nil, &ModelNotFoundError{"PlayerTokenConfig", gameID}
+	}
+	return &config, nil
+}
+
+// SetPlayerTokenConfig creates or replaces the player token config of the game
+func SetPlayerTokenConfig(
+	db DB, gameID, algorithm, key, issuer, audience string, allowedRoutes []string,
+) (*PlayerTokenConfig, error) {
+	config, err := GetPlayerTokenConfigByGameID(db, gameID)
+	exists := err == nil
+	if !exists {
+		config = &PlayerTokenConfig{GameID: gameID}
+	}
+	config.Algorithm = algorithm
+	config.Key = key
+	config.Issuer = issuer
+	config.Audience = audience
+	config.AllowedRoutes = strings.Join(allowedRoutes, ",")
+
+	if _, err = config.verificationKey(); err != nil {
+		return nil, err
+	}
+
+	if exists {
+		_, err = db.Update(config)
+	} else {
+		err = db.Insert(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// DeletePlayerTokenConfig deletes the player token config of the game, which stops accepting player tokens
+func DeletePlayerTokenConfig(db DB, gameID string) error {
+	config, err := GetPlayerTokenConfigByGameID(db, gameID)
+	if err != nil {
+		return err
+	}
+	_, err = db.Delete(config)
+	return err
+}
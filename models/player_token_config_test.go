@@ -0,0 +1,132 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Player Token Config Model", func() {
+	var testDb DB
+	var game *Game
+	secret := "0123456789abcdef0123456789abcdef"
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		game = GameFactory.MustCreate().(*Game)
+		err = testDb.Insert(game)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	sign := func(method jwt.SigningMethod, claims jwt.MapClaims, key interface{}) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		Expect(err).NotTo(HaveOccurred())
+		return token
+	}
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "player-id",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iss": "game-backend",
+			"aud": "khan",
+		}
+	}
+
+	Describe("Set Player Token Config", func() {
+		It("Should create and replace the player token config", func() {
+			_, err := SetPlayerTokenConfig(testDb, game.PublicID, PlayerTokenAlgorithmHS256, secret, "", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			config, err := SetPlayerTokenConfig(
+				testDb, game.PublicID, PlayerTokenAlgorithmHS256, secret, "game-backend", "",
+				[]string{"GET /games/:gameID/clans", "POST /games/:gameID/clans"},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			dbConfig, err := GetPlayerTokenConfigByGameID(testDb, game.PublicID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbConfig.ID).To(Equal(config.ID))
+			Expect(dbConfig.Issuer).To(Equal("game-backend"))
+			Expect(dbConfig.AllowsRoute("POST /games/:gameID/clans")).To(BeTrue())
+			Expect(dbConfig.AllowsRoute("PUT /games/:gameID/clans/:clanPublicID")).To(BeFalse())
+		})
+
+		It("Should not set invalid algorithms or keys", func() {
+			_, err := SetPlayerTokenConfig(testDb, game.PublicID, "none", secret, "", "", nil)
+			Expect(err).To(BeAssignableToTypeOf(&InvalidPlayerTokenConfigError{}))
+
+			_, err = SetPlayerTokenConfig(testDb, game.PublicID, PlayerTokenAlgorithmHS256, "short", "", "", nil)
+			Expect(err).To(BeAssignableToTypeOf(&InvalidPlayerTokenConfigError{}))
+
+			_, err = SetPlayerTokenConfig(testDb, game.PublicID, PlayerTokenAlgorithmRS256, secret, "", "", nil)
+			Expect(err).To(BeAssignableToTypeOf(&InvalidPlayerTokenConfigError{}))
+		})
+	})
+
+	Describe("Parse Token", func() {
+		It("Should return the subject of HS256 tokens", func() {
+			config := &PlayerTokenConfig{Algorithm: PlayerTokenAlgorithmHS256, Key: secret, Issuer: "game-backend", Audience: "khan"}
+
+			subject, err := config.ParseToken(sign(jwt.SigningMethodHS256, claims(), []byte(secret)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subject).To(Equal("player-id"))
+		})
+
+		It("Should return the subject of RS256 tokens", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+			config := &PlayerTokenConfig{
+				Algorithm: PlayerTokenAlgorithmRS256,
+				Key:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})),
+			}
+
+			subject, err := config.ParseToken(sign(jwt.SigningMethodRS256, claims(), privateKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subject).To(Equal("player-id"))
+
+			_, err = config.ParseToken(sign(jwt.SigningMethodHS256, claims(), []byte(config.Key)))
+			Expect(err).To(BeAssignableToTypeOf(&InvalidPlayerTokenError{}))
+		})
+
+		It("Should reject tokens with invalid claims", func() {
+			config := &PlayerTokenConfig{Algorithm: PlayerTokenAlgorithmHS256, Key: secret, Issuer: "game-backend", Audience: "khan"}
+
+			for claim, value := range map[string]interface{}{
+				"exp": time.Now().Add(-time.Minute).Unix(),
+				"iss": "another-backend",
+				"aud": "another-service",
+				"sub": "",
+			} {
+				invalid := claims()
+				invalid[claim] = value
+				_, err := config.ParseToken(sign(jwt.SigningMethodHS256, invalid, []byte(secret)))
+				Expect(err).To(BeAssignableToTypeOf(&InvalidPlayerTokenError{}), claim)
+			}
+
+			withoutExpiration := claims()
+			delete(withoutExpiration, "exp")
+			_, err := config.ParseToken(sign(jwt.SigningMethodHS256, withoutExpiration, []byte(secret)))
+			Expect(err).To(BeAssignableToTypeOf(&InvalidPlayerTokenError{}))
+		})
+	})
+})
@@ -82,6 +82,7 @@ func pruneDeniedMemberships(options *PruneOptions, db DB, logger zap.Logger) (in
 	query := `DELETE FROM memberships m WHERE
 		m.game_id=$1 AND
 		m.denied=TRUE AND
+		m.banned_at=0 AND
 		m.updated_at < $2`
 
 	updatedAt := util.NowMilli() - int64(options.DeniedMembershipsExpiration*1000)
@@ -92,6 +93,7 @@ func pruneDeletedMemberships(options *PruneOptions, db DB, logger zap.Logger) (i
 	query := `DELETE FROM memberships m WHERE
 		m.game_id=$1 AND
 		m.deleted_at > 0 AND
+		m.banned_at=0 AND
 		m.updated_at < $2`
 
 	updatedAt := util.NowMilli() - int64(options.DeletedMembershipsExpiration*1000)
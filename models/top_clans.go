@@ -0,0 +1,231 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/garyburd/redigo/redis"
+	workers "github.com/jrallison/go-workers"
+	"github.com/topfreegames/khan/queues"
+)
+
+// TopClansByMembershipCount ranks clans by their number of members
+const TopClansByMembershipCount = "membershipCount"
+
+// TopClansByCreatedAt ranks clans by creation date, newest first
+const TopClansByCreatedAt = "createdAt"
+
+// TopClansByMetadataPrefix prefixes the metadata keys clans can be ranked by
+const TopClansByMetadataPrefix = "metadata."
+
+// TopClan is a clan and its score in a top clans ranking
+type TopClan struct {
+	Clan  Clan
+	Score float64
+}
+
+// Serialize returns a JSON with the clan summary and its score
+func (t *TopClan) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		"publicID":         t.Clan.PublicID,
+		"name":             t.Clan.Name,
+		"membershipCount":  t.Clan.MembershipCount,
+		"metadata":         t.Clan.Metadata,
+		"allowApplication": t.Clan.AllowApplication,
+		"autoJoin":         t.Clan.AutoJoin,
+		"score":            t.Score,
+	}
+}
+
+// GetTopClansDimensions returns the dimensions the game clans are ranked by
+// Besides the membership count and the creation date, clans are ranked by the
+// numeric metadata keys declared in the game topClansMetadataDimensions.
+func (g *Game) GetTopClansDimensions() []string {
+	dimensions := []string{TopClansByMembershipCount, TopClansByCreatedAt}
+	for _, key := range strings.Split(g.TopClansMetadataDimensions, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			dimensions = append(dimensions, TopClansByMetadataPrefix+key)
+		}
+	}
+	return dimensions
+}
+
+// IsValidTopClansDimension returns whether the game clans are ranked by the given dimension
+func (g *Game) IsValidTopClansDimension(dimension string) bool {
+	for _, d := range g.GetTopClansDimensions() {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+func topClansKey(gameID, dimension string) string {
+	return fmt.Sprintf("%skhan:top-clans:%s:%s", workers.Config.Namespace, gameID, dimension)
+}
+
+func topClansNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// topClansScore returns the score of the clan in the dimension
+// Clans whose metadata does not hold a number for the dimension are not ranked.
+func topClansScore(clan *Clan, dimension string) (float64, bool) {
+	if clan == nil {
+		return 0, false
+	}
+	switch dimension {
+	case TopClansByMembershipCount:
+		return float64(clan.MembershipCount), true
+	case TopClansByCreatedAt:
+		return float64(clan.CreatedAt), true
+	}
+
+	if clan.Metadata == nil || !strings.HasPrefix(dimension, TopClansByMetadataPrefix) {
+		return 0, false
+	}
+	value := clan.Metadata[strings.TrimPrefix(dimension, TopClansByMetadataPrefix)]
+	if _, isString := value.(string); isString {
+		return 0, false
+	}
+	return topClansNumber(value)
+}
+
+// UpdateClanIntoTopClans after operation in PG
+func (c *Clan) UpdateClanIntoTopClans(db DB) error {
+	return c.enqueueTopClansUpdate(db, "update")
+}
+
+// DeleteClanFromTopClans after deletion in PG
+func (c *Clan) DeleteClanFromTopClans(db DB) error {
+	return c.enqueueTopClansUpdate(db, "delete")
+}
+
+// enqueueTopClansUpdate enqueues the job that ranks the clan again
+// The job does not carry the clan, the worker reads its latest state when ranking it.
+func (c *Clan) enqueueTopClansUpdate(db DB, op string) error {
+	return EnqueueOutbox(db, queues.KhanTopClansQueue, map[string]interface{}{
+		"game":   c.GameID,
+		"op":     op,
+		"clanID": c.PublicID,
+	})
+}
+
+// sendTopClansScores queues the commands that rank the clan in every dimension of the game
+// A nil clan is removed from all the rankings.
+func sendTopClansScores(conn redis.Conn, game *Game, clanID string, clan *Clan) {
+	for _, dimension := range game.GetTopClansDimensions() {
+		key := topClansKey(game.PublicID, dimension)
+		if score, ok := topClansScore(clan, dimension); ok {
+			conn.Send("ZADD", key, score, clanID)
+		} else {
+			conn.Send("ZREM", key, clanID)
+		}
+	}
+}
+
+// RebuildTopClans ranks all the clans of the game in every dimension, reading batchSize clans
+// at once, and returns the number of clans ranked
+// Use it to rank the clans created before the rankings existed or before a dimension was added.
+func RebuildTopClans(db DB, game *Game, batchSize int) (int, error) {
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	ranked := 0
+	var cursor *ClansCursor
+	for {
+		clans, next, err := GetClansPage(db, game.PublicID, cursor, batchSize)
+		if err != nil {
+			return ranked, err
+		}
+
+		conn.Send("MULTI")
+		for i := range clans {
+			sendTopClansScores(conn, game, clans[i].PublicID, &clans[i])
+		}
+		if _, err = conn.Do("EXEC"); err != nil {
+			return ranked, err
+		}
+		ranked += len(clans)
+
+		if next == nil {
+			return ranked, nil
+		}
+		cursor = next
+	}
+}
+
+// GetTopClans returns the clans with the highest scores in the given dimension
+func GetTopClans(db DB, gameID, dimension string, limit int) ([]*TopClan, error) {
+	topClans := []*TopClan{}
+	if limit <= 0 {
+		return topClans, nil
+	}
+
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	values, err := redis.Strings(conn.Do("ZREVRANGE", topClansKey(gameID, dimension), 0, limit-1, "WITHSCORES"))
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return topClans, nil
+	}
+
+	publicIDs := make([]string, 0, len(values)/2)
+	scores := map[string]float64{}
+	for i := 0; i+1 < len(values); i += 2 {
+		score, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		publicIDs = append(publicIDs, values[i])
+		scores[values[i]] = score
+	}
+
+	clans, err := GetClansByPublicIDs(db, gameID, publicIDs)
+	if err != nil {
+		// clans deleted after they were ranked are skipped
+		if _, ok := err.(*CouldNotFindAllClansError); !ok {
+			return nil, err
+		}
+	}
+
+	clansByPublicID := map[string]Clan{}
+	for _, clan := range clans {
+		clansByPublicID[clan.PublicID] = clan
+	}
+	for _, publicID := range publicIDs {
+		clan, ok := clansByPublicID[publicID]
+		if !ok {
+			continue
+		}
+		topClans = append(topClans, &TopClan{Clan: clan, Score: scores[publicID]})
+	}
+	return topClans, nil
+}
@@ -0,0 +1,179 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models_test
+
+import (
+	"encoding/json"
+
+	workers "github.com/jrallison/go-workers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	kt "github.com/topfreegames/khan/testing"
+
+	. "github.com/topfreegames/khan/models"
+)
+
+var _ = Describe("Top Clans Model", func() {
+	var testDb DB
+	var worker *TopClansWorker
+
+	BeforeEach(func() {
+		var err error
+		testDb, err = GetTestDB()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ConfigureAndStartGoWorkers()
+		Expect(err).NotTo(HaveOccurred())
+
+		worker = NewTopClansWorker(kt.NewMockLogger(), testDb)
+	})
+
+	performUpdate := func(op string, clan *Clan) {
+		job, err := json.Marshal(map[string]interface{}{
+			"jid": uuid.NewV4().String(),
+			"args": map[string]interface{}{
+				"game":   clan.GameID,
+				"op":     op,
+				"clanID": clan.PublicID,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		msg, err := workers.NewMsg(string(job))
+		Expect(err).NotTo(HaveOccurred())
+		worker.PerformUpdateTopClans(msg)
+	}
+
+	createRankedClans := func() (*Game, []*Clan) {
+		_, clans, err := GetTestClans(testDb, "", "", 3)
+		Expect(err).NotTo(HaveOccurred())
+
+		game, err := GetGameByPublicID(testDb, clans[0].GameID)
+		Expect(err).NotTo(HaveOccurred())
+		game.TopClansMetadataDimensions = "trophies, level"
+		_, err = testDb.Update(game)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i, clan := range clans {
+			clan.MembershipCount = i + 1
+			clan.Metadata = map[string]interface{}{"trophies": 100 - i*10}
+			_, err = testDb.Update(clan)
+			Expect(err).NotTo(HaveOccurred())
+			performUpdate("update", clan)
+		}
+		return game, clans
+	}
+
+	Describe("Get Top Clans Dimensions", func() {
+		It("Should return the default and the metadata dimensions of the game", func() {
+			game := &Game{TopClansMetadataDimensions: "trophies, level,"}
+			Expect(game.GetTopClansDimensions()).To(Equal([]string{
+				TopClansByMembershipCount, TopClansByCreatedAt, "metadata.trophies", "metadata.level",
+			}))
+			Expect(game.IsValidTopClansDimension("metadata.level")).To(BeTrue())
+			Expect(game.IsValidTopClansDimension("metadata.other")).To(BeFalse())
+		})
+	})
+
+	Describe("Get Top Clans", func() {
+		It("Should rank clans by membership count", func() {
+			game, clans := createRankedClans()
+
+			topClans, err := GetTopClans(testDb, game.PublicID, TopClansByMembershipCount, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(2))
+			Expect(topClans[0].Clan.PublicID).To(Equal(clans[2].PublicID))
+			Expect(topClans[0].Score).To(Equal(float64(3)))
+			Expect(topClans[1].Clan.PublicID).To(Equal(clans[1].PublicID))
+			Expect(topClans[1].Score).To(Equal(float64(2)))
+		})
+
+		It("Should rank clans by a metadata dimension", func() {
+			game, clans := createRankedClans()
+
+			topClans, err := GetTopClans(testDb, game.PublicID, "metadata.trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(3))
+			Expect(topClans[0].Clan.PublicID).To(Equal(clans[0].PublicID))
+			Expect(topClans[0].Score).To(Equal(float64(100)))
+			Expect(topClans[2].Clan.PublicID).To(Equal(clans[2].PublicID))
+
+			topClans, err = GetTopClans(testDb, game.PublicID, "metadata.level", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(BeEmpty())
+		})
+
+		It("Should remove deleted clans from the rankings", func() {
+			game, clans := createRankedClans()
+
+			clans[0].DeletedAt = clans[0].CreatedAt
+			_, err := testDb.Update(clans[0])
+			Expect(err).NotTo(HaveOccurred())
+			performUpdate("delete", clans[0])
+
+			topClans, err := GetTopClans(testDb, game.PublicID, "metadata.trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(2))
+			Expect(topClans[0].Clan.PublicID).To(Equal(clans[1].PublicID))
+		})
+
+		It("Should remove clans from a ranking when they lose the metadata key", func() {
+			game, clans := createRankedClans()
+
+			clans[0].Metadata = map[string]interface{}{}
+			_, err := testDb.Update(clans[0])
+			Expect(err).NotTo(HaveOccurred())
+			performUpdate("update", clans[0])
+
+			topClans, err := GetTopClans(testDb, game.PublicID, "metadata.trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(2))
+
+			topClans, err = GetTopClans(testDb, game.PublicID, TopClansByMembershipCount, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(3))
+		})
+
+		It("Should rank the latest state of the clan when jobs are processed out of order", func() {
+			game, clans := createRankedClans()
+
+			stale := *clans[0]
+			clans[0].Metadata = map[string]interface{}{"trophies": 50}
+			_, err := testDb.Update(clans[0])
+			Expect(err).NotTo(HaveOccurred())
+			performUpdate("update", &stale)
+
+			topClans, err := GetTopClans(testDb, game.PublicID, "metadata.trophies", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(3))
+			Expect(topClans[2].Clan.PublicID).To(Equal(clans[0].PublicID))
+			Expect(topClans[2].Score).To(Equal(float64(50)))
+		})
+	})
+
+	Describe("Rebuild Top Clans", func() {
+		It("Should rank the existing clans in a new dimension", func() {
+			game, clans := createRankedClans()
+
+			for i, clan := range clans {
+				clan.Metadata["level"] = i + 1
+				_, err := testDb.Update(clan)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			ranked, err := RebuildTopClans(testDb, game, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranked).To(Equal(3))
+
+			topClans, err := GetTopClans(testDb, game.PublicID, "metadata.level", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topClans).To(HaveLen(3))
+			Expect(topClans[0].Clan.PublicID).To(Equal(clans[2].PublicID))
+		})
+	})
+})
@@ -0,0 +1,80 @@
+// khan
+// https://github.com/topfreegames/khan
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/jrallison/go-workers"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/topfreegames/extensions/tracing"
+	"github.com/uber-go/zap"
+)
+
+// TopClansWorker is the worker that will keep the top clans rankings up to date
+type TopClansWorker struct {
+	Logger zap.Logger
+	DB     DB
+}
+
+// NewTopClansWorker creates and returns a new top clans worker
+func NewTopClansWorker(logger zap.Logger, db DB) *TopClansWorker {
+	return &TopClansWorker{
+		Logger: logger,
+		DB:     db,
+	}
+}
+
+// PerformUpdateTopClans updates the clan score in every top clans ranking of its game
+// The clan is read from the database, so jobs processed out of order still rank its latest state.
+func (w *TopClansWorker) PerformUpdateTopClans(m *workers.Msg) {
+	tags := opentracing.Tags{"component": "go-workers"}
+	span := opentracing.StartSpan("PerformUpdateTopClans", tags)
+	defer span.Finish()
+	defer tracing.LogPanic(span)
+
+	item := m.Args()
+	data := item.MustMap()
+	gameID := data["game"].(string)
+	op := data["op"].(string)
+	clanID := data["clanID"].(string)
+
+	l := w.Logger.With(
+		zap.String("game", gameID),
+		zap.String("operation", op),
+		zap.String("clanId", clanID),
+		zap.String("source", "PerformUpdateTopClans"),
+	)
+
+	game, err := GetGameByPublicID(w.DB, gameID)
+	if err != nil {
+		l.Error("Failed to retrieve the game of the top clans update", zap.Error(err))
+		return
+	}
+
+	// deleted clans are not found and are removed from the rankings
+	clan, err := GetClanByPublicID(w.DB, gameID, clanID)
+	if _, ok := err.(*ModelNotFoundError); ok {
+		clan, err = nil, nil
+	}
+	if err != nil {
+		l.Error("Failed to retrieve the clan of the top clans update", zap.Error(err))
+		panic(err)
+	}
+
+	conn := workers.Config.Pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	sendTopClansScores(conn, game, clanID, clan)
+	_, err = conn.Do("EXEC")
+	if err != nil {
+		panic(err)
+	}
+	l.Debug(fmt.Sprintf("updated clan %s in top clans", clanID))
+}
@@ -39,3 +39,52 @@ func GetClanNameRegularIndexCommand(gameID string, background bool) bson.D {
 		}},
 	}
 }
+
+// GetClanNamePrefixesIndexCommand returns a mongo command to create the clan name prefixes index used by autocomplete.
+func GetClanNamePrefixesIndexCommand(gameID string, background bool) bson.D {
+	return bson.D{
+		{Name: "createIndexes", Value: fmt.Sprintf("clans_%s", gameID)},
+		{Name: "indexes", Value: []interface{}{
+			bson.M{
+				"key": bson.D{
+					{Name: "namePrefixes", Value: 1},
+					{Name: "membershipCount", Value: -1},
+				},
+				"name":       fmt.Sprintf("clans_%s_namePrefixes_membershipCount_index", gameID),
+				"background": background,
+			},
+		}},
+	}
+}
+
+// GetClanSearchIndexesCommand returns a mongo command to create the indexes used by the clan search
+// filters and sort options, including one index for each of the game clan search metadata keys.
+func GetClanSearchIndexesCommand(gameID string, metadataKeys []string, background bool) bson.D {
+	index := func(name string, key bson.D) bson.M {
+		return bson.M{
+			"key":        key,
+			"name":       fmt.Sprintf("clans_%s_%s_index", gameID, name),
+			"background": background,
+		}
+	}
+	indexes := []interface{}{
+		index("membershipCount_id", bson.D{{Name: "membershipCount", Value: 1}, {Name: "id", Value: 1}}),
+		index("createdAt_id", bson.D{{Name: "createdAt", Value: 1}, {Name: "id", Value: 1}}),
+		index("name_id", bson.D{{Name: "name", Value: 1}, {Name: "id", Value: 1}}),
+		index("allowApplication_autoJoin_membershipCount", bson.D{
+			{Name: "allowApplication", Value: 1},
+			{Name: "autoJoin", Value: 1},
+			{Name: "membershipCount", Value: 1},
+		}),
+	}
+	for _, key := range metadataKeys {
+		indexes = append(indexes, index(
+			fmt.Sprintf("metadata_%s_id", key),
+			bson.D{{Name: fmt.Sprintf("metadata.%s", key), Value: 1}, {Name: "id", Value: 1}},
+		))
+	}
+	return bson.D{
+		{Name: "createIndexes", Value: fmt.Sprintf("clans_%s", gameID)},
+		{Name: "indexes", Value: indexes},
+	}
+}
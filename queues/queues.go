@@ -8,3 +8,6 @@ const KhanESQueue = "khan_es_updater"
 
 // KhanMongoQueue is the queue that will receive Mongo updates
 const KhanMongoQueue = "khan_mongo_updater"
+
+// KhanTopClansQueue is the queue that will receive top clans ranking updates
+const KhanTopClansQueue = "khan_top_clans_updater"
@@ -29,6 +29,24 @@ func CreateClanNameRegularIndexInMongo(getTestMongo func() (interfaces.MongoDB,
 	return db.Run(mongo.GetClanNameRegularIndexCommand(gameID, false), nil)
 }
 
+// CreateClanNamePrefixesIndexInMongo creates the index for clan name autocomplete in mongo
+func CreateClanNamePrefixesIndexInMongo(getTestMongo func() (interfaces.MongoDB, error), gameID string) error {
+	db, err := getTestMongo()
+	if err != nil {
+		return err
+	}
+	return db.Run(mongo.GetClanNamePrefixesIndexCommand(gameID, false), nil)
+}
+
+// CreateClanSearchIndexesInMongo creates the indexes for clan search filters and sort options in mongo
+func CreateClanSearchIndexesInMongo(getTestMongo func() (interfaces.MongoDB, error), gameID string, metadataKeys []string) error {
+	db, err := getTestMongo()
+	if err != nil {
+		return err
+	}
+	return db.Run(mongo.GetClanSearchIndexesCommand(gameID, metadataKeys, false), nil)
+}
+
 // GetTestDB returns a connection to the test database.
 func GetTestDB() (models.DB, error) {
 	return models.GetDB(